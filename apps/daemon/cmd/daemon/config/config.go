@@ -4,22 +4,135 @@
 package config
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/kelseyhightower/envconfig"
+	"gopkg.in/yaml.v3"
 
-	log "github.com/sirupsen/logrus"
+	common_proxy "github.com/daytonaio/common-go/pkg/proxy"
+	ssh_config "github.com/daytonaio/daemon/pkg/ssh/config"
+	toolbox_config "github.com/daytonaio/daemon/pkg/toolbox/config"
 )
 
+// Config is the daemon configuration. Values are read from the YAML config
+// file and then overridden by the environment variables named in the
+// envconfig tags.
 type Config struct {
-	ProjectDir  string
-	LogFilePath *string `envconfig:"DAYTONA_DAEMON_LOG_FILE_PATH"`
+	ProjectDir  string            `yaml:"projectDir" json:"projectDir" envconfig:"DAYTONA_PROJECT_DIR"`
+	Log         LogConfig         `yaml:"log" json:"log"`
+	Toolbox     ToolboxConfig     `yaml:"toolbox" json:"toolbox"`
+	Files       FilesConfig       `yaml:"files" json:"files"`
+	Proxy       ProxyConfig       `yaml:"proxy" json:"proxy"`
+	Terminal    TerminalConfig    `yaml:"terminal" json:"terminal"`
+	SSH         SSHConfig         `yaml:"ssh" json:"ssh"`
+	Recording   RecordingConfig   `yaml:"recording" json:"recording"`
+	LSP         LSPConfig         `yaml:"lsp" json:"lsp"`
+	ComputerUse ComputerUseConfig `yaml:"computerUse" json:"computerUse"`
+	// Path of the file the configuration was loaded from, empty if none
+	File string `yaml:"-" json:"file" ignored:"true"`
 }
 
+type LogConfig struct {
+	Level    string `yaml:"level" json:"level" envconfig:"LOG_LEVEL"`
+	Format   string `yaml:"format" json:"format" envconfig:"DAYTONA_DAEMON_LOG_FORMAT"`
+	FilePath string `yaml:"filePath" json:"filePath" envconfig:"DAYTONA_DAEMON_LOG_FILE_PATH"`
+	// Size in megabytes at which the log file is rotated
+	MaxSizeMB  int `yaml:"maxSizeMB" json:"maxSizeMB" envconfig:"DAYTONA_DAEMON_LOG_MAX_SIZE_MB"`
+	MaxAgeDays int `yaml:"maxAgeDays" json:"maxAgeDays" envconfig:"DAYTONA_DAEMON_LOG_MAX_AGE_DAYS"`
+	MaxBackups int `yaml:"maxBackups" json:"maxBackups" envconfig:"DAYTONA_DAEMON_LOG_MAX_BACKUPS"`
+}
+
+type ToolboxConfig struct {
+	Port  int    `yaml:"port" json:"port" envconfig:"DAYTONA_TOOLBOX_PORT" validate:"min=1,max=65535"`
+	Token string `yaml:"token" json:"token" envconfig:"DAYTONA_TOOLBOX_TOKEN"`
+	// Default timeout of commands run through /process/execute
+	ExecuteTimeout Duration `yaml:"executeTimeout" json:"executeTimeout" envconfig:"DAYTONA_EXECUTE_TIMEOUT"`
+	// Time given to in-flight requests and sessions to finish on shutdown
+	ShutdownTimeout Duration `yaml:"shutdownTimeout" json:"shutdownTimeout" envconfig:"DAYTONA_SHUTDOWN_TIMEOUT"`
+	// Port ranges reported by the port detector, all ports when empty
+	PortRanges string `yaml:"portRanges" json:"portRanges" envconfig:"DAYTONA_PORT_RANGES"`
+}
+
+type FilesConfig struct {
+	// Paths the file system API may access, all paths when empty
+	AllowedPaths []string `yaml:"allowedPaths" json:"allowedPaths" envconfig:"DAYTONA_FS_ALLOWED_PATHS"`
+	DeniedPaths  []string `yaml:"deniedPaths" json:"deniedPaths" envconfig:"DAYTONA_FS_DENIED_PATHS"`
+}
+
+// ProxyConfig configures the toolbox /proxy route to ports in the sandbox
+type ProxyConfig struct {
+	DialTimeout Duration `yaml:"dialTimeout" json:"dialTimeout" envconfig:"DAYTONA_PROXY_DIAL_TIMEOUT"`
+	// Time to wait for the response headers, no limit when zero
+	ResponseHeaderTimeout Duration `yaml:"responseHeaderTimeout" json:"responseHeaderTimeout" envconfig:"DAYTONA_PROXY_RESPONSE_HEADER_TIMEOUT"`
+	IdleConnTimeout       Duration `yaml:"idleConnTimeout" json:"idleConnTimeout" envconfig:"DAYTONA_PROXY_IDLE_CONN_TIMEOUT"`
+	// Flush interval of non-streaming responses
+	FlushInterval Duration `yaml:"flushInterval" json:"flushInterval" envconfig:"DAYTONA_PROXY_FLUSH_INTERVAL"`
+	// Proxy to upstreams over cleartext HTTP/2
+	H2C bool `yaml:"h2c" json:"h2c" envconfig:"DAYTONA_PROXY_H2C"`
+}
+
+// Config returns the settings in the form used by the proxy handler
+func (c ProxyConfig) Config() common_proxy.Config {
+	return common_proxy.Config{
+		DialTimeout:           c.DialTimeout.Duration(),
+		ResponseHeaderTimeout: c.ResponseHeaderTimeout.Duration(),
+		IdleConnTimeout:       c.IdleConnTimeout.Duration(),
+		FlushInterval:         c.FlushInterval.Duration(),
+		H2C:                   c.H2C,
+	}
+}
+
+type TerminalConfig struct {
+	Enabled        bool     `yaml:"enabled" json:"enabled" envconfig:"DAYTONA_TERMINAL_ENABLED"`
+	Port           int      `yaml:"port" json:"port" envconfig:"DAYTONA_TERMINAL_PORT" validate:"min=1,max=65535"`
+	Token          string   `yaml:"token" json:"token" envconfig:"DAYTONA_TERMINAL_TOKEN"`
+	AllowedOrigins []string `yaml:"allowedOrigins" json:"allowedOrigins" envconfig:"DAYTONA_TERMINAL_ALLOWED_ORIGINS"`
+}
+
+type SSHConfig struct {
+	Enabled            bool   `yaml:"enabled" json:"enabled" envconfig:"DAYTONA_SSH_ENABLED"`
+	Port               int    `yaml:"port" json:"port" envconfig:"DAYTONA_SSH_PORT" validate:"min=1,max=65535"`
+	AuthorizedKeysPath string `yaml:"authorizedKeysPath" json:"authorizedKeysPath" envconfig:"DAYTONA_SSH_AUTHORIZED_KEYS_PATH"`
+	AuthorizedKeys     string `yaml:"authorizedKeys" json:"authorizedKeys" envconfig:"DAYTONA_SSH_AUTHORIZED_KEYS"`
+	HostKeyPath        string `yaml:"hostKeyPath" json:"hostKeyPath" envconfig:"DAYTONA_SSH_HOST_KEY_PATH"`
+}
+
+type RecordingConfig struct {
+	Enabled bool `yaml:"enabled" json:"enabled" envconfig:"DAYTONA_SESSION_RECORDING"`
+	// Denied to the file system API while recording is enabled
+	Dir string `yaml:"dir" json:"dir" envconfig:"DAYTONA_RECORDINGS_DIR"`
+}
+
+type LSPConfig struct {
+	ConfigPath string `yaml:"configPath" json:"configPath" envconfig:"DAYTONA_LSP_CONFIG_PATH"`
+	// Time after which unused language servers are stopped, never when zero
+	IdleTTL *Duration `yaml:"idleTTL" json:"idleTTL" envconfig:"DAYTONA_LSP_IDLE_TTL"`
+}
+
+type ComputerUseConfig struct {
+	Enabled bool `yaml:"enabled" json:"enabled" envconfig:"DAYTONA_COMPUTER_USE_ENABLED"`
+}
+
+const CONFIG_FILE_ENV = "DAYTONA_DAEMON_CONFIG"
+
+var DEFAULT_CONFIG_FILE_PATH = "/etc/daytona/daemon.yaml"
+
 var DEFAULT_LOG_FILE_PATH = "/tmp/daytona-daemon.log"
 
+var DEFAULT_SHUTDOWN_TIMEOUT = 20 * time.Second
+
+var DEFAULT_TERMINAL_PORT = 22222
+
+const redacted = "[REDACTED]"
+
 var config *Config
 
 func GetConfig() (*Config, error) {
@@ -27,32 +140,127 @@ func GetConfig() (*Config, error) {
 		return config, nil
 	}
 
-	config = &Config{}
-
-	err := envconfig.Process("", config)
+	c, err := Load()
 	if err != nil {
-		log.Error(err)
-		os.Exit(2)
+		return nil, err
+	}
+
+	// Keep the toolbox token out of the environment of processes started
+	// through the toolbox
+	os.Unsetenv("DAYTONA_TOOLBOX_TOKEN")
+
+	config = c
+
+	return config, nil
+}
+
+// Load reads the config file named by DAYTONA_DAEMON_CONFIG, or the default
+// config file if it exists, and applies the environment overrides.
+func Load() (*Config, error) {
+	c := defaultConfig()
+
+	configFilePath, configFileSet := os.LookupEnv(CONFIG_FILE_ENV)
+	if !configFileSet {
+		configFilePath = DEFAULT_CONFIG_FILE_PATH
+	}
+	if configFilePath != "" {
+		if err := c.loadFile(configFilePath); err != nil {
+			// The default config file is optional
+			if configFileSet || !errors.Is(err, os.ErrNotExist) {
+				return nil, err
+			}
+		}
+	}
+
+	if err := envconfig.Process("", c); err != nil {
+		return nil, err
 	}
 
 	var validate = validator.New()
-	err = validate.Struct(config)
-	if err != nil {
+	if err := validate.Struct(c); err != nil {
 		return nil, err
 	}
 
-	config.LogFilePath = GetLogFilePath()
+	home := os.Getenv("HOME")
+	c.Log.FilePath = strings.Replace(c.Log.FilePath, "(HOME)", home, 1)
 
-	return config, nil
+	if c.ProjectDir == "" {
+		c.ProjectDir = home
+	}
+	if c.Recording.Dir == "" {
+		c.Recording.Dir = filepath.Join(home, ".daytona", "recordings")
+	}
+	if c.SSH.AuthorizedKeysPath == "" {
+		c.SSH.AuthorizedKeysPath = filepath.Join(home, ".ssh", "authorized_keys")
+	}
+	if c.SSH.HostKeyPath == "" {
+		c.SSH.HostKeyPath = filepath.Join(home, ".daytona", "ssh_host_ed25519_key")
+	}
+	if c.LSP.ConfigPath == "" {
+		c.LSP.ConfigPath = filepath.Join(home, ".daytona", "lsp.json")
+	}
+	if c.Toolbox.ExecuteTimeout <= 0 {
+		c.Toolbox.ExecuteTimeout = Duration(toolbox_config.DEFAULT_EXECUTE_TIMEOUT)
+	}
+	if c.Toolbox.ShutdownTimeout <= 0 {
+		c.Toolbox.ShutdownTimeout = Duration(DEFAULT_SHUTDOWN_TIMEOUT)
+	}
+
+	return c, nil
+}
+
+func defaultConfig() *Config {
+	return &Config{
+		Log: LogConfig{
+			Level:    "warn",
+			Format:   "text",
+			FilePath: DEFAULT_LOG_FILE_PATH,
+		},
+		Toolbox: ToolboxConfig{
+			Port: toolbox_config.TOOLBOX_API_PORT,
+		},
+		Proxy: ProxyConfig{
+			DialTimeout:     Duration(common_proxy.DefaultConfig.DialTimeout),
+			IdleConnTimeout: Duration(common_proxy.DefaultConfig.IdleConnTimeout),
+		},
+		Terminal: TerminalConfig{
+			Enabled: true,
+			Port:    DEFAULT_TERMINAL_PORT,
+		},
+		SSH: SSHConfig{
+			Port: ssh_config.SSH_PORT,
+		},
+		ComputerUse: ComputerUseConfig{
+			Enabled: true,
+		},
+	}
 }
 
-func GetLogFilePath() *string {
-	logFilePath, ok := os.LookupEnv("DAYTONA_DAEMON_LOG_FILE_PATH")
-	if !ok {
-		return &DEFAULT_LOG_FILE_PATH
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
 	}
 
-	logFilePath = strings.Replace(logFilePath, "(HOME)", os.Getenv("HOME"), 1)
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
 
-	return &logFilePath
+	c.File = path
+	return nil
+}
+
+// Redacted returns a copy of the configuration that is safe to expose, with
+// tokens replaced by a placeholder.
+func (c *Config) Redacted() *Config {
+	redactedConfig := *c
+	if redactedConfig.Toolbox.Token != "" {
+		redactedConfig.Toolbox.Token = redacted
+	}
+	if redactedConfig.Terminal.Token != "" {
+		redactedConfig.Terminal.Token = redacted
+	}
+	return &redactedConfig
 }
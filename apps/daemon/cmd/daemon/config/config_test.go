@@ -0,0 +1,80 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package config_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/daytonaio/daemon/cmd/daemon/config"
+	"github.com/stretchr/testify/require"
+)
+
+const configFile = `
+projectDir: /workspace
+log:
+  level: debug
+  format: json
+toolbox:
+  port: 3280
+  token: file-token
+  executeTimeout: 10m
+files:
+  deniedPaths: [/etc]
+proxy:
+  responseHeaderTimeout: 1m
+terminal:
+  enabled: false
+lsp:
+  idleTTL: 0s
+`
+
+func TestLoad_FileWithEnvOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daemon.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(configFile), 0644))
+
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(config.CONFIG_FILE_ENV, path)
+	t.Setenv("DAYTONA_TOOLBOX_PORT", "4280")
+	t.Setenv("DAYTONA_SHUTDOWN_TIMEOUT", "5s")
+	t.Setenv("DAYTONA_PROXY_H2C", "true")
+
+	c, err := config.Load()
+	require.NoError(t, err)
+
+	require.Equal(t, path, c.File)
+	require.Equal(t, "/workspace", c.ProjectDir)
+	require.Equal(t, "debug", c.Log.Level)
+	require.Equal(t, 4280, c.Toolbox.Port)
+	require.Equal(t, 10*time.Minute, c.Toolbox.ExecuteTimeout.Duration())
+	require.Equal(t, 5*time.Second, c.Toolbox.ShutdownTimeout.Duration())
+	require.Equal(t, []string{"/etc"}, c.Files.DeniedPaths)
+	require.Equal(t, time.Minute, c.Proxy.Config().ResponseHeaderTimeout)
+	require.Equal(t, 10*time.Second, c.Proxy.Config().DialTimeout)
+	require.True(t, c.Proxy.Config().H2C)
+	require.False(t, c.Terminal.Enabled)
+	require.Equal(t, 22222, c.Terminal.Port)
+	require.True(t, c.ComputerUse.Enabled)
+	require.NotNil(t, c.LSP.IdleTTL)
+	require.Zero(t, c.LSP.IdleTTL.Duration())
+
+	redacted, err := json.Marshal(c.Redacted())
+	require.NoError(t, err)
+	require.NotContains(t, string(redacted), "file-token")
+	require.Contains(t, string(redacted), `"executeTimeout":"10m0s"`)
+	require.Equal(t, "file-token", c.Toolbox.Token)
+}
+
+func TestLoad_RejectsUnknownFields(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "daemon.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("toolbox:\n  prot: 1\n"), 0644))
+
+	t.Setenv(config.CONFIG_FILE_ENV, path)
+
+	_, err := config.Load()
+	require.Error(t, err)
+}
@@ -9,23 +9,34 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+// LogFormatter formats entries for the console and mirrors them to the log
+// file, which gets its own formatter so that it stays free of color codes.
 type LogFormatter struct {
-	TextFormatter *log.TextFormatter
+	Formatter     log.Formatter
+	FileFormatter log.Formatter
 	LogFileWriter io.Writer
 }
 
 func (f *LogFormatter) Format(entry *log.Entry) ([]byte, error) {
-	formatted, err := f.TextFormatter.Format(entry)
+	formatted, err := f.Formatter.Format(entry)
 	if err != nil {
 		return nil, err
 	}
 
 	if f.LogFileWriter != nil {
-		_, err = f.LogFileWriter.Write(formatted)
+		fileFormatted := formatted
+		if f.FileFormatter != nil {
+			fileFormatted, err = f.FileFormatter.Format(entry)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		_, err = f.LogFileWriter.Write(fileFormatted)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	return []byte(formatted), nil
+	return formatted, nil
 }
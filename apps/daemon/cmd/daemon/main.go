@@ -4,18 +4,25 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
 	"os/signal"
-	"path/filepath"
+	"slices"
 	"syscall"
+	"time"
 
 	golog "log"
 
+	"github.com/daytonaio/common-go/pkg/logs"
 	"github.com/daytonaio/daemon/cmd/daemon/config"
+	"github.com/daytonaio/daemon/pkg/recording"
+	"github.com/daytonaio/daemon/pkg/ssh"
+	"github.com/daytonaio/daemon/pkg/telemetry"
 	"github.com/daytonaio/daemon/pkg/terminal"
 	"github.com/daytonaio/daemon/pkg/toolbox"
+	"github.com/daytonaio/daemon/pkg/toolbox/fs"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -24,11 +31,6 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
-	c.ProjectDir = filepath.Join(os.Getenv("HOME"))
-
-	if projectDir := os.Getenv("DAYTONA_PROJECT_DIR"); projectDir != "" {
-		c.ProjectDir = projectDir
-	}
 
 	if _, err := os.Stat(c.ProjectDir); os.IsNotExist(err) {
 		if err := os.MkdirAll(c.ProjectDir, 0755); err != nil {
@@ -36,23 +38,88 @@ func main() {
 		}
 	}
 
+	logFormat, logFormatErr := logs.ParseFormat(c.Log.Format)
+
 	var logWriter io.Writer
-	if c.LogFilePath != nil {
-		logFile, err := os.OpenFile(*c.LogFilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if c.Log.FilePath != "" {
+		logFile, err := logs.NewFileWriter(logs.FileConfig{
+			Path:       c.Log.FilePath,
+			MaxSizeMB:  c.Log.MaxSizeMB,
+			MaxAgeDays: c.Log.MaxAgeDays,
+			MaxBackups: c.Log.MaxBackups,
+		})
 		if err != nil {
-			log.Error("Failed to open log file at ", *c.LogFilePath)
+			log.Errorf("Failed to open log file at %s: %v", c.Log.FilePath, err)
 		} else {
 			defer logFile.Close()
 			logWriter = logFile
 		}
 	}
 
-	initLogs(logWriter)
+	initLogs(c.Log.Level, logFormat, logWriter)
+
+	if logFormatErr != nil {
+		log.Errorf("%v, falling back to %s", logFormatErr, logs.FormatText)
+	}
+	if c.File != "" {
+		log.Infof("Loaded configuration from %s", c.File)
+	}
+
+	shutdownTracing, err := telemetry.InitTracing(context.Background())
+	if err != nil {
+		log.Errorf("Failed to initialize tracing: %v", err)
+	}
+
+	var recordings *recording.Store
+	if c.Recording.Enabled {
+		recordings, err = recording.NewStore(c.Recording.Dir)
+		if err != nil {
+			log.Errorf("Failed to enable session recording: %v", err)
+		}
+	}
 
 	errChan := make(chan error)
 
+	var lspIdleTTL *time.Duration
+	if c.LSP.IdleTTL != nil {
+		ttl := c.LSP.IdleTTL.Duration()
+		lspIdleTTL = &ttl
+	}
+
+	// The daemon's own servers must not be reachable through the toolbox proxy,
+	// which only requires the proxy scope
+	var reservedPorts []int
+	if c.Terminal.Enabled {
+		reservedPorts = append(reservedPorts, c.Terminal.Port)
+	}
+	if c.SSH.Enabled {
+		reservedPorts = append(reservedPorts, c.SSH.Port)
+	}
+
+	// Recordings are only served through /recordings, the file system API must
+	// not be able to delete or rewrite them
+	deniedPaths := c.Files.DeniedPaths
+	if recordings != nil {
+		deniedPaths = append(slices.Clone(deniedPaths), recordings.Dir())
+	}
+
 	toolBoxServer := &toolbox.Server{
-		ProjectDir: c.ProjectDir,
+		ProjectDir:         c.ProjectDir,
+		Port:               c.Toolbox.Port,
+		Recordings:         recordings,
+		Token:              c.Toolbox.Token,
+		ExecuteTimeout:     c.Toolbox.ExecuteTimeout.Duration(),
+		PortRanges:         c.Toolbox.PortRanges,
+		LSPConfigPath:      c.LSP.ConfigPath,
+		LSPIdleTTL:         lspIdleTTL,
+		ComputerUseEnabled: c.ComputerUse.Enabled,
+		PathPolicy: fs.PathPolicy{
+			AllowedPaths: c.Files.AllowedPaths,
+			DeniedPaths:  deniedPaths,
+		},
+		Proxy:         c.Proxy.Config(),
+		ReservedPorts: reservedPorts,
+		Config:        c.Redacted(),
 	}
 
 	// Start the toolbox server in a go routine
@@ -63,12 +130,44 @@ func main() {
 		}
 	}()
 
-	// Start terminal server
-	go func() {
-		if err := terminal.StartTerminalServer(22222); err != nil {
-			errChan <- err
+	if c.Terminal.Enabled {
+		// Without a dedicated token the terminal accepts the toolbox token, it
+		// would otherwise give unauthenticated shell access
+		terminalToken := c.Terminal.Token
+		if terminalToken == "" {
+			terminalToken = c.Toolbox.Token
 		}
-	}()
+
+		go func() {
+			err := terminal.StartTerminalServer(terminal.ServerConfig{
+				Port:           c.Terminal.Port,
+				Token:          terminalToken,
+				AllowedOrigins: c.Terminal.AllowedOrigins,
+				Recordings:     recordings,
+			})
+			if err != nil {
+				errChan <- err
+			}
+		}()
+	}
+
+	if c.SSH.Enabled {
+		sshServer := &ssh.Server{
+			ProjectDir:         c.ProjectDir,
+			DefaultProjectDir:  os.Getenv("HOME"),
+			Port:               c.SSH.Port,
+			Recordings:         recordings,
+			AuthorizedKeysPath: c.SSH.AuthorizedKeysPath,
+			AuthorizedKeys:     c.SSH.AuthorizedKeys,
+			HostKeyPath:        c.SSH.HostKeyPath,
+		}
+
+		go func() {
+			if err := sshServer.Start(); err != nil {
+				errChan <- err
+			}
+		}()
+	}
 
 	// Set up signal handling for graceful shutdown
 	sigChan := make(chan os.Signal, 1)
@@ -83,35 +182,33 @@ func main() {
 	}
 
 	// Graceful shutdown
-	log.Info("Stopping computer use processes...")
-	if toolBoxServer.ComputerUse != nil {
-		_, err := toolBoxServer.ComputerUse.Stop()
-		if err != nil {
-			log.Errorf("Failed to stop computer use: %v", err)
+	ctx, cancel := context.WithTimeout(context.Background(), c.Toolbox.ShutdownTimeout.Duration())
+	if err := toolBoxServer.Shutdown(ctx); err != nil {
+		log.Errorf("Failed to shut down toolbox: %v", err)
+	}
+	cancel()
+
+	if shutdownTracing != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := shutdownTracing(ctx); err != nil {
+			log.Errorf("Failed to flush traces: %v", err)
 		}
+		cancel()
 	}
 
 	log.Info("Shutdown complete")
 }
 
-func initLogs(logWriter io.Writer) {
-	logLevel := log.WarnLevel
-
-	logLevelEnv, logLevelSet := os.LookupEnv("LOG_LEVEL")
-
-	if logLevelSet {
-		var err error
-		logLevel, err = log.ParseLevel(logLevelEnv)
-		if err != nil {
-			logLevel = log.WarnLevel
-		}
+func initLogs(level string, logFormat logs.Format, logWriter io.Writer) {
+	logLevel, err := log.ParseLevel(level)
+	if err != nil {
+		logLevel = log.WarnLevel
 	}
 
 	log.SetLevel(logLevel)
 	logFormatter := &config.LogFormatter{
-		TextFormatter: &log.TextFormatter{
-			ForceColors: true,
-		},
+		Formatter:     logs.ConsoleFormatter(logFormat, true),
+		FileFormatter: logs.FileFormatter(logFormat),
 		LogFileWriter: logWriter,
 	}
 
@@ -3,10 +3,8 @@
 
 package git
 
-import "github.com/go-git/go-git/v5"
-
 func (s *Service) Add(files []string) error {
-	repo, err := git.PlainOpen(s.ProjectDir)
+	repo, err := s.openRepository()
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,117 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package git
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func (s *Service) Blame(file, ref string) ([]GitBlameLine, error) {
+	repo, err := s.openRepository()
+	if err != nil {
+		return []GitBlameLine{}, err
+	}
+
+	commit, err := resolveCommit(repo, ref)
+	if err != nil {
+		return []GitBlameLine{}, err
+	}
+
+	relPath, err := s.repoRelativePath(repo, file)
+	if err != nil {
+		return []GitBlameLine{}, err
+	}
+
+	result, err := git.Blame(commit, relPath)
+	if err != nil {
+		return []GitBlameLine{}, fmt.Errorf("failed to blame '%s': %w", relPath, err)
+	}
+
+	lines := make([]GitBlameLine, 0, len(result.Lines))
+	for i, line := range result.Lines {
+		lines = append(lines, GitBlameLine{
+			Line:      i + 1,
+			Hash:      line.Hash.String(),
+			Author:    line.AuthorName,
+			Email:     line.Author,
+			Timestamp: line.Date,
+			Text:      line.Text,
+		})
+	}
+
+	return lines, nil
+}
+
+// GetFileAtRevision returns a reader for the content of file as of ref
+// together with its size. The caller is responsible for closing the reader.
+func (s *Service) GetFileAtRevision(file, ref string) (io.ReadCloser, int64, error) {
+	repo, err := s.openRepository()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	commit, err := resolveCommit(repo, ref)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	relPath, err := s.repoRelativePath(repo, file)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	f, err := commit.File(relPath)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read '%s' at '%s': %w", relPath, commit.Hash, err)
+	}
+
+	reader, err := f.Reader()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return reader, f.Size, nil
+}
+
+// resolveCommit resolves ref to a commit, defaulting to HEAD when ref is empty.
+func resolveCommit(repo *git.Repository, ref string) (*object.Commit, error) {
+	revision := "HEAD"
+	if ref != "" {
+		revision = ref
+	}
+
+	hash, err := repo.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve revision '%s': %w", revision, err)
+	}
+
+	return repo.CommitObject(*hash)
+}
+
+// repoRelativePath converts file into a slash separated path relative to the
+// repository root. Absolute paths must point inside the worktree.
+func (s *Service) repoRelativePath(repo *git.Repository, file string) (string, error) {
+	if !filepath.IsAbs(file) {
+		return filepath.ToSlash(filepath.Clean(file)), nil
+	}
+
+	w, err := repo.Worktree()
+	if err != nil {
+		return "", err
+	}
+
+	rel, err := filepath.Rel(w.Filesystem.Root(), file)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("file '%s' is outside of the repository", file)
+	}
+
+	return filepath.ToSlash(rel), nil
+}
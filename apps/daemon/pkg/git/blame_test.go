@@ -0,0 +1,65 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package git_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/daytonaio/daemon/pkg/git"
+	go_git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/require"
+)
+
+func commitFile(t *testing.T, dir, name, content, author string) {
+	repo, err := go_git.PlainOpen(dir)
+	require.NoError(t, err)
+
+	w, err := repo.Worktree()
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+
+	_, err = w.Add(name)
+	require.NoError(t, err)
+
+	_, err = w.Commit("update "+name, &go_git.CommitOptions{
+		Author: &object.Signature{Name: author, Email: author + "@example.com", When: time.Now()},
+	})
+	require.NoError(t, err)
+}
+
+func TestBlameAndFileAtRevision(t *testing.T) {
+	dir := t.TempDir()
+	_, err := go_git.PlainInit(dir, false)
+	require.NoError(t, err)
+
+	commitFile(t, dir, "file.txt", "first\nsecond\n", "alice")
+	commitFile(t, dir, "file.txt", "first\nchanged\n", "bob")
+
+	gitService := &git.Service{ProjectDir: dir}
+
+	lines, err := gitService.Blame("file.txt", "")
+	require.NoError(t, err)
+	require.Len(t, lines, 2)
+	require.Equal(t, "alice", lines[0].Author)
+	require.Equal(t, "bob", lines[1].Author)
+	require.Equal(t, "changed", lines[1].Text)
+
+	reader, size, err := gitService.GetFileAtRevision(filepath.Join(dir, "file.txt"), "HEAD~1")
+	require.NoError(t, err)
+	defer reader.Close()
+
+	content, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.Equal(t, "first\nsecond\n", string(content))
+	require.EqualValues(t, len(content), size)
+
+	_, _, err = gitService.GetFileAtRevision("/etc/passwd", "")
+	require.Error(t, err)
+}
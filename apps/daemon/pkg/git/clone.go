@@ -46,7 +46,7 @@ func (s *Service) CloneRepository(repo *gitprovider.GitRepository, auth *http.Ba
 	}
 
 	if repo.Target == gitprovider.CloneTargetCommit {
-		r, err := git.PlainOpen(s.ProjectDir)
+		r, err := s.openRepository()
 		if err != nil {
 			return err
 		}
@@ -5,8 +5,8 @@ package git
 
 import "github.com/go-git/go-git/v5"
 
-func (s *Service) Commit(message string, options *git.CommitOptions) (string, error) {
-	repo, err := git.PlainOpen(s.ProjectDir)
+func (s *Service) Commit(message string, options *git.CommitOptions, signing *SigningOptions) (string, error) {
+	repo, err := s.openRepository()
 	if err != nil {
 		return "", err
 	}
@@ -16,6 +16,14 @@ func (s *Service) Commit(message string, options *git.CommitOptions) (string, er
 		return "", err
 	}
 
+	if signing != nil {
+		signer, err := s.newSigner(repo, signing)
+		if err != nil {
+			return "", err
+		}
+		options.Signer = signer
+	}
+
 	commit, err := w.Commit(message, options)
 	if err != nil {
 		return "", err
@@ -0,0 +1,37 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package git
+
+import (
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+
+	"github.com/go-git/go-git/v5"
+)
+
+func (s *Service) Fetch(remote string, refSpecs []string, auth *http.BasicAuth) error {
+	repo, err := s.openRepository()
+	if err != nil {
+		return err
+	}
+
+	if remote == "" {
+		remote = git.DefaultRemoteName
+	}
+
+	options := &git.FetchOptions{
+		RemoteName: remote,
+		Auth:       auth,
+	}
+
+	for _, refSpec := range refSpecs {
+		rs := config.RefSpec(refSpec)
+		if err := rs.Validate(); err != nil {
+			return err
+		}
+		options.RefSpecs = append(options.RefSpecs, rs)
+	}
+
+	return repo.Fetch(options)
+}
@@ -10,7 +10,7 @@ import (
 )
 
 func (s *Service) Log() ([]GitCommitInfo, error) {
-	repo, err := git.PlainOpen(s.ProjectDir)
+	repo, err := s.openRepository()
 	if err != nil {
 		return []GitCommitInfo{}, err
 	}
@@ -25,6 +25,8 @@ func (s *Service) Log() ([]GitCommitInfo, error) {
 		return []GitCommitInfo{}, err
 	}
 
+	verifier := newSignatureVerifier()
+
 	var history []GitCommitInfo
 	err = commits.ForEach(func(commit *object.Commit) error {
 		history = append(history, GitCommitInfo{
@@ -33,6 +35,7 @@ func (s *Service) Log() ([]GitCommitInfo, error) {
 			Email:     commit.Author.Email,
 			Message:   commit.Message,
 			Timestamp: commit.Author.When,
+			Signature: verifier.Verify(commit),
 		})
 		return nil
 	})
@@ -4,13 +4,22 @@
 package git
 
 import (
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/transport/http"
 
 	"github.com/go-git/go-git/v5"
 )
 
-func (s *Service) Pull(auth *http.BasicAuth) error {
-	repo, err := git.PlainOpen(s.ProjectDir)
+type PullOptions struct {
+	// Remote defaults to origin
+	Remote string
+	// Branch is the remote branch to merge, defaults to the upstream of the current branch
+	Branch string
+	Auth   *http.BasicAuth
+}
+
+func (s *Service) Pull(opts *PullOptions) error {
+	repo, err := s.openRepository()
 	if err != nil {
 		return err
 	}
@@ -20,9 +29,21 @@ func (s *Service) Pull(auth *http.BasicAuth) error {
 		return err
 	}
 
+	if opts == nil {
+		opts = &PullOptions{}
+	}
+
 	options := &git.PullOptions{
-		RemoteName: "origin",
-		Auth:       auth,
+		RemoteName: git.DefaultRemoteName,
+		Auth:       opts.Auth,
+	}
+
+	if opts.Remote != "" {
+		options.RemoteName = opts.Remote
+	}
+
+	if opts.Branch != "" {
+		options.ReferenceName = plumbing.NewBranchReferenceName(opts.Branch)
 	}
 
 	return w.Pull(options)
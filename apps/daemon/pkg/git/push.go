@@ -4,16 +4,29 @@
 package git
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
 	"github.com/go-git/go-git/v5/plumbing/transport/http"
 
 	"github.com/go-git/go-git/v5"
 )
 
-func (s *Service) Push(auth *http.BasicAuth) error {
-	repo, err := git.PlainOpen(s.ProjectDir)
+type PushOptions struct {
+	// Remote defaults to origin
+	Remote string
+	// Branch is the remote branch name, defaults to the current branch name
+	Branch         string
+	SetUpstream    bool
+	ForceWithLease bool
+	Auth           *http.BasicAuth
+}
+
+func (s *Service) Push(opts *PushOptions) error {
+	repo, err := s.openRepository()
 	if err != nil {
 		return err
 	}
@@ -23,12 +36,122 @@ func (s *Service) Push(auth *http.BasicAuth) error {
 		return err
 	}
 
+	if opts == nil {
+		opts = &PushOptions{}
+	}
+
+	remote := opts.Remote
+	if remote == "" {
+		remote = git.DefaultRemoteName
+	}
+
+	target := ref.Name()
+	if opts.Branch != "" {
+		target = plumbing.NewBranchReferenceName(opts.Branch)
+	}
+
+	refSpec := config.RefSpec(fmt.Sprintf("%s:%s", ref.Name(), target))
+
+	var lease *git.ForceWithLease
+	if opts.ForceWithLease {
+		lease, err = checkLease(repo, remote, ref.Name(), target, opts.Auth)
+		if err != nil {
+			return err
+		}
+		if lease == nil {
+			// The lease was checked above, go-git cannot express it
+			refSpec = "+" + refSpec
+		}
+	}
+
 	options := &git.PushOptions{
-		Auth: auth,
-		RefSpecs: []config.RefSpec{
-			config.RefSpec(fmt.Sprintf("%s:%s", ref.Name(), ref.Name())),
-		},
+		RemoteName:     remote,
+		Auth:           opts.Auth,
+		RefSpecs:       []config.RefSpec{refSpec},
+		ForceWithLease: lease,
+	}
+
+	err = repo.Push(options)
+	if err != nil && err != git.NoErrAlreadyUpToDate {
+		return err
+	}
+
+	if opts.SetUpstream && ref.Name().IsBranch() {
+		if upstreamErr := setUpstream(repo, ref.Name().Short(), remote, target); upstreamErr != nil {
+			return upstreamErr
+		}
+	}
+
+	return err
+}
+
+// checkLease verifies that the target branch on the remote is where its
+// remote-tracking ref says it was last fetched, or absent when there is no
+// remote-tracking ref. It returns the lease for go-git to check again against
+// the refs advertised by the push, or nil when go-git cannot check it: go-git
+// looks up the remote-tracking ref of the local branch rather than the one of
+// the target, and cannot expect a branch to be absent.
+func checkLease(repo *git.Repository, remoteName string, local, target plumbing.ReferenceName, auth *http.BasicAuth) (*git.ForceWithLease, error) {
+	expected := plumbing.ZeroHash
+	tracking, err := repo.Reference(plumbing.NewRemoteReferenceName(remoteName, target.Short()), true)
+	if err == nil {
+		expected = tracking.Hash()
+	} else if !errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return nil, err
+	}
+
+	remote, err := repo.Remote(remoteName)
+	if err != nil {
+		return nil, err
+	}
+
+	listOptions := &git.ListOptions{}
+	if auth != nil {
+		listOptions.Auth = auth
+	}
+	remoteRefs, err := remote.List(listOptions)
+	if err != nil && !errors.Is(err, transport.ErrEmptyRemoteRepository) {
+		return nil, err
+	}
+
+	actual := plumbing.ZeroHash
+	for _, remoteRef := range remoteRefs {
+		if remoteRef.Name() == target {
+			actual = remoteRef.Hash()
+		}
+	}
+
+	if actual != expected {
+		if expected.IsZero() {
+			return nil, fmt.Errorf("stale info: %s exists on %s but has not been fetched", target.Short(), remoteName)
+		}
+		return nil, fmt.Errorf("stale info: %s on %s is not at the fetched %s", target.Short(), remoteName, expected)
+	}
+
+	if expected.IsZero() {
+		return nil, nil
+	}
+	if _, err := repo.Reference(plumbing.NewRemoteReferenceName(remoteName, local.Short()), true); err != nil {
+		return nil, nil
+	}
+
+	return &git.ForceWithLease{
+		RefName: target,
+		Hash:    expected,
+	}, nil
+}
+
+func setUpstream(repo *git.Repository, branch, remote string, merge plumbing.ReferenceName) error {
+	cfg, err := repo.Config()
+	if err != nil {
+		return err
+	}
+
+	cfg.Branches[branch] = &config.Branch{
+		Name:   branch,
+		Remote: remote,
+		Merge:  merge,
 	}
 
-	return repo.Push(options)
+	return repo.SetConfig(cfg)
 }
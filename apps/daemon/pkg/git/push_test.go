@@ -0,0 +1,168 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package git_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/daytonaio/daemon/pkg/git"
+	go_git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/require"
+)
+
+// newRemoteRepository creates a bare remote and a repository with one commit
+// that has it configured as origin
+func newRemoteRepository(t *testing.T) (string, string, *git.Service) {
+	remoteDir := t.TempDir()
+	_, err := go_git.PlainInit(remoteDir, true)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	_, err = go_git.PlainInit(dir, false)
+	require.NoError(t, err)
+	commitFile(t, dir, "file.txt", "first\n", "alice")
+
+	gitService := &git.Service{ProjectDir: dir}
+	require.NoError(t, gitService.AddRemote("origin", remoteDir))
+
+	remotes, err := gitService.ListRemotes()
+	require.NoError(t, err)
+	require.Len(t, remotes, 1)
+
+	return remoteDir, dir, gitService
+}
+
+func remoteHash(t *testing.T, remoteDir string, name plumbing.ReferenceName) plumbing.Hash {
+	repo, err := go_git.PlainOpen(remoteDir)
+	require.NoError(t, err)
+
+	ref, err := repo.Reference(name, true)
+	if err == plumbing.ErrReferenceNotFound {
+		return plumbing.ZeroHash
+	}
+	require.NoError(t, err)
+	return ref.Hash()
+}
+
+func headHash(t *testing.T, dir string) plumbing.Hash {
+	repo, err := go_git.PlainOpen(dir)
+	require.NoError(t, err)
+
+	head, err := repo.Head()
+	require.NoError(t, err)
+	return head.Hash()
+}
+
+func fetch(t *testing.T, gitService *git.Service) {
+	err := gitService.Fetch("", nil, nil)
+	if err != go_git.NoErrAlreadyUpToDate {
+		require.NoError(t, err)
+	}
+}
+
+// pushFromClone pushes a commit to branch from another clone of the remote
+func pushFromClone(t *testing.T, remoteDir, branch string) {
+	cloneDir := t.TempDir()
+	_, err := go_git.PlainClone(cloneDir, false, &go_git.CloneOptions{
+		URL:           remoteDir,
+		ReferenceName: plumbing.NewBranchReferenceName(branch),
+	})
+	require.NoError(t, err)
+
+	commitFile(t, cloneDir, "other.txt", "other\n", "bob")
+
+	repo, err := go_git.PlainOpen(cloneDir)
+	require.NoError(t, err)
+	require.NoError(t, repo.Push(&go_git.PushOptions{}))
+}
+
+func TestPush_ForceWithLease(t *testing.T) {
+	remoteDir, dir, gitService := newRemoteRepository(t)
+	branch := plumbing.Master
+
+	require.NoError(t, gitService.Push(&git.PushOptions{SetUpstream: true}))
+	fetch(t, gitService)
+
+	// The remote moves on while the local branch diverges from it
+	pushFromClone(t, remoteDir, branch.Short())
+	commitFile(t, dir, "file.txt", "rewritten\n", "alice")
+
+	require.ErrorContains(t, gitService.Push(nil), "non-fast-forward")
+	require.ErrorContains(t, gitService.Push(&git.PushOptions{ForceWithLease: true}), "stale info")
+
+	fetch(t, gitService)
+	require.NoError(t, gitService.Push(&git.PushOptions{ForceWithLease: true}))
+	require.Equal(t, headHash(t, dir), remoteHash(t, remoteDir, branch))
+}
+
+func TestPush_ForceWithLeaseToOtherBranch(t *testing.T) {
+	remoteDir, dir, gitService := newRemoteRepository(t)
+	release := plumbing.NewBranchReferenceName("release")
+
+	// Without a remote-tracking ref the remote branch is expected to be absent
+	require.NoError(t, gitService.Push(&git.PushOptions{Branch: "release", ForceWithLease: true}))
+	require.Equal(t, headHash(t, dir), remoteHash(t, remoteDir, release))
+
+	fetch(t, gitService)
+	pushFromClone(t, remoteDir, "release")
+
+	commitFile(t, dir, "file.txt", "second\n", "alice")
+	require.ErrorContains(t, gitService.Push(&git.PushOptions{Branch: "release", ForceWithLease: true}), "stale info")
+	require.NotEqual(t, headHash(t, dir), remoteHash(t, remoteDir, release))
+
+	fetch(t, gitService)
+	require.NoError(t, gitService.Push(&git.PushOptions{Branch: "release", ForceWithLease: true}))
+	require.Equal(t, headHash(t, dir), remoteHash(t, remoteDir, release))
+}
+
+func TestPush_ForceWithLeaseToUnfetchedBranch(t *testing.T) {
+	remoteDir, dir, gitService := newRemoteRepository(t)
+	release := plumbing.NewBranchReferenceName("release")
+
+	otherDir := t.TempDir()
+	_, err := go_git.PlainInit(otherDir, false)
+	require.NoError(t, err)
+	commitFile(t, otherDir, "other.txt", "other\n", "bob")
+
+	otherService := &git.Service{ProjectDir: otherDir}
+	require.NoError(t, otherService.AddRemote("origin", remoteDir))
+	require.NoError(t, otherService.Push(&git.PushOptions{Branch: "release"}))
+
+	require.ErrorContains(t, gitService.Push(&git.PushOptions{Branch: "release", ForceWithLease: true}), "has not been fetched")
+	require.NotEqual(t, headHash(t, dir), remoteHash(t, remoteDir, release))
+}
+
+func TestTags_CreatePushDelete(t *testing.T) {
+	remoteDir, dir, gitService := newRemoteRepository(t)
+
+	require.NoError(t, gitService.CreateTag("v1.0.0", "", "", nil))
+	require.NoError(t, gitService.CreateTag("v1.1.0", "HEAD", "release", &object.Signature{
+		Name:  "alice",
+		Email: "alice@example.com",
+		When:  time.Now(),
+	}))
+
+	tags, err := gitService.ListTags()
+	require.NoError(t, err)
+	require.Len(t, tags, 2)
+	for _, tag := range tags {
+		require.Equal(t, headHash(t, dir).String(), tag.Target)
+		require.Equal(t, tag.Name == "v1.1.0", tag.Annotated)
+	}
+
+	require.NoError(t, gitService.PushTag("", "v1.1.0", nil))
+	require.False(t, remoteHash(t, remoteDir, plumbing.NewTagReferenceName("v1.1.0")).IsZero())
+	require.True(t, remoteHash(t, remoteDir, plumbing.NewTagReferenceName("v1.0.0")).IsZero())
+
+	require.NoError(t, gitService.DeleteRemoteTag("", "v1.1.0", nil))
+	require.True(t, remoteHash(t, remoteDir, plumbing.NewTagReferenceName("v1.1.0")).IsZero())
+
+	require.NoError(t, gitService.DeleteTag("v1.0.0"))
+	tags, err = gitService.ListTags()
+	require.NoError(t, err)
+	require.Len(t, tags, 1)
+}
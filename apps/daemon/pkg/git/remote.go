@@ -0,0 +1,53 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package git
+
+import (
+	"github.com/go-git/go-git/v5/config"
+)
+
+func (s *Service) ListRemotes() ([]GitRemote, error) {
+	repo, err := s.openRepository()
+	if err != nil {
+		return []GitRemote{}, err
+	}
+
+	remotes, err := repo.Remotes()
+	if err != nil {
+		return []GitRemote{}, err
+	}
+
+	remoteList := []GitRemote{}
+	for _, remote := range remotes {
+		cfg := remote.Config()
+		remoteList = append(remoteList, GitRemote{
+			Name: cfg.Name,
+			URLs: cfg.URLs,
+		})
+	}
+
+	return remoteList, nil
+}
+
+func (s *Service) AddRemote(name, url string) error {
+	repo, err := s.openRepository()
+	if err != nil {
+		return err
+	}
+
+	_, err = repo.CreateRemote(&config.RemoteConfig{
+		Name: name,
+		URLs: []string{url},
+	})
+	return err
+}
+
+func (s *Service) RemoveRemote(name string) error {
+	repo, err := s.openRepository()
+	if err != nil {
+		return err
+	}
+
+	return repo.DeleteRemote(name)
+}
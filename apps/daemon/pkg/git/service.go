@@ -19,6 +19,15 @@ type GitStatus struct {
 	BranchPublished bool          `json:"branchPublished" validate:"optional"`
 	Ahead           int           `json:"ahead" validate:"optional"`
 	Behind          int           `json:"behind" validate:"optional"`
+	Upstream        string        `json:"upstream,omitempty" validate:"optional"`
+	HeadCommit      string        `json:"headCommit,omitempty" validate:"optional"`
+	Detached        bool          `json:"detached" validate:"optional"`
+	StashCount      int           `json:"stashCount" validate:"optional"`
+	// merge, rebase, cherry-pick, revert or bisect when one is in progress
+	Operation Operation `json:"operation,omitempty" validate:"optional"`
+	// Set when the git binary is not installed, the upstream, ahead/behind
+	// counts and stash count are then not reported
+	Partial bool `json:"partial,omitempty" validate:"optional"`
 } // @name GitStatus
 
 type FileStatus struct {
@@ -26,8 +35,19 @@ type FileStatus struct {
 	Extra    string `json:"extra" validate:"required"`
 	Staging  Status `json:"staging" validate:"required"`
 	Worktree Status `json:"worktree" validate:"required"`
+	// previous path of a renamed or copied file
+	OrigName  string           `json:"origName,omitempty" validate:"optional"`
+	Additions *int             `json:"additions,omitempty" validate:"optional"`
+	Deletions *int             `json:"deletions,omitempty" validate:"optional"`
+	Submodule *SubmoduleStatus `json:"submodule,omitempty" validate:"optional"`
 } // @name FileStatus
 
+type SubmoduleStatus struct {
+	CommitChanged    bool `json:"commitChanged" validate:"required"`
+	HasModifications bool `json:"hasModifications" validate:"required"`
+	HasUntracked     bool `json:"hasUntracked" validate:"required"`
+} // @name SubmoduleStatus
+
 // Status status code of a file in the Worktree
 type Status string // @name Status
 
@@ -40,6 +60,7 @@ const (
 	Renamed            Status = "Renamed"
 	Copied             Status = "Copied"
 	UpdatedButUnmerged Status = "Updated but unmerged"
+	Ignored            Status = "Ignored"
 )
 
 var MapStatus map[git.StatusCode]Status = map[git.StatusCode]Status{
@@ -53,12 +74,32 @@ var MapStatus map[git.StatusCode]Status = map[git.StatusCode]Status{
 	git.UpdatedButUnmerged: UpdatedButUnmerged,
 }
 
+// Operation in-progress multi-step operation in the repository
+type Operation string // @name Operation
+
+const (
+	OperationMerge      Operation = "merge"
+	OperationRebase     Operation = "rebase"
+	OperationCherryPick Operation = "cherry-pick"
+	OperationRevert     Operation = "revert"
+	OperationBisect     Operation = "bisect"
+)
+
+type StatusOptions struct {
+	DetectRenames  bool
+	IncludeIgnored bool
+	// LineStats adds per-file added/deleted line counts against HEAD
+	LineStats bool
+	// Paths limits the status to the given pathspecs
+	Paths []string
+}
+
 type IGitService interface {
 	CloneRepository(repo *gitprovider.GitRepository, auth *http.BasicAuth) error
 	CloneRepositoryCmd(repo *gitprovider.GitRepository, auth *http.BasicAuth) []string
 	RepositoryExists() (bool, error)
 	SetGitConfig(userData *gitprovider.GitUser, providerConfig *gitprovider.GitProviderConfig) error
-	GetGitStatus() (*GitStatus, error)
+	GetGitStatus(opts *StatusOptions) (*GitStatus, error)
 }
 
 type Service struct {
@@ -78,3 +119,11 @@ func (s *Service) RepositoryExists() (bool, error) {
 	}
 	return true, nil
 }
+
+// openRepository opens the repository at ProjectDir. Linked worktrees keep
+// their refs and objects in the main repository, so the common dir is enabled.
+func (s *Service) openRepository() (*git.Repository, error) {
+	return git.PlainOpenWithOptions(s.ProjectDir, &git.PlainOpenOptions{
+		EnableDotGitCommonDir: true,
+	})
+}
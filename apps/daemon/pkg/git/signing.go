@@ -0,0 +1,240 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package git
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/daytonaio/daemon/pkg/gitprovider"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	sshSigMagic     = "SSHSIG"
+	sshSigNamespace = "git"
+	sshSigPemType   = "SSH SIGNATURE"
+)
+
+type SigningOptions struct {
+	// Method defaults to the gpg.format git config value
+	Method gitprovider.SigningMethod
+	// Key is private key material, an armored GPG key or an OpenSSH private key
+	Key string
+	// KeyId references a key known to gpg or ssh-keygen (GPG key id, SSH key file or public key).
+	// When both Key and KeyId are empty, user.signingkey from the git config is used.
+	KeyId      string
+	Passphrase string
+}
+
+// newSigner returns a go-git signer for the given options. Key material is
+// used in-process, key references are signed with the gpg/ssh-keygen binaries
+// the same way the git binary does.
+func (s *Service) newSigner(repo *git.Repository, opts *SigningOptions) (git.Signer, error) {
+	method := opts.Method
+	keyId := opts.KeyId
+
+	if method == "" || (opts.Key == "" && keyId == "") {
+		cfg, err := repo.ConfigScoped(config.GlobalScope)
+		if err != nil {
+			return nil, err
+		}
+
+		if method == "" {
+			method = gitprovider.SigningMethodGPG
+			if cfg.Raw.Section("gpg").Option("format") == string(gitprovider.SigningMethodSSH) {
+				method = gitprovider.SigningMethodSSH
+			}
+		}
+
+		if opts.Key == "" && keyId == "" {
+			keyId = cfg.Raw.Section("user").Option("signingkey")
+			if keyId == "" {
+				return nil, errors.New("no signing key provided and user.signingkey is not configured")
+			}
+		}
+	}
+
+	switch method {
+	case gitprovider.SigningMethodGPG:
+		if opts.Key != "" {
+			return newGPGKeySigner(opts.Key, opts.Passphrase)
+		}
+		return &gpgCommandSigner{keyId: keyId}, nil
+	case gitprovider.SigningMethodSSH:
+		if opts.Key != "" {
+			return newSSHKeySigner(opts.Key, opts.Passphrase)
+		}
+		return &sshCommandSigner{key: keyId}, nil
+	default:
+		return nil, fmt.Errorf("unsupported signing method '%s'", method)
+	}
+}
+
+type gpgKeySigner struct {
+	entity *openpgp.Entity
+}
+
+func newGPGKeySigner(armoredKey, passphrase string) (*gpgKeySigner, error) {
+	entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(armoredKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read GPG key: %w", err)
+	}
+	if len(entities) == 0 || entities[0].PrivateKey == nil {
+		return nil, errors.New("GPG key does not contain a private key")
+	}
+
+	entity := entities[0]
+	if entity.PrivateKey.Encrypted {
+		if passphrase == "" {
+			return nil, errors.New("GPG key is encrypted but no passphrase was provided")
+		}
+		if err := entity.DecryptPrivateKeys([]byte(passphrase)); err != nil {
+			return nil, fmt.Errorf("failed to decrypt GPG key: %w", err)
+		}
+	}
+
+	return &gpgKeySigner{entity: entity}, nil
+}
+
+func (s *gpgKeySigner) Sign(message io.Reader) ([]byte, error) {
+	var b bytes.Buffer
+	if err := openpgp.ArmoredDetachSign(&b, s.entity, message, nil); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+type gpgCommandSigner struct {
+	keyId string
+}
+
+func (s *gpgCommandSigner) Sign(message io.Reader) ([]byte, error) {
+	return runSigningCommand(message, "gpg", "--status-fd=2", "-bsau", s.keyId)
+}
+
+type sshKeySigner struct {
+	signer ssh.Signer
+}
+
+func newSSHKeySigner(privateKey, passphrase string) (*sshKeySigner, error) {
+	var signer ssh.Signer
+	var err error
+	if passphrase != "" {
+		signer, err = ssh.ParsePrivateKeyWithPassphrase([]byte(privateKey), []byte(passphrase))
+	} else {
+		signer, err = ssh.ParsePrivateKey([]byte(privateKey))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse SSH key: %w", err)
+	}
+
+	return &sshKeySigner{signer: signer}, nil
+}
+
+// Sign produces an armored SSHSIG signature, equivalent to `ssh-keygen -Y sign -n git`.
+func (s *sshKeySigner) Sign(message io.Reader) ([]byte, error) {
+	h := sha512.New()
+	if _, err := io.Copy(h, message); err != nil {
+		return nil, err
+	}
+
+	signedData := sshSignedData("sha512", h.Sum(nil))
+
+	var sig *ssh.Signature
+	var err error
+	if algSigner, ok := s.signer.(ssh.AlgorithmSigner); ok && s.signer.PublicKey().Type() == ssh.KeyAlgoRSA {
+		sig, err = algSigner.SignWithAlgorithm(rand.Reader, signedData, ssh.KeyAlgoRSASHA512)
+	} else {
+		sig, err = s.signer.Sign(rand.Reader, signedData)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	blob := append([]byte(sshSigMagic), ssh.Marshal(sshSignature{
+		Version:       1,
+		PublicKey:     s.signer.PublicKey().Marshal(),
+		Namespace:     sshSigNamespace,
+		HashAlgorithm: "sha512",
+		Signature:     ssh.Marshal(sig),
+	})...)
+
+	return pem.EncodeToMemory(&pem.Block{Type: sshSigPemType, Bytes: blob}), nil
+}
+
+type sshCommandSigner struct {
+	// key is a path to a key file or a literal public key held by the ssh-agent
+	key string
+}
+
+func (s *sshCommandSigner) Sign(message io.Reader) ([]byte, error) {
+	key := strings.TrimPrefix(s.key, "key::")
+	if !strings.HasPrefix(key, "ssh-") && !strings.HasPrefix(key, "ecdsa-") && !strings.HasPrefix(key, "sk-") {
+		return runSigningCommand(message, "ssh-keygen", "-Y", "sign", "-n", sshSigNamespace, "-f", key)
+	}
+
+	// Literal public keys are resolved through the ssh-agent
+	keyFile, err := os.CreateTemp("", "daytona-signing-key-*.pub")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(keyFile.Name())
+
+	_, err = keyFile.WriteString(key + "\n")
+	keyFile.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return runSigningCommand(message, "ssh-keygen", "-Y", "sign", "-n", sshSigNamespace, "-U", "-f", keyFile.Name())
+}
+
+func runSigningCommand(message io.Reader, name string, args ...string) ([]byte, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = message
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s failed to sign commit: %s", name, strings.TrimSpace(stderr.String()))
+	}
+
+	return out, nil
+}
+
+type sshSignature struct {
+	Version       uint32
+	PublicKey     []byte
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Signature     []byte
+}
+
+func sshSignedData(hashAlgorithm string, hash []byte) []byte {
+	return append([]byte(sshSigMagic), ssh.Marshal(struct {
+		Namespace     string
+		Reserved      string
+		HashAlgorithm string
+		Hash          []byte
+	}{
+		Namespace:     sshSigNamespace,
+		HashAlgorithm: hashAlgorithm,
+		Hash:          hash,
+	})...)
+}
@@ -0,0 +1,143 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package git_test
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/daytonaio/daemon/pkg/git"
+	"github.com/daytonaio/daemon/pkg/gitprovider"
+	go_git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+func signedCommit(t *testing.T, gitService *git.Service, signing *git.SigningOptions) {
+	_, err := gitService.Commit("signed", &go_git.CommitOptions{
+		Author:            &object.Signature{Name: "Daytona", Email: "dev@daytona.io", When: time.Now()},
+		AllowEmptyCommits: true,
+	}, signing)
+	require.NoError(t, err)
+}
+
+func TestCommit_SSHSignature(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	block, err := ssh.MarshalPrivateKey(privateKey, "")
+	require.NoError(t, err)
+
+	sshPublicKey, err := ssh.NewPublicKey(publicKey)
+	require.NoError(t, err)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(home, ".ssh"), 0700))
+	allowedSigners := fmt.Sprintf("dev@daytona.io namespaces=\"git\" %s", ssh.MarshalAuthorizedKey(sshPublicKey))
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".ssh/allowed_signers"), []byte(allowedSigners), 0600))
+
+	dir := t.TempDir()
+	_, err = go_git.PlainInit(dir, false)
+	require.NoError(t, err)
+
+	gitService := &git.Service{ProjectDir: dir}
+	signedCommit(t, gitService, &git.SigningOptions{
+		Method: gitprovider.SigningMethodSSH,
+		Key:    string(pem.EncodeToMemory(block)),
+	})
+
+	history, err := gitService.Log()
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.Equal(t, git.SignatureStatusVerified, history[0].Signature.Status)
+	require.Equal(t, gitprovider.SigningMethodSSH, history[0].Signature.Method)
+	require.Equal(t, ssh.FingerprintSHA256(sshPublicKey), history[0].Signature.Signer)
+}
+
+func TestCommit_GPGSignature(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	entity, err := openpgp.NewEntity("Daytona", "", "dev@daytona.io", nil)
+	require.NoError(t, err)
+	// Sorts first but is not the primary identity
+	require.NoError(t, entity.AddUserId("Alice", "", "alice@daytona.io", nil))
+
+	var privateKey bytes.Buffer
+	w, err := armor.Encode(&privateKey, openpgp.PrivateKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.SerializePrivate(w, nil))
+	require.NoError(t, w.Close())
+
+	dir := t.TempDir()
+	_, err = go_git.PlainInit(dir, false)
+	require.NoError(t, err)
+
+	gitService := &git.Service{ProjectDir: dir}
+	signedCommit(t, gitService, &git.SigningOptions{
+		Method: gitprovider.SigningMethodGPG,
+		Key:    privateKey.String(),
+	})
+
+	repo, err := go_git.PlainOpen(dir)
+	require.NoError(t, err)
+	head, err := repo.Head()
+	require.NoError(t, err)
+	commit, err := repo.CommitObject(head.Hash())
+	require.NoError(t, err)
+
+	var publicKey bytes.Buffer
+	w, err = armor.Encode(&publicKey, openpgp.PublicKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.Serialize(w))
+	require.NoError(t, w.Close())
+
+	_, err = commit.Verify(publicKey.String())
+	require.NoError(t, err)
+
+	if _, err := exec.LookPath("gpg"); err != nil {
+		t.Skip("gpg is not installed")
+	}
+
+	gnupgHome := t.TempDir()
+	t.Setenv("GNUPGHOME", gnupgHome)
+	importKey := exec.Command("gpg", "--batch", "--import")
+	importKey.Stdin = &publicKey
+	out, err := importKey.CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	history, err := gitService.Log()
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.Equal(t, git.SignatureStatusVerified, history[0].Signature.Status)
+	require.Equal(t, gitprovider.SigningMethodGPG, history[0].Signature.Method)
+	require.Equal(t, "Daytona <dev@daytona.io>", history[0].Signature.Signer)
+}
+
+func TestCommit_SigningWithoutKey(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	dir := t.TempDir()
+	_, err := go_git.PlainInit(dir, false)
+	require.NoError(t, err)
+
+	gitService := &git.Service{ProjectDir: dir}
+	_, err = gitService.Commit("signed", &go_git.CommitOptions{
+		Author:            &object.Signature{Name: "Daytona", Email: "dev@daytona.io", When: time.Now()},
+		AllowEmptyCommits: true,
+	}, &git.SigningOptions{Method: gitprovider.SigningMethodSSH})
+	require.Error(t, err)
+}
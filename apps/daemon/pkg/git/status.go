@@ -5,15 +5,77 @@ package git
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strconv"
 	"strings"
 
-	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/storage/filesystem"
 )
 
-func (s *Service) GetGitStatus() (*GitStatus, error) {
-	repo, err := git.PlainOpen(s.ProjectDir)
+func (s *Service) GetGitStatus(opts *StatusOptions) (*GitStatus, error) {
+	if opts == nil {
+		opts = &StatusOptions{}
+	}
+
+	if _, err := exec.LookPath("git"); err != nil {
+		return s.getGoGitStatus(opts)
+	}
+
+	args := []string{"status", "--porcelain=v2", "--branch", "--show-stash", "--untracked-files=all", "-z"}
+	if opts.DetectRenames {
+		args = append(args, "--find-renames")
+	} else {
+		args = append(args, "--no-renames")
+	}
+	if opts.IncludeIgnored {
+		args = append(args, "--ignored=matching")
+	}
+	args = append(args, "--")
+	args = append(args, opts.Paths...)
+
+	out, err := s.runGitCommand(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	status := parseStatusPorcelainV2(out)
+
+	if opts.LineStats {
+		if err := s.addLineStats(status, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	gitDir, err := s.runGitCommand("rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return nil, err
+	}
+	status.Operation = detectOperation(strings.TrimSpace(string(gitDir)))
+
+	return status, nil
+}
+
+// getGoGitStatus is used when the git binary is not installed. It reports
+// the current branch, the in-progress operation and per-file staging/worktree
+// states, and fails for the options it cannot honour.
+func (s *Service) getGoGitStatus(opts *StatusOptions) (*GitStatus, error) {
+	var unsupported []string
+	if opts.DetectRenames {
+		unsupported = append(unsupported, "rename detection")
+	}
+	if opts.IncludeIgnored {
+		unsupported = append(unsupported, "ignored files")
+	}
+	if opts.LineStats {
+		unsupported = append(unsupported, "line stats")
+	}
+	if len(unsupported) > 0 {
+		return nil, fmt.Errorf("%s require the git binary, which is not installed", strings.Join(unsupported, ", "))
+	}
+
+	repo, err := s.openRepository()
 	if err != nil {
 		return nil, err
 	}
@@ -35,6 +97,10 @@ func (s *Service) GetGitStatus() (*GitStatus, error) {
 
 	files := []*FileStatus{}
 	for path, file := range status {
+		if !matchesPaths(path, opts.Paths) {
+			continue
+		}
+
 		files = append(files, &FileStatus{
 			Name:     path,
 			Extra:    file.Extra,
@@ -43,76 +109,252 @@ func (s *Service) GetGitStatus() (*GitStatus, error) {
 		})
 	}
 
-	branchPublished, err := s.isBranchPublished()
-	if err != nil {
-		return nil, err
-	}
-
-	ahead, behind, err := s.getAheadBehindInfo()
-	if err != nil {
-		return nil, err
+	var operation Operation
+	if storage, ok := repo.Storer.(*filesystem.Storage); ok {
+		operation = detectOperation(storage.Filesystem().Root())
 	}
 
 	return &GitStatus{
-		CurrentBranch:   ref.Name().Short(),
-		Files:           files,
-		BranchPublished: branchPublished,
-		Ahead:           ahead,
-		Behind:          behind,
+		CurrentBranch: ref.Name().Short(),
+		Files:         files,
+		HeadCommit:    ref.Hash().String(),
+		Detached:      !ref.Name().IsBranch(),
+		Operation:     operation,
+		Partial:       true,
 	}, nil
 }
 
-func (s *Service) isBranchPublished() (bool, error) {
-	upstream, err := s.getUpstreamBranch()
-	if err != nil {
-		return false, err
+// parseStatusPorcelainV2 parses the output of
+// `git status --porcelain=v2 --branch --show-stash -z`.
+func parseStatusPorcelainV2(output []byte) *GitStatus {
+	status := &GitStatus{
+		Files: []*FileStatus{},
 	}
-	return upstream != "", nil
+
+	entries := strings.Split(string(output), "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if entry == "" {
+			continue
+		}
+
+		switch entry[0] {
+		case '#':
+			parseStatusHeader(status, entry)
+		case '1':
+			// 1 <XY> <sub> <mH> <mI> <mW> <hH> <hI> <path>
+			fields := strings.SplitN(entry, " ", 9)
+			if len(fields) == 9 {
+				status.Files = append(status.Files, newFileStatus(fields[1], fields[2], fields[8]))
+			}
+		case '2':
+			// 2 <XY> <sub> <mH> <mI> <mW> <hH> <hI> <X><score> <path>, followed by <origPath>
+			fields := strings.SplitN(entry, " ", 10)
+			if len(fields) == 10 {
+				file := newFileStatus(fields[1], fields[2], fields[9])
+				if i+1 < len(entries) {
+					i++
+					file.OrigName = entries[i]
+					file.Extra = entries[i]
+				}
+				status.Files = append(status.Files, file)
+			}
+		case 'u':
+			// u <XY> <sub> <m1> <m2> <m3> <mW> <h1> <h2> <h3> <path>
+			fields := strings.SplitN(entry, " ", 11)
+			if len(fields) == 11 {
+				file := newFileStatus(fields[1], fields[2], fields[10])
+				file.Staging = UpdatedButUnmerged
+				file.Worktree = UpdatedButUnmerged
+				status.Files = append(status.Files, file)
+			}
+		case '?':
+			status.Files = append(status.Files, &FileStatus{
+				Name:     entry[2:],
+				Staging:  Untracked,
+				Worktree: Untracked,
+			})
+		case '!':
+			status.Files = append(status.Files, &FileStatus{
+				Name:     entry[2:],
+				Staging:  Ignored,
+				Worktree: Ignored,
+			})
+		}
+	}
+
+	status.BranchPublished = status.Upstream != ""
+
+	return status
 }
 
-func (s *Service) getUpstreamBranch() (string, error) {
-	cmd := exec.Command("git", "-C", s.ProjectDir, "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}")
-	out, err := cmd.CombinedOutput()
-	if err != nil {
-		return "", nil
+func parseStatusHeader(status *GitStatus, header string) {
+	fields := strings.Fields(header)
+	if len(fields) < 3 {
+		return
 	}
 
-	return strings.TrimSpace(string(out)), nil
+	switch fields[1] {
+	case "branch.oid":
+		if fields[2] != "(initial)" {
+			status.HeadCommit = fields[2]
+		}
+	case "branch.head":
+		if fields[2] == "(detached)" {
+			status.CurrentBranch = "HEAD"
+			status.Detached = true
+		} else {
+			status.CurrentBranch = fields[2]
+		}
+	case "branch.upstream":
+		status.Upstream = fields[2]
+	case "branch.ab":
+		if len(fields) == 4 {
+			status.Ahead, _ = strconv.Atoi(strings.TrimPrefix(fields[2], "+"))
+			status.Behind, _ = strconv.Atoi(strings.TrimPrefix(fields[3], "-"))
+		}
+	case "stash":
+		status.StashCount, _ = strconv.Atoi(fields[2])
+	}
 }
 
-func (s *Service) getAheadBehindInfo() (int, int, error) {
-	upstream, err := s.getUpstreamBranch()
-	if err != nil {
-		return 0, 0, err
+func newFileStatus(xy, sub, path string) *FileStatus {
+	file := &FileStatus{
+		Name:     path,
+		Staging:  mapStatusCode(xy[0]),
+		Worktree: mapStatusCode(xy[1]),
 	}
-	if upstream == "" {
-		return 0, 0, nil
+
+	// <sub> is "N..." for regular files and "S<c><m><u>" for submodules
+	if len(sub) == 4 && sub[0] == 'S' {
+		file.Submodule = &SubmoduleStatus{
+			CommitChanged:    sub[1] == 'C',
+			HasModifications: sub[2] == 'M',
+			HasUntracked:     sub[3] == 'U',
+		}
 	}
 
-	cmd := exec.Command("git", "-C", s.ProjectDir, "rev-list", "--left-right", "--count", fmt.Sprintf("%s...HEAD", upstream))
-	out, err := cmd.CombinedOutput()
+	return file
+}
+
+func mapStatusCode(code byte) Status {
+	switch code {
+	case 'M', 'T':
+		return Modified
+	case 'A':
+		return Added
+	case 'D':
+		return Deleted
+	case 'R':
+		return Renamed
+	case 'C':
+		return Copied
+	case 'U':
+		return UpdatedButUnmerged
+	default:
+		return Unmodified
+	}
+}
+
+// addLineStats fills in added/deleted line counts of each file against HEAD,
+// or against the empty tree when the branch has no commits yet.
+func (s *Service) addLineStats(status *GitStatus, opts *StatusOptions) error {
+	args := []string{"diff", "--numstat", "-z"}
+	if opts.DetectRenames {
+		args = append(args, "--find-renames")
+	} else {
+		args = append(args, "--no-renames")
+	}
+	if status.HeadCommit != "" {
+		args = append(args, "HEAD")
+	} else {
+		args = append(args, "--cached")
+	}
+	args = append(args, "--")
+	args = append(args, opts.Paths...)
+
+	out, err := s.runGitCommand(args...)
 	if err != nil {
-		return 0, 0, nil
+		return err
 	}
 
-	return parseAheadBehind(out)
+	stats := parseNumstat(out)
+	for _, file := range status.Files {
+		if stat, ok := stats[file.Name]; ok {
+			file.Additions = stat[0]
+			file.Deletions = stat[1]
+		}
+	}
+
+	return nil
 }
 
-func parseAheadBehind(output []byte) (int, int, error) {
-	counts := strings.Split(strings.TrimSpace(string(output)), "\t")
-	if len(counts) != 2 {
-		return 0, 0, nil
+// parseNumstat parses `git diff --numstat -z` output into additions/deletions
+// per path. Binary files have nil counts.
+func parseNumstat(output []byte) map[string][2]*int {
+	stats := map[string][2]*int{}
+
+	entries := strings.Split(string(output), "\x00")
+	for i := 0; i < len(entries); i++ {
+		fields := strings.SplitN(entries[i], "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+
+		path := fields[2]
+		// Renames have an empty path followed by the old and new paths
+		if path == "" && i+2 < len(entries) {
+			path = entries[i+2]
+			i += 2
+		}
+
+		stats[path] = [2]*int{parseCount(fields[0]), parseCount(fields[1])}
 	}
 
-	ahead, err := strconv.Atoi(counts[1])
+	return stats
+}
+
+func parseCount(value string) *int {
+	count, err := strconv.Atoi(value)
 	if err != nil {
-		return 0, 0, nil
+		return nil
 	}
+	return &count
+}
 
-	behind, err := strconv.Atoi(counts[0])
-	if err != nil {
-		return 0, 0, nil
+func detectOperation(gitDir string) Operation {
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(gitDir, name))
+		return err == nil
+	}
+
+	switch {
+	case exists("rebase-merge"), exists("rebase-apply"):
+		return OperationRebase
+	case exists("MERGE_HEAD"):
+		return OperationMerge
+	case exists("CHERRY_PICK_HEAD"):
+		return OperationCherryPick
+	case exists("REVERT_HEAD"):
+		return OperationRevert
+	case exists("BISECT_LOG"):
+		return OperationBisect
+	}
+
+	return ""
+}
+
+func matchesPaths(path string, paths []string) bool {
+	if len(paths) == 0 {
+		return true
+	}
+
+	for _, p := range paths {
+		p = strings.TrimSuffix(filepath.ToSlash(filepath.Clean(p)), "/")
+		if p == "." || path == p || strings.HasPrefix(path, p+"/") {
+			return true
+		}
 	}
 
-	return ahead, behind, nil
+	return false
 }
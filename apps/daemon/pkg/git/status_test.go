@@ -0,0 +1,87 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package git_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/daytonaio/daemon/pkg/git"
+	go_git "github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetGitStatus_Options(t *testing.T) {
+	dir := t.TempDir()
+	_, err := go_git.PlainInit(dir, false)
+	require.NoError(t, err)
+
+	commitFile(t, dir, ".gitignore", "*.log\n", "alice")
+	commitFile(t, dir, "old.txt", "one\ntwo\nthree\n", "alice")
+
+	require.NoError(t, os.Rename(filepath.Join(dir, "old.txt"), filepath.Join(dir, "new.txt")))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "debug.log"), []byte("log"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sub", "file.txt"), []byte("a\nb\n"), 0644))
+
+	gitService := &git.Service{ProjectDir: dir}
+	require.NoError(t, gitService.Add([]string{"."}))
+
+	status, err := gitService.GetGitStatus(&git.StatusOptions{
+		DetectRenames:  true,
+		IncludeIgnored: true,
+		LineStats:      true,
+	})
+	require.NoError(t, err)
+	require.False(t, status.Detached)
+	require.Empty(t, status.Operation)
+
+	files := map[string]*git.FileStatus{}
+	for _, file := range status.Files {
+		files[file.Name] = file
+	}
+
+	require.Contains(t, files, "new.txt")
+	require.Equal(t, git.Renamed, files["new.txt"].Staging)
+	require.Equal(t, "old.txt", files["new.txt"].OrigName)
+	require.NotContains(t, files, "old.txt")
+
+	require.Contains(t, files, "debug.log")
+	require.Equal(t, git.Ignored, files["debug.log"].Worktree)
+
+	require.Contains(t, files, "sub/file.txt")
+	require.Equal(t, git.Added, files["sub/file.txt"].Staging)
+	require.NotNil(t, files["sub/file.txt"].Additions)
+	require.Equal(t, 2, *files["sub/file.txt"].Additions)
+
+	status, err = gitService.GetGitStatus(&git.StatusOptions{Paths: []string{"sub"}})
+	require.NoError(t, err)
+	require.Len(t, status.Files, 1)
+	require.Equal(t, "sub/file.txt", status.Files[0].Name)
+}
+
+func TestGetGitStatus_WithoutGitBinary(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	dir := t.TempDir()
+	_, err := go_git.PlainInit(dir, false)
+	require.NoError(t, err)
+
+	commitFile(t, dir, "file.txt", "one\n", "alice")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file.txt"), []byte("two\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".git", "MERGE_HEAD"), nil, 0644))
+
+	gitService := &git.Service{ProjectDir: dir}
+
+	_, err = gitService.GetGitStatus(&git.StatusOptions{LineStats: true})
+	require.ErrorContains(t, err, "line stats require the git binary")
+
+	status, err := gitService.GetGitStatus(nil)
+	require.NoError(t, err)
+	require.True(t, status.Partial)
+	require.Equal(t, git.OperationMerge, status.Operation)
+	require.Len(t, status.Files, 1)
+	require.Equal(t, git.Modified, status.Files[0].Worktree)
+}
@@ -0,0 +1,115 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package git
+
+import (
+	"fmt"
+
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+
+	"github.com/go-git/go-git/v5"
+)
+
+func (s *Service) ListTags() ([]GitTag, error) {
+	repo, err := s.openRepository()
+	if err != nil {
+		return []GitTag{}, err
+	}
+
+	tags, err := repo.Tags()
+	if err != nil {
+		return []GitTag{}, err
+	}
+
+	tagList := []GitTag{}
+	err = tags.ForEach(func(ref *plumbing.Reference) error {
+		tag := GitTag{
+			Name:   ref.Name().Short(),
+			Target: ref.Hash().String(),
+		}
+
+		// Lightweight tags point directly at a commit
+		annotated, err := repo.TagObject(ref.Hash())
+		switch err {
+		case nil:
+			tag.Annotated = true
+			tag.Target = annotated.Target.String()
+			tag.Message = annotated.Message
+			tag.Tagger = annotated.Tagger.Name
+			tag.Email = annotated.Tagger.Email
+			tag.Timestamp = &annotated.Tagger.When
+		case plumbing.ErrObjectNotFound:
+		default:
+			return err
+		}
+
+		tagList = append(tagList, tag)
+		return nil
+	})
+
+	return tagList, err
+}
+
+// CreateTag creates a tag pointing at ref, or at HEAD if ref is empty.
+// The tag is annotated when a tagger is provided, lightweight otherwise.
+func (s *Service) CreateTag(name, ref, message string, tagger *object.Signature) error {
+	repo, err := s.openRepository()
+	if err != nil {
+		return err
+	}
+
+	commit, err := resolveCommit(repo, ref)
+	if err != nil {
+		return err
+	}
+
+	var options *git.CreateTagOptions
+	if tagger != nil {
+		options = &git.CreateTagOptions{
+			Tagger:  tagger,
+			Message: message,
+		}
+	}
+
+	_, err = repo.CreateTag(name, commit.Hash, options)
+	return err
+}
+
+func (s *Service) DeleteTag(name string) error {
+	repo, err := s.openRepository()
+	if err != nil {
+		return err
+	}
+
+	return repo.DeleteTag(name)
+}
+
+func (s *Service) PushTag(remote, name string, auth *http.BasicAuth) error {
+	tagRef := plumbing.NewTagReferenceName(name)
+	return s.pushRefSpec(remote, config.RefSpec(fmt.Sprintf("%s:%s", tagRef, tagRef)), auth)
+}
+
+func (s *Service) DeleteRemoteTag(remote, name string, auth *http.BasicAuth) error {
+	return s.pushRefSpec(remote, config.RefSpec(fmt.Sprintf(":%s", plumbing.NewTagReferenceName(name))), auth)
+}
+
+func (s *Service) pushRefSpec(remote string, refSpec config.RefSpec, auth *http.BasicAuth) error {
+	repo, err := s.openRepository()
+	if err != nil {
+		return err
+	}
+
+	if remote == "" {
+		remote = git.DefaultRemoteName
+	}
+
+	return repo.Push(&git.PushOptions{
+		RemoteName: remote,
+		Auth:       auth,
+		RefSpecs:   []config.RefSpec{refSpec},
+	})
+}
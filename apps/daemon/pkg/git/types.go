@@ -3,12 +3,69 @@
 
 package git
 
-import "time"
+import (
+	"time"
+
+	"github.com/daytonaio/daemon/pkg/gitprovider"
+)
 
 type GitCommitInfo struct {
+	Hash      string            `json:"hash" validate:"required"`
+	Author    string            `json:"author" validate:"required"`
+	Email     string            `json:"email" validate:"required"`
+	Message   string            `json:"message" validate:"required"`
+	Timestamp time.Time         `json:"timestamp" validate:"required"`
+	Signature *GitSignatureInfo `json:"signature,omitempty" validate:"optional"`
+} // @name GitCommitInfo
+
+type SignatureStatus string // @name SignatureStatus
+
+const (
+	SignatureStatusUnsigned SignatureStatus = "unsigned"
+	// Signed, but the key is unknown or the signature does not match
+	SignatureStatusUnverified SignatureStatus = "unverified"
+	SignatureStatusVerified   SignatureStatus = "verified"
+)
+
+type GitSignatureInfo struct {
+	Status SignatureStatus           `json:"status" validate:"required"`
+	Method gitprovider.SigningMethod `json:"method,omitempty" validate:"optional"`
+	// GPG identity or SSH key fingerprint of a verified signature
+	Signer string `json:"signer,omitempty" validate:"optional"`
+} // @name GitSignatureInfo
+
+type GitWorktree struct {
+	Path       string `json:"path" validate:"required"`
+	Head       string `json:"head" validate:"optional"`
+	Branch     string `json:"branch" validate:"optional"`
+	Bare       bool   `json:"bare" validate:"optional"`
+	Detached   bool   `json:"detached" validate:"optional"`
+	Locked     bool   `json:"locked" validate:"optional"`
+	LockReason string `json:"lockReason" validate:"optional"`
+	Prunable   bool   `json:"prunable" validate:"optional"`
+} // @name GitWorktree
+
+type GitRemote struct {
+	Name string   `json:"name" validate:"required"`
+	URLs []string `json:"urls" validate:"required"`
+} // @name GitRemote
+
+type GitTag struct {
+	Name string `json:"name" validate:"required"`
+	// hash of the tagged commit
+	Target    string     `json:"target" validate:"required"`
+	Annotated bool       `json:"annotated" validate:"required"`
+	Message   string     `json:"message,omitempty" validate:"optional"`
+	Tagger    string     `json:"tagger,omitempty" validate:"optional"`
+	Email     string     `json:"email,omitempty" validate:"optional"`
+	Timestamp *time.Time `json:"timestamp,omitempty" validate:"optional"`
+} // @name GitTag
+
+type GitBlameLine struct {
+	Line      int       `json:"line" validate:"required"`
 	Hash      string    `json:"hash" validate:"required"`
 	Author    string    `json:"author" validate:"required"`
 	Email     string    `json:"email" validate:"required"`
-	Message   string    `json:"message" validate:"required"`
 	Timestamp time.Time `json:"timestamp" validate:"required"`
-} // @name GitCommitInfo
+	Text      string    `json:"text" validate:"required"`
+} // @name GitBlameLine
@@ -0,0 +1,222 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/pem"
+	"errors"
+	"hash"
+	"io"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/daytonaio/daemon/pkg/gitprovider"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"golang.org/x/crypto/ssh"
+)
+
+// signatureVerifier checks commit signatures against the GPG keyring and the
+// SSH allowed signers file. Both are loaded and parsed once and reused across
+// commits.
+type signatureVerifier struct {
+	gpgKeyRing     openpgp.EntityList
+	allowedSigners []allowedSigner
+}
+
+type allowedSigner struct {
+	principals []string
+	key        ssh.PublicKey
+}
+
+func newSignatureVerifier() *signatureVerifier {
+	v := &signatureVerifier{}
+
+	if out, err := exec.Command("gpg", "--export", "--armor").Output(); err == nil && len(out) > 0 {
+		if keyRing, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(out)); err == nil {
+			v.gpgKeyRing = keyRing
+		}
+	}
+
+	allowedSignersFile := filepath.Join(os.Getenv("HOME"), ".ssh/allowed_signers")
+	if content, err := os.ReadFile(allowedSignersFile); err == nil {
+		v.allowedSigners = parseAllowedSigners(content)
+	}
+
+	return v
+}
+
+func (v *signatureVerifier) Verify(commit *object.Commit) *GitSignatureInfo {
+	if commit.PGPSignature == "" {
+		return &GitSignatureInfo{Status: SignatureStatusUnsigned}
+	}
+
+	if strings.HasPrefix(commit.PGPSignature, "-----BEGIN "+sshSigPemType) {
+		info := &GitSignatureInfo{Method: gitprovider.SigningMethodSSH, Status: SignatureStatusUnverified}
+		if fingerprint, err := v.verifySSH(commit); err == nil {
+			info.Status = SignatureStatusVerified
+			info.Signer = fingerprint
+		}
+		return info
+	}
+
+	info := &GitSignatureInfo{Method: gitprovider.SigningMethodGPG, Status: SignatureStatusUnverified}
+	if len(v.gpgKeyRing) == 0 {
+		return info
+	}
+
+	entity, err := v.verifyGPG(commit)
+	if err != nil {
+		return info
+	}
+
+	info.Status = SignatureStatusVerified
+	info.Signer = gpgSignerName(entity)
+
+	return info
+}
+
+// verifyGPG verifies an OpenPGP commit signature against the parsed keyring
+// and returns the signing entity.
+func (v *signatureVerifier) verifyGPG(commit *object.Commit) (*openpgp.Entity, error) {
+	encoded := &plumbing.MemoryObject{}
+	if err := commit.EncodeWithoutSignature(encoded); err != nil {
+		return nil, err
+	}
+	reader, err := encoded.Reader()
+	if err != nil {
+		return nil, err
+	}
+
+	return openpgp.CheckArmoredDetachedSignature(v.gpgKeyRing, reader, strings.NewReader(commit.PGPSignature), nil)
+}
+
+// gpgSignerName returns the identity marked as primary, or the first identity
+// by name, so that keys with several identities always report the same one.
+func gpgSignerName(entity *openpgp.Entity) string {
+	names := slices.Sorted(maps.Keys(entity.Identities))
+	for _, name := range names {
+		selfSignature := entity.Identities[name].SelfSignature
+		if selfSignature != nil && selfSignature.IsPrimaryId != nil && *selfSignature.IsPrimaryId {
+			return name
+		}
+	}
+	if len(names) > 0 {
+		return names[0]
+	}
+	return entity.PrimaryKey.KeyIdString()
+}
+
+// verifySSH verifies an SSHSIG commit signature, equivalent to `ssh-keygen -Y verify`
+// with the committer email as the principal, and returns the signing key fingerprint.
+func (v *signatureVerifier) verifySSH(commit *object.Commit) (string, error) {
+	block, _ := pem.Decode([]byte(commit.PGPSignature))
+	if block == nil || block.Type != sshSigPemType || !bytes.HasPrefix(block.Bytes, []byte(sshSigMagic)) {
+		return "", errors.New("malformed SSH signature")
+	}
+
+	var sig sshSignature
+	if err := ssh.Unmarshal(block.Bytes[len(sshSigMagic):], &sig); err != nil {
+		return "", err
+	}
+	if sig.Version != 1 || sig.Namespace != sshSigNamespace {
+		return "", errors.New("unsupported SSH signature")
+	}
+
+	publicKey, err := ssh.ParsePublicKey(sig.PublicKey)
+	if err != nil {
+		return "", err
+	}
+
+	if !v.isAllowedSigner(commit.Committer.Email, publicKey) {
+		return "", errors.New("signing key is not an allowed signer")
+	}
+
+	var h hash.Hash
+	switch sig.HashAlgorithm {
+	case "sha256":
+		h = sha256.New()
+	case "sha512":
+		h = sha512.New()
+	default:
+		return "", errors.New("unsupported SSH signature hash algorithm")
+	}
+
+	encoded := &plumbing.MemoryObject{}
+	if err := commit.EncodeWithoutSignature(encoded); err != nil {
+		return "", err
+	}
+	reader, err := encoded.Reader()
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(h, reader); err != nil {
+		return "", err
+	}
+
+	signature := new(ssh.Signature)
+	if err := ssh.Unmarshal(sig.Signature, signature); err != nil {
+		return "", err
+	}
+
+	if err := publicKey.Verify(sshSignedData(sig.HashAlgorithm, h.Sum(nil)), signature); err != nil {
+		return "", err
+	}
+
+	return ssh.FingerprintSHA256(publicKey), nil
+}
+
+func (v *signatureVerifier) isAllowedSigner(email string, key ssh.PublicKey) bool {
+	for _, signer := range v.allowedSigners {
+		if !bytes.Equal(signer.key.Marshal(), key.Marshal()) {
+			continue
+		}
+		for _, principal := range signer.principals {
+			if matched, _ := filepath.Match(principal, email); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseAllowedSigners parses an allowed signers file in the format written by
+// SetGitConfig: `principals [options] keytype base64-key [comment]`.
+func parseAllowedSigners(content []byte) []allowedSigner {
+	signers := []allowedSigner{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		principals, rest, found := strings.Cut(line, " ")
+		if !found {
+			continue
+		}
+
+		key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(rest))
+		if err != nil {
+			continue
+		}
+
+		signers = append(signers, allowedSigner{
+			principals: strings.Split(principals, ","),
+			key:        key,
+		})
+	}
+
+	return signers
+}
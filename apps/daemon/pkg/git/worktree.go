@@ -0,0 +1,149 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// go-git has no support for linked worktrees, so these operations are
+// delegated to the git binary. Positional arguments follow "--" so that paths
+// and branches starting with "-" are not parsed as options.
+
+func (s *Service) AddWorktree(path, branch, commitish string, createBranch bool) error {
+	args := []string{"worktree", "add"}
+	if createBranch {
+		if branch == "" {
+			return fmt.Errorf("branch name is required to create a branch")
+		}
+		args = append(args, "-b", branch, "--", path)
+		if commitish != "" {
+			args = append(args, commitish)
+		}
+	} else if branch != "" {
+		args = append(args, "--", path, branch)
+	} else if commitish != "" {
+		args = append(args, "--detach", "--", path, commitish)
+	} else {
+		args = append(args, "--", path)
+	}
+
+	_, err := s.runGitCommand(args...)
+	return err
+}
+
+func (s *Service) ListWorktrees() ([]GitWorktree, error) {
+	out, err := s.runGitCommand("worktree", "list", "--porcelain")
+	if err != nil {
+		return []GitWorktree{}, err
+	}
+
+	return parseWorktreeList(out), nil
+}
+
+func (s *Service) LockWorktree(path, reason string) error {
+	args := []string{"worktree", "lock"}
+	if reason != "" {
+		args = append(args, "--reason", reason)
+	}
+	args = append(args, "--", path)
+
+	_, err := s.runGitCommand(args...)
+	return err
+}
+
+func (s *Service) UnlockWorktree(path string) error {
+	_, err := s.runGitCommand("worktree", "unlock", "--", path)
+	return err
+}
+
+func (s *Service) RemoveWorktree(path string, force bool) error {
+	args := []string{"worktree", "remove"}
+	if force {
+		args = append(args, "--force")
+	}
+	args = append(args, "--", path)
+
+	_, err := s.runGitCommand(args...)
+	return err
+}
+
+func (s *Service) PruneWorktrees() error {
+	_, err := s.runGitCommand("worktree", "prune")
+	return err
+}
+
+func (s *Service) runGitCommand(args ...string) ([]byte, error) {
+	cmd := exec.Command("git", append([]string{"-C", s.ProjectDir}, args...)...)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			return nil, fmt.Errorf("git %s failed: %w", args[0], err)
+		}
+		return nil, fmt.Errorf("git %s failed: %s", args[0], msg)
+	}
+
+	return out, nil
+}
+
+// parseWorktreeList parses the output of `git worktree list --porcelain`,
+// where each worktree is a block of attribute lines separated by a blank line.
+func parseWorktreeList(output []byte) []GitWorktree {
+	worktrees := []GitWorktree{}
+
+	var current *GitWorktree
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if current != nil {
+				worktrees = append(worktrees, *current)
+				current = nil
+			}
+			continue
+		}
+
+		key, value, _ := strings.Cut(line, " ")
+		if key == "worktree" {
+			current = &GitWorktree{Path: value}
+			continue
+		}
+		if current == nil {
+			continue
+		}
+
+		switch key {
+		case "HEAD":
+			current.Head = value
+		case "branch":
+			current.Branch = plumbing.ReferenceName(value).Short()
+		case "bare":
+			current.Bare = true
+		case "detached":
+			current.Detached = true
+		case "locked":
+			current.Locked = true
+			current.LockReason = value
+		case "prunable":
+			current.Prunable = true
+		}
+	}
+
+	if current != nil {
+		worktrees = append(worktrees, *current)
+	}
+
+	return worktrees
+}
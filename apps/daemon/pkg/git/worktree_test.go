@@ -0,0 +1,36 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package git_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/daytonaio/daemon/pkg/git"
+	go_git "github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorktree_PathsStartingWithDash(t *testing.T) {
+	dir := t.TempDir()
+	_, err := go_git.PlainInit(dir, false)
+	require.NoError(t, err)
+	commitFile(t, dir, "file.txt", "content\n", "alice")
+
+	gitService := &git.Service{ProjectDir: dir}
+
+	// Would be parsed as the --force option without the separator
+	path := "--force"
+	require.NoError(t, gitService.AddWorktree(path, "feature", "", true))
+	require.DirExists(t, filepath.Join(dir, path))
+
+	require.NoError(t, gitService.LockWorktree(path, "in use"))
+	require.Error(t, gitService.RemoveWorktree(path, false))
+	require.NoError(t, gitService.UnlockWorktree(path))
+	require.NoError(t, gitService.RemoveWorktree(path, false))
+	require.NoDirExists(t, filepath.Join(dir, path))
+
+	require.NoError(t, gitService.AddWorktree("-detached", "", "HEAD", false))
+	require.DirExists(t, filepath.Join(dir, "-detached"))
+}
@@ -0,0 +1,122 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package recording
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// Event types of the asciicast v2 format
+const (
+	EventOutput = "o"
+	EventInput  = "i"
+	EventResize = "r"
+)
+
+// maxEventSize bounds the length of a single line when reading recordings.
+const maxEventSize = 4 * 1024 * 1024
+
+// Header is the first line of an asciicast v2 recording.
+// See https://docs.asciinema.org/manual/asciicast/v2/
+type Header struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp,omitempty"`
+	Command   string            `json:"command,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+} // @name RecordingHeader
+
+// Event is a single line of an asciicast v2 recording, encoded as a
+// [time, type, data] JSON array. Time is in seconds since the recording
+// started.
+type Event struct {
+	Time float64
+	Type string
+	Data string
+}
+
+func (e Event) MarshalJSON() ([]byte, error) {
+	// Microsecond precision is what asciinema itself records
+	return json.Marshal([]any{math.Round(e.Time*1e6) / 1e6, e.Type, e.Data})
+}
+
+func (e *Event) UnmarshalJSON(data []byte) error {
+	var fields []json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	if len(fields) != 3 {
+		return fmt.Errorf("invalid event: expected 3 fields, got %d", len(fields))
+	}
+
+	if err := json.Unmarshal(fields[0], &e.Time); err != nil {
+		return fmt.Errorf("invalid event time: %w", err)
+	}
+	if err := json.Unmarshal(fields[1], &e.Type); err != nil {
+		return fmt.Errorf("invalid event type: %w", err)
+	}
+	if err := json.Unmarshal(fields[2], &e.Data); err != nil {
+		return fmt.Errorf("invalid event data: %w", err)
+	}
+
+	return nil
+}
+
+// Reader reads the events of an asciicast v2 recording.
+type Reader struct {
+	Header  Header
+	scanner *bufio.Scanner
+}
+
+func NewReader(r io.Reader) (*Reader, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxEventSize)
+
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("recording is empty")
+	}
+
+	var header Header
+	if err := json.Unmarshal(scanner.Bytes(), &header); err != nil {
+		return nil, fmt.Errorf("invalid recording header: %w", err)
+	}
+	if header.Version != 2 {
+		return nil, fmt.Errorf("unsupported asciicast version: %d", header.Version)
+	}
+
+	return &Reader{Header: header, scanner: scanner}, nil
+}
+
+// Next returns the next event, or io.EOF once every event was read. A
+// truncated last line, as left behind by a recording that is still being
+// written, is treated as the end of the recording.
+func (r *Reader) Next() (Event, error) {
+	for r.scanner.Scan() {
+		line := r.scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var event Event
+		if err := json.Unmarshal(line, &event); err != nil {
+			return Event{}, io.EOF
+		}
+		return event, nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return Event{}, err
+	}
+	return Event{}, io.EOF
+}
@@ -0,0 +1,144 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package recording
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Recorder writes the events of a PTY session to an asciicast v2 file.
+// Every event is written as soon as it is recorded so that recordings of
+// sessions that end abruptly are still complete up to that point.
+//
+// All methods are safe for concurrent use and are no-ops on a nil Recorder,
+// so callers do not have to check whether recording is enabled.
+type Recorder struct {
+	id    string
+	store *Store
+
+	mu        sync.Mutex
+	file      *os.File
+	startedAt time.Time
+	// trailing bytes of an incomplete UTF-8 sequence per event type
+	pending map[string][]byte
+	closed  bool
+}
+
+func (r *Recorder) Id() string {
+	if r == nil {
+		return ""
+	}
+	return r.id
+}
+
+// Output records data written by the PTY.
+func (r *Recorder) Output(data []byte) {
+	r.record(EventOutput, data)
+}
+
+// Input records data sent to the PTY.
+func (r *Recorder) Input(data []byte) {
+	r.record(EventInput, data)
+}
+
+// Resize records a change of the terminal size.
+func (r *Recorder) Resize(cols, rows int) {
+	r.record(EventResize, []byte(fmt.Sprintf("%dx%d", cols, rows)))
+}
+
+// OutputWriter returns a writer recording everything written to it as output.
+func (r *Recorder) OutputWriter() io.Writer {
+	return recorderWriter{recorder: r, eventType: EventOutput}
+}
+
+// InputWriter returns a writer recording everything written to it as input.
+func (r *Recorder) InputWriter() io.Writer {
+	return recorderWriter{recorder: r, eventType: EventInput}
+}
+
+func (r *Recorder) Close() error {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+	r.store.finish(r.id)
+
+	return r.file.Close()
+}
+
+func (r *Recorder) record(eventType string, data []byte) {
+	if r == nil || len(data) == 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return
+	}
+
+	// Event data must be valid UTF-8, hold back a multi-byte character split
+	// across reads until the rest of it arrives
+	data = append(r.pending[eventType], data...)
+	cut := incompleteSuffix(data)
+	r.pending[eventType] = append([]byte(nil), data[cut:]...)
+	data = data[:cut]
+	if len(data) == 0 {
+		return
+	}
+
+	line, err := json.Marshal(Event{
+		Time: time.Since(r.startedAt).Seconds(),
+		Type: eventType,
+		Data: string(data),
+	})
+	if err != nil {
+		log.Errorf("Failed to encode recording event: %v", err)
+		return
+	}
+
+	if _, err := r.file.Write(append(line, '\n')); err != nil {
+		log.Errorf("Failed to write recording %s: %v", r.id, err)
+	}
+}
+
+// incompleteSuffix returns the index at which an incomplete UTF-8 sequence
+// at the end of data starts, or len(data) if there is none.
+func incompleteSuffix(data []byte) int {
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRune(data[i:]) {
+				return i
+			}
+			break
+		}
+	}
+	return len(data)
+}
+
+type recorderWriter struct {
+	recorder  *Recorder
+	eventType string
+}
+
+func (w recorderWriter) Write(p []byte) (int, error) {
+	w.recorder.record(w.eventType, p)
+	return len(p), nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package recording_test
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/daytonaio/daemon/pkg/recording"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder_WritesAsciicast(t *testing.T) {
+	store, err := recording.NewStore(t.TempDir())
+	require.NoError(t, err)
+
+	recorder, err := store.Start(recording.StartOptions{Title: "ssh: daytona", Width: 80, Height: 24})
+	require.NoError(t, err)
+
+	recorder.Input([]byte("ls\r"))
+	// A multi-byte character split across two reads is recorded whole
+	euro := []byte("€\r\n")
+	recorder.Output(euro[:1])
+	recorder.Output(euro[1:])
+	recorder.Resize(120, 40)
+
+	info, err := store.Get(recorder.Id())
+	require.NoError(t, err)
+	require.True(t, info.Active)
+	require.Equal(t, "ssh: daytona", info.Title)
+
+	require.NoError(t, recorder.Close())
+
+	recordings, err := store.List()
+	require.NoError(t, err)
+	require.Len(t, recordings, 1)
+	require.False(t, recordings[0].Active)
+
+	file, err := store.Open(recorder.Id())
+	require.NoError(t, err)
+	defer file.Close()
+
+	reader, err := recording.NewReader(file)
+	require.NoError(t, err)
+	require.Equal(t, 2, reader.Header.Version)
+	require.Equal(t, 80, reader.Header.Width)
+
+	var events []recording.Event
+	for {
+		event, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		events = append(events, event)
+	}
+
+	require.Len(t, events, 3)
+	require.Equal(t, recording.EventInput, events[0].Type)
+	require.Equal(t, "ls\r", events[0].Data)
+	require.Equal(t, recording.EventOutput, events[1].Type)
+	require.Equal(t, "€\r\n", events[1].Data)
+	require.Equal(t, recording.EventResize, events[2].Type)
+	require.Equal(t, "120x40", events[2].Data)
+}
+
+func TestStore_RejectsInvalidIds(t *testing.T) {
+	store, err := recording.NewStore(t.TempDir())
+	require.NoError(t, err)
+
+	_, err = store.Open("../../etc/passwd")
+	require.ErrorIs(t, err, recording.ErrRecordingNotFound)
+}
@@ -0,0 +1,213 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package recording
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const fileExtension = ".cast"
+
+var ErrRecordingNotFound = errors.New("recording not found")
+
+type RecordingInfo struct {
+	Id        string    `json:"id" validate:"required"`
+	Title     string    `json:"title,omitempty" validate:"optional"`
+	Command   string    `json:"command,omitempty" validate:"optional"`
+	Width     int       `json:"width" validate:"required"`
+	Height    int       `json:"height" validate:"required"`
+	StartedAt time.Time `json:"startedAt" validate:"required"`
+	UpdatedAt time.Time `json:"updatedAt" validate:"required"`
+	Size      int64     `json:"size" validate:"required"`
+	// Whether the session is still being recorded
+	Active bool `json:"active" validate:"required"`
+} // @name RecordingInfo
+
+type StartOptions struct {
+	Title   string
+	Command string
+	Width   int
+	Height  int
+	Env     map[string]string
+}
+
+// Store keeps PTY session recordings as asciicast v2 files in a directory.
+type Store struct {
+	dir string
+
+	mu     sync.Mutex
+	active map[string]struct{}
+}
+
+func NewStore(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create recordings directory: %w", err)
+	}
+
+	return &Store{
+		dir:    dir,
+		active: make(map[string]struct{}),
+	}, nil
+}
+
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Start creates a new recording and writes its header. It returns a nil
+// Recorder on a nil Store so that callers can record unconditionally.
+func (s *Store) Start(opts StartOptions) (*Recorder, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	startedAt := time.Now()
+	header := Header{
+		Version:   2,
+		Width:     opts.Width,
+		Height:    opts.Height,
+		Timestamp: startedAt.Unix(),
+		Command:   opts.Command,
+		Title:     opts.Title,
+		Env:       opts.Env,
+	}
+
+	line, err := json.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+
+	id := uuid.NewString()
+	file, err := os.OpenFile(s.path(id), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recording: %w", err)
+	}
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to write recording header: %w", err)
+	}
+
+	s.mu.Lock()
+	s.active[id] = struct{}{}
+	s.mu.Unlock()
+
+	return &Recorder{
+		id:        id,
+		store:     s,
+		file:      file,
+		startedAt: startedAt,
+		pending:   make(map[string][]byte),
+	}, nil
+}
+
+// List returns every recording in the store, oldest first.
+func (s *Store) List() ([]RecordingInfo, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	recordings := []RecordingInfo{}
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), fileExtension)
+		if !ok || entry.IsDir() {
+			continue
+		}
+
+		info, err := s.Get(id)
+		if err != nil {
+			// Skip files that are not readable recordings
+			continue
+		}
+		recordings = append(recordings, info)
+	}
+
+	sort.Slice(recordings, func(i, j int) bool {
+		return recordings[i].StartedAt.Before(recordings[j].StartedAt)
+	})
+
+	return recordings, nil
+}
+
+func (s *Store) Get(id string) (RecordingInfo, error) {
+	file, err := s.Open(id)
+	if err != nil {
+		return RecordingInfo{}, err
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return RecordingInfo{}, err
+	}
+
+	reader, err := NewReader(file)
+	if err != nil {
+		return RecordingInfo{}, err
+	}
+
+	s.mu.Lock()
+	_, active := s.active[id]
+	s.mu.Unlock()
+
+	return RecordingInfo{
+		Id:        id,
+		Title:     reader.Header.Title,
+		Command:   reader.Header.Command,
+		Width:     reader.Header.Width,
+		Height:    reader.Header.Height,
+		StartedAt: time.Unix(reader.Header.Timestamp, 0),
+		UpdatedAt: stat.ModTime(),
+		Size:      stat.Size(),
+		Active:    active,
+	}, nil
+}
+
+// Open opens a recording for reading.
+func (s *Store) Open(id string) (*os.File, error) {
+	// Only ids generated by the store are valid, which also keeps the path
+	// inside the store directory
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, ErrRecordingNotFound
+	}
+
+	file, err := os.Open(s.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrRecordingNotFound
+	}
+	return file, err
+}
+
+func (s *Store) Path(id string) (string, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return "", ErrRecordingNotFound
+	}
+
+	path := s.path(id)
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return "", ErrRecordingNotFound
+	}
+	return path, nil
+}
+
+func (s *Store) path(id string) string {
+	return filepath.Join(s.dir, id+fileExtension)
+}
+
+func (s *Store) finish(id string) {
+	s.mu.Lock()
+	delete(s.active, id)
+	s.mu.Unlock()
+}
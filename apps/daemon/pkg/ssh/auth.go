@@ -0,0 +1,252 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package ssh
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gliderlabs/ssh"
+	gossh "golang.org/x/crypto/ssh"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// keyPolicyExtension is the permissions extension carrying the policy of the
+// key a connection authenticated with.
+const keyPolicyExtension = "daytona-key-policy"
+
+// KeyPolicy restricts what a connection authenticated with a key may do. It
+// is configured with the standard authorized_keys options. The zero value
+// allows nothing beyond running commands.
+type KeyPolicy struct {
+	PortForwarding  bool `json:"portForwarding"`
+	AgentForwarding bool `json:"agentForwarding"`
+	Pty             bool `json:"pty"`
+	// Destinations allowed for local forwarding as host:port, any when empty
+	PermitOpen []string `json:"permitOpen,omitempty"`
+	// Addresses allowed for remote forwarding as [host:]port, any when empty
+	PermitListen []string `json:"permitListen,omitempty"`
+}
+
+// parseKeyPolicy builds the policy of a key from its authorized_keys options.
+// Options that cannot be enforced are rejected so that keys are never granted
+// more than their entry allows.
+func parseKeyPolicy(options []string) (KeyPolicy, error) {
+	policy := KeyPolicy{
+		PortForwarding:  true,
+		AgentForwarding: true,
+		Pty:             true,
+	}
+
+	for _, option := range options {
+		name, value, hasValue := strings.Cut(option, "=")
+		value = strings.Trim(value, `"`)
+
+		switch strings.ToLower(name) {
+		case "restrict":
+			policy.PortForwarding = false
+			policy.AgentForwarding = false
+			policy.Pty = false
+		case "port-forwarding":
+			policy.PortForwarding = true
+		case "no-port-forwarding":
+			policy.PortForwarding = false
+		case "agent-forwarding":
+			policy.AgentForwarding = true
+		case "no-agent-forwarding":
+			policy.AgentForwarding = false
+		case "pty":
+			policy.Pty = true
+		case "no-pty":
+			policy.Pty = false
+		case "permitopen":
+			if !hasValue {
+				return KeyPolicy{}, errors.New("permitopen requires a value")
+			}
+			policy.PermitOpen = append(policy.PermitOpen, value)
+		case "permitlisten":
+			if !hasValue {
+				return KeyPolicy{}, errors.New("permitlisten requires a value")
+			}
+			policy.PermitListen = append(policy.PermitListen, value)
+		case "no-x11-forwarding", "x11-forwarding", "no-user-rc", "user-rc":
+			// X11 forwarding and user rc files are not supported at all
+		default:
+			return KeyPolicy{}, fmt.Errorf("unsupported option %q", name)
+		}
+	}
+
+	return policy, nil
+}
+
+// AllowsLocalForwarding reports whether the key may open connections to
+// host:port from the sandbox.
+func (p KeyPolicy) AllowsLocalForwarding(host string, port uint32) bool {
+	if !p.PortForwarding {
+		return false
+	}
+	if len(p.PermitOpen) == 0 {
+		return true
+	}
+
+	for _, permitted := range p.PermitOpen {
+		permittedHost, permittedPort, err := net.SplitHostPort(permitted)
+		if err != nil {
+			continue
+		}
+		if matchesHost(permittedHost, host) && matchesPort(permittedPort, port) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsRemoteForwarding reports whether the key may listen on host:port in
+// the sandbox.
+func (p KeyPolicy) AllowsRemoteForwarding(host string, port uint32) bool {
+	if !p.PortForwarding {
+		return false
+	}
+	if len(p.PermitListen) == 0 {
+		return true
+	}
+
+	for _, permitted := range p.PermitListen {
+		permittedHost, permittedPort, err := net.SplitHostPort(permitted)
+		if err != nil {
+			// A bare port only permits listening on localhost
+			permittedHost, permittedPort = "localhost", permitted
+		}
+		if permittedHost == "localhost" && isLoopback(host) && matchesPort(permittedPort, port) {
+			return true
+		}
+		if matchesHost(permittedHost, host) && matchesPort(permittedPort, port) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesHost(pattern, host string) bool {
+	return pattern == "*" || strings.EqualFold(pattern, host)
+}
+
+func matchesPort(pattern string, port uint32) bool {
+	return pattern == "*" || pattern == strconv.FormatUint(uint64(port), 10)
+}
+
+func isLoopback(host string) bool {
+	if host == "" || strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+type authorizedKey struct {
+	key    gossh.PublicKey
+	policy KeyPolicy
+}
+
+// authorizedKeys verifies public keys against an authorized_keys file and
+// keys injected when the sandbox was created. The file is read on every
+// authentication attempt so that edits take effect for new connections.
+type authorizedKeys struct {
+	path     string
+	injected []authorizedKey
+}
+
+func newAuthorizedKeys(path string, injected string) *authorizedKeys {
+	return &authorizedKeys{
+		path:     path,
+		injected: parseAuthorizedKeys([]byte(injected), "injected keys"),
+	}
+}
+
+func (a *authorizedKeys) lookup(key gossh.PublicKey) (KeyPolicy, bool) {
+	keys := a.injected
+
+	if a.path != "" {
+		content, err := os.ReadFile(a.path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Errorf("Failed to read authorized keys from %s: %v", a.path, err)
+		}
+		keys = append(keys[:len(keys):len(keys)], parseAuthorizedKeys(content, a.path)...)
+	}
+
+	for _, authorized := range keys {
+		if ssh.KeysEqual(authorized.key, key) {
+			return authorized.policy, true
+		}
+	}
+	return KeyPolicy{}, false
+}
+
+func parseAuthorizedKeys(content []byte, source string) []authorizedKey {
+	keys := []authorizedKey{}
+
+	for len(strings.TrimSpace(string(content))) > 0 {
+		key, _, options, rest, err := gossh.ParseAuthorizedKey(content)
+		if err != nil {
+			// ParseAuthorizedKey skips invalid lines, an error means no
+			// further keys were found
+			break
+		}
+		content = rest
+
+		policy, err := parseKeyPolicy(options)
+		if err != nil {
+			log.Warnf("Ignoring %s key from %s: %v", key.Type(), source, err)
+			continue
+		}
+		keys = append(keys, authorizedKey{key: key, policy: policy})
+	}
+
+	return keys
+}
+
+// publicKeyHandler accepts keys found in the authorized keys and attaches
+// their policy to the connection permissions.
+func (a *authorizedKeys) publicKeyHandler(ctx ssh.Context, key ssh.PublicKey) bool {
+	policy, ok := a.lookup(key)
+	if !ok {
+		return false
+	}
+
+	encoded, err := json.Marshal(policy)
+	if err != nil {
+		log.Errorf("Failed to encode key policy: %v", err)
+		return false
+	}
+
+	// The permissions returned for each key are cached by the handshake and
+	// the ones of the key the client finally authenticates with end up on the
+	// connection, so each key needs its own permissions
+	ctx.Permissions().Permissions = &gossh.Permissions{
+		Extensions: map[string]string{keyPolicyExtension: string(encoded)},
+	}
+
+	return true
+}
+
+// policyFromContext returns the policy of the key the connection
+// authenticated with. Connections without one are allowed nothing.
+func policyFromContext(ctx ssh.Context) KeyPolicy {
+	conn, ok := ctx.Value(ssh.ContextKeyConn).(*gossh.ServerConn)
+	if !ok || conn.Permissions == nil {
+		return KeyPolicy{}
+	}
+
+	var policy KeyPolicy
+	if err := json.Unmarshal([]byte(conn.Permissions.Extensions[keyPolicyExtension]), &policy); err != nil {
+		return KeyPolicy{}
+	}
+	return policy
+}
@@ -0,0 +1,60 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package ssh
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	gossh "golang.org/x/crypto/ssh"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// loadOrCreateHostKey loads the host key at path, generating an ed25519 key
+// the first time. Keeping the key across restarts stops clients from seeing
+// a changed host key every time the daemon starts.
+func loadOrCreateHostKey(path string) (gossh.Signer, error) {
+	content, err := os.ReadFile(path)
+	if err == nil {
+		signer, err := gossh.ParsePrivateKey(content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse host key %s: %w", path, err)
+		}
+		return signer, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read host key %s: %w", path, err)
+	}
+
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate host key: %w", err)
+	}
+
+	block, err := gossh.MarshalPrivateKey(privateKey, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode host key: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create host key directory: %w", err)
+	}
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), 0600); err != nil {
+		return nil, fmt.Errorf("failed to write host key %s: %w", path, err)
+	}
+
+	signer, err := gossh.NewSignerFromKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Generated SSH host key %s (%s)", path, gossh.FingerprintSHA256(signer.PublicKey()))
+	return signer, nil
+}
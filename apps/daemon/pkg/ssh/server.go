@@ -10,6 +10,7 @@ import (
 	"os/exec"
 
 	"github.com/daytonaio/daemon/pkg/common"
+	"github.com/daytonaio/daemon/pkg/recording"
 	"github.com/daytonaio/daemon/pkg/ssh/config"
 	"github.com/gliderlabs/ssh"
 	"github.com/pkg/sftp"
@@ -21,14 +22,39 @@ import (
 type Server struct {
 	ProjectDir        string
 	DefaultProjectDir string
+	// PTY sessions are recorded to the store when set
+	Recordings *recording.Store
+	// Defaults to config.SSH_PORT
+	Port int
+	// authorized_keys file read on every authentication attempt
+	AuthorizedKeysPath string
+	// authorized_keys entries injected when the sandbox was created
+	AuthorizedKeys string
+	// Generated on first start when missing
+	HostKeyPath string
 }
 
 func (s *Server) Start() error {
+	port := s.Port
+	if port == 0 {
+		port = config.SSH_PORT
+	}
+
+	hostKey, err := loadOrCreateHostKey(s.HostKeyPath)
+	if err != nil {
+		return err
+	}
+
+	authorizedKeys := newAuthorizedKeys(s.AuthorizedKeysPath, s.AuthorizedKeys)
 	forwardedTCPHandler := &ssh.ForwardedTCPHandler{}
 	unixForwardHandler := newForwardedUnixHandler()
 
 	sshServer := ssh.Server{
-		Addr: fmt.Sprintf(":%d", config.SSH_PORT),
+		Addr:             fmt.Sprintf(":%d", port),
+		PublicKeyHandler: authorizedKeys.publicKeyHandler,
+		PtyCallback: func(ctx ssh.Context, pty ssh.Pty) bool {
+			return policyFromContext(ctx).Pty
+		},
 		Handler: func(session ssh.Session) {
 			switch ss := session.Subsystem(); ss {
 			case "":
@@ -63,17 +89,19 @@ func (s *Server) Start() error {
 			"sftp": s.sftpHandler,
 		},
 		LocalPortForwardingCallback: ssh.LocalPortForwardingCallback(func(ctx ssh.Context, dhost string, dport uint32) bool {
-			return true
+			return policyFromContext(ctx).AllowsLocalForwarding(dhost, dport)
 		}),
 		ReversePortForwardingCallback: ssh.ReversePortForwardingCallback(func(ctx ssh.Context, host string, port uint32) bool {
-			return true
+			return policyFromContext(ctx).AllowsRemoteForwarding(host, port)
 		}),
 		SessionRequestCallback: func(sess ssh.Session, requestType string) bool {
 			return true
 		},
 	}
 
-	log.Printf("Starting ssh server on port %d...\n", config.SSH_PORT)
+	sshServer.AddHostKey(hostKey)
+
+	log.Printf("Starting ssh server on port %d...\n", port)
 	return sshServer.ListenAndServe()
 }
 
@@ -86,7 +114,7 @@ func (s *Server) handlePty(session ssh.Session, ptyReq ssh.Pty, winCh <-chan ssh
 
 	env := []string{}
 
-	if ssh.AgentRequested(session) {
+	if ssh.AgentRequested(session) && policyFromContext(session.Context()).AgentForwarding {
 		l, err := ssh.NewAgentListener()
 		if err != nil {
 			log.Errorf("Failed to start agent listener: %v", err)
@@ -97,10 +125,23 @@ func (s *Server) handlePty(session ssh.Session, ptyReq ssh.Pty, winCh <-chan ssh
 		env = append(env, fmt.Sprintf("%s=%s", "SSH_AUTH_SOCK", l.Addr().String()))
 	}
 
+	recorder, err := s.Recordings.Start(recording.StartOptions{
+		Title:   fmt.Sprintf("ssh: %s", session.User()),
+		Command: common.GetShell(),
+		Width:   ptyReq.Window.Width,
+		Height:  ptyReq.Window.Height,
+		Env:     map[string]string{"TERM": ptyReq.Term},
+	})
+	if err != nil {
+		log.Errorf("Failed to start recording ssh session: %v", err)
+	}
+	defer recorder.Close()
+
 	sizeCh := make(chan common.TTYSize)
 
 	go func() {
 		for win := range winCh {
+			recorder.Resize(win.Width, win.Height)
 			sizeCh <- common.TTYSize{
 				Height: win.Height,
 				Width:  win.Width,
@@ -108,10 +149,10 @@ func (s *Server) handlePty(session ssh.Session, ptyReq ssh.Pty, winCh <-chan ssh
 		}
 	}()
 
-	err := common.SpawnTTY(common.SpawnTTYOptions{
+	err = common.SpawnTTY(common.SpawnTTYOptions{
 		Dir:    dir,
-		StdIn:  session,
-		StdOut: session,
+		StdIn:  io.TeeReader(session, recorder.InputWriter()),
+		StdOut: io.MultiWriter(session, recorder.OutputWriter()),
 		Term:   ptyReq.Term,
 		Env:    env,
 		SizeCh: sizeCh,
@@ -135,7 +176,7 @@ func (s *Server) handleNonPty(session ssh.Session) {
 
 	cmd.Env = append(cmd.Env, os.Environ()...)
 
-	if ssh.AgentRequested(session) {
+	if ssh.AgentRequested(session) && policyFromContext(session.Context()).AgentForwarding {
 		l, err := ssh.NewAgentListener()
 		if err != nil {
 			log.Errorf("Failed to start agent listener: %v", err)
@@ -0,0 +1,141 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package ssh_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/daytonaio/daemon/pkg/ssh"
+	"github.com/stretchr/testify/require"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+func newSigner(t *testing.T) gossh.Signer {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer, err := gossh.NewSignerFromKey(privateKey)
+	require.NoError(t, err)
+	return signer
+}
+
+func authorizedKeyLine(options string, signer gossh.Signer) string {
+	line := strings.TrimSpace(string(gossh.MarshalAuthorizedKey(signer.PublicKey())))
+	if options != "" {
+		line = options + " " + line
+	}
+	return line + "\n"
+}
+
+// startServer starts an SSH server with the given authorized keys, written to
+// the authorized_keys file and injected, and returns its address.
+func startServer(t *testing.T, fileKeys, injectedKeys string) string {
+	dir := t.TempDir()
+	authorizedKeysPath := filepath.Join(dir, "authorized_keys")
+	require.NoError(t, os.WriteFile(authorizedKeysPath, []byte(fileKeys), 0600))
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	require.NoError(t, listener.Close())
+
+	server := &ssh.Server{
+		ProjectDir:         dir,
+		DefaultProjectDir:  dir,
+		Port:               port,
+		AuthorizedKeysPath: authorizedKeysPath,
+		AuthorizedKeys:     injectedKeys,
+		HostKeyPath:        filepath.Join(dir, "host_key"),
+	}
+	go func() {
+		_ = server.Start()
+	}()
+
+	addr := fmt.Sprintf("127.0.0.1:%d", port)
+	require.Eventually(t, func() bool {
+		conn, err := net.Dial("tcp", addr)
+		if err != nil {
+			return false
+		}
+		conn.Close()
+		return true
+	}, 5*time.Second, 50*time.Millisecond)
+
+	return addr
+}
+
+func connect(addr string, signer gossh.Signer) (*gossh.Client, error) {
+	return gossh.Dial("tcp", addr, &gossh.ClientConfig{
+		User:            "daytona",
+		Auth:            []gossh.AuthMethod{gossh.PublicKeys(signer)},
+		HostKeyCallback: gossh.InsecureIgnoreHostKey(),
+		Timeout:         5 * time.Second,
+	})
+}
+
+func TestServer_PublicKeyAuth(t *testing.T) {
+	fileKey := newSigner(t)
+	injectedKey := newSigner(t)
+	unknownKey := newSigner(t)
+
+	addr := startServer(t, authorizedKeyLine("", fileKey), authorizedKeyLine("", injectedKey))
+
+	_, err := connect(addr, unknownKey)
+	require.Error(t, err)
+
+	for _, signer := range []gossh.Signer{fileKey, injectedKey} {
+		client, err := connect(addr, signer)
+		require.NoError(t, err)
+
+		session, err := client.NewSession()
+		require.NoError(t, err)
+		output, err := session.Output("echo hello")
+		require.NoError(t, err)
+		require.Equal(t, "hello\n", string(output))
+
+		client.Close()
+	}
+}
+
+func TestServer_KeyPolicyRestrictsForwarding(t *testing.T) {
+	restrictedKey := newSigner(t)
+	openKey := newSigner(t)
+
+	target, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer target.Close()
+	go func() {
+		for {
+			conn, err := target.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	addr := startServer(t, authorizedKeyLine("restrict", restrictedKey)+authorizedKeyLine("", openKey), "")
+
+	restricted, err := connect(addr, restrictedKey)
+	require.NoError(t, err)
+	defer restricted.Close()
+
+	_, err = restricted.Dial("tcp", target.Addr().String())
+	require.Error(t, err)
+
+	open, err := connect(addr, openKey)
+	require.NoError(t, err)
+	defer open.Close()
+
+	conn, err := open.Dial("tcp", target.Addr().String())
+	require.NoError(t, err)
+	conn.Close()
+}
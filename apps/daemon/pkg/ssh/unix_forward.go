@@ -62,6 +62,11 @@ func (h *forwardedUnixHandler) HandleSSHRequest(ctx ssh.Context, _ *ssh.Server,
 
 	switch req.Type {
 	case "streamlocal-forward@openssh.com":
+		if !policyFromContext(ctx).PortForwarding {
+			log.Warn(ctx, "SSH unix forward request denied by key policy")
+			return false, nil
+		}
+
 		var reqPayload streamLocalForwardPayload
 		err := gossh.Unmarshal(req.Payload, &reqPayload)
 		if err != nil {
@@ -211,6 +216,11 @@ type directStreamLocalPayload struct {
 }
 
 func directStreamLocalHandler(_ *ssh.Server, _ *gossh.ServerConn, newChan gossh.NewChannel, ctx ssh.Context) {
+	if !policyFromContext(ctx).PortForwarding {
+		_ = newChan.Reject(gossh.Prohibited, "port forwarding is not permitted for this key")
+		return
+	}
+
 	var reqPayload directStreamLocalPayload
 	err := gossh.Unmarshal(newChan.ExtraData(), &reqPayload)
 	if err != nil {
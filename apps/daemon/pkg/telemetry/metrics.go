@@ -0,0 +1,85 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package telemetry
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	ExecutionKindExecute = "execute"
+	ExecutionKindSession = "session"
+
+	DirectionUpload   = "upload"
+	DirectionDownload = "download"
+)
+
+var (
+	// Histogram to track toolbox API latency per route
+	RequestDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "daytona_daemon_http_request_duration_seconds",
+			Help:    "Time taken to handle toolbox API requests in seconds",
+			Buckets: []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300},
+		},
+		[]string{"method", "route", "status"},
+	)
+
+	// Gauge to track commands currently being executed through the toolbox
+	ExecutionsInFlight = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "daytona_daemon_process_executions_in_flight",
+			Help: "Number of commands currently being executed",
+		},
+		[]string{"kind"},
+	)
+
+	// Gauge to track open process sessions
+	ProcessSessions = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "daytona_daemon_process_sessions",
+			Help: "Number of open process sessions",
+		},
+	)
+
+	// Counter to track file contents transferred through the file system API
+	FsBytesTransferred = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "daytona_daemon_fs_bytes_transferred_total",
+			Help: "Total number of bytes uploaded and downloaded through the file system API",
+		},
+		[]string{"direction"},
+	)
+)
+
+// LabeledValue is a single sample of a gauge computed at scrape time.
+type LabeledValue struct {
+	Labels []string
+	Value  float64
+}
+
+type gaugeVecFunc struct {
+	desc    *prometheus.Desc
+	collect func() []LabeledValue
+}
+
+// RegisterGaugeVecFunc registers a labeled gauge whose values are computed by
+// collect on every scrape, for state that is already tracked elsewhere.
+func RegisterGaugeVecFunc(name, help string, labels []string, collect func() []LabeledValue) {
+	prometheus.MustRegister(&gaugeVecFunc{
+		desc:    prometheus.NewDesc(name, help, labels, nil),
+		collect: collect,
+	})
+}
+
+func (g *gaugeVecFunc) Describe(ch chan<- *prometheus.Desc) {
+	ch <- g.desc
+}
+
+func (g *gaugeVecFunc) Collect(ch chan<- prometheus.Metric) {
+	for _, value := range g.collect() {
+		ch <- prometheus.MustNewConstMetric(g.desc, prometheus.GaugeValue, value.Value, value.Labels...)
+	}
+}
@@ -4,121 +4,295 @@
 package terminal
 
 import (
+	"crypto/subtle"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"io/fs"
-	"log"
 	"net/http"
+	"net/url"
+	"strings"
 
-	"github.com/daytonaio/daemon/pkg/common"
+	"github.com/daytonaio/daemon/pkg/recording"
 	"github.com/gorilla/websocket"
-)
 
-var upgrader = websocket.Upgrader{
-	CheckOrigin: func(r *http.Request) bool {
-		return true // Be careful with this in production
-	},
-}
+	log "github.com/sirupsen/logrus"
+)
 
 type windowSize struct {
 	Rows uint16 `json:"rows"`
 	Cols uint16 `json:"cols"`
 }
 
-func StartTerminalServer(port int) error {
-	// Prepare the embedded frontend files
-	// Serve the files from the embedded filesystem
-	staticFS, err := fs.Sub(static, "static")
+type createSessionRequest struct {
+	Name string `json:"name"`
+}
+
+type ServerConfig struct {
+	Port int
+	// Token required on every API and WebSocket request. Auth is disabled
+	// when empty.
+	Token string
+	// Origins allowed to open WebSocket connections in addition to the
+	// server's own. "*" allows any origin.
+	AllowedOrigins []string
+	// Working directory of new sessions
+	Dir string
+	// Sessions are recorded to the store when set
+	Recordings *recording.Store
+}
+
+type Server struct {
+	config   ServerConfig
+	sessions *SessionManager
+	upgrader websocket.Upgrader
+}
+
+func NewServer(config ServerConfig) *Server {
+	if config.Dir == "" {
+		config.Dir = "/"
+	}
+
+	s := &Server{
+		config:   config,
+		sessions: NewSessionManager(config.Dir, config.Recordings),
+	}
+	s.upgrader = websocket.Upgrader{
+		CheckOrigin: s.checkOrigin,
+	}
+
+	return s
+}
+
+func StartTerminalServer(config ServerConfig) error {
+	return NewServer(config).Start()
+}
+
+func (s *Server) Sessions() *SessionManager {
+	return s.sessions
+}
+
+func (s *Server) Start() error {
+	handler, err := s.Handler()
 	if err != nil {
 		return err
 	}
 
-	http.Handle("/", http.FileServer(http.FS(staticFS)))
-	http.HandleFunc("/ws", handleWebSocket)
+	if s.config.Token == "" {
+		log.Warn("Terminal server token is not set, terminal sessions are not authenticated")
+	}
+
+	addr := fmt.Sprintf(":%d", s.config.Port)
+	log.Infof("Starting terminal server on http://localhost%s", addr)
+	return http.ListenAndServe(addr, handler)
+}
+
+func (s *Server) Handler() (http.Handler, error) {
+	// Serve the frontend from the embedded filesystem
+	staticFS, err := fs.Sub(static, "static")
+	if err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/", http.FileServer(http.FS(staticFS)))
+	mux.HandleFunc("GET /ws", s.authenticated(s.handleWebSocket))
+	mux.HandleFunc("GET /sessions", s.authenticated(s.listSessions))
+	mux.HandleFunc("POST /sessions", s.authenticated(s.createSession))
+	mux.HandleFunc("DELETE /sessions/{id}", s.authenticated(s.killSession))
+
+	return mux, nil
+}
+
+func (s *Server) authenticated(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.config.Token == "" {
+			next(w, r)
+			return
+		}
+
+		// Browsers cannot set headers on WebSocket requests so the token may
+		// also be passed as a query parameter
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if token == "" {
+			token = r.URL.Query().Get("token")
+		}
+
+		if subtle.ConstantTimeCompare([]byte(token), []byte(s.config.Token)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+func (s *Server) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+
+	if strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	if forwardedHost := r.Header.Get("X-Forwarded-Host"); forwardedHost != "" && strings.EqualFold(u.Host, forwardedHost) {
+		return true
+	}
+
+	for _, allowed := range s.config.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Server) listSessions(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.sessions.List())
+}
+
+func (s *Server) createSession(w http.ResponseWriter, r *http.Request) {
+	var req createSessionRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("invalid request body: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	session, err := s.sessions.Create(req.Name)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to start session: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, session.Info())
+}
+
+func (s *Server) killSession(w http.ResponseWriter, r *http.Request) {
+	err := s.sessions.Kill(r.PathValue("id"))
+	if errors.Is(err, ErrSessionNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to kill session: %v", err), http.StatusInternalServerError)
+		return
+	}
 
-	addr := fmt.Sprintf(":%d", port)
-	log.Printf("Starting terminal server on http://localhost%s", addr)
-	return http.ListenAndServe(addr, nil)
+	w.WriteHeader(http.StatusNoContent)
 }
 
-func handleWebSocket(w http.ResponseWriter, r *http.Request) {
-	conn, err := upgrader.Upgrade(w, r, nil)
+// handleWebSocket attaches a viewer to the session given by the session query
+// parameter. Without one, a new session is started that is killed when the
+// connection closes. Viewers attached with mode=read-only receive output but
+// cannot send input or resize the terminal.
+func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	readOnly := query.Get("mode") == "read-only"
+
+	var session *Session
+	var err error
+	ephemeral := false
+
+	if id := query.Get("session"); id != "" {
+		session, err = s.sessions.Get(id)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+	} else {
+		session, err = s.sessions.Create(query.Get("name"))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to start session: %v", err), http.StatusInternalServerError)
+			return
+		}
+		ephemeral = true
+	}
+
+	conn, err := s.upgrader.Upgrade(w, r, nil)
 	if err != nil {
-		log.Printf("Failed to upgrade connection: %v", err)
+		log.Errorf("Failed to upgrade connection: %v", err)
+		if ephemeral {
+			_ = session.Kill()
+		}
 		return
 	}
 	defer conn.Close()
 
-	// Create a new UTF8Decoder instance for this connection
-	decoder := NewUTF8Decoder()
+	v, scrollback := session.attach(readOnly)
+	defer session.detach(v)
 
-	sizeCh := make(chan common.TTYSize)
-	stdInReader, stdInWriter := io.Pipe()
-	stdOutReader, stdOutWriter := io.Pipe()
+	if ephemeral {
+		defer func() {
+			if err := session.Kill(); err != nil {
+				log.Errorf("Failed to kill terminal session %s: %v", session.id, err)
+			}
+		}()
+	}
 
 	// Handle websocket -> pty
 	go func() {
+		defer session.detach(v)
+
 		for {
 			messageType, p, err := conn.ReadMessage()
 			if err != nil {
 				return
 			}
 
+			if v.readOnly {
+				continue
+			}
+
 			// Check if it's a resize message
 			if messageType == websocket.TextMessage {
 				var size windowSize
 				if err := json.Unmarshal(p, &size); err == nil {
-					sizeCh <- common.TTYSize{
-						Height: int(size.Rows),
-						Width:  int(size.Cols),
+					if err := session.Resize(size.Rows, size.Cols); err != nil {
+						log.Errorf("Failed to resize terminal session %s: %v", session.id, err)
 					}
 					continue
 				}
 			}
 
-			// Write to pty
-			_, err = stdInWriter.Write(p)
-			if err != nil {
+			if _, err := session.Write(p); err != nil {
 				return
 			}
 		}
 	}()
 
-	go func() {
-		// Handle pty -> websocket
-		buf := make([]byte, 1024)
-		for {
-			n, err := stdOutReader.Read(buf)
-			if err != nil {
-				if err != io.EOF {
-					log.Printf("Failed to read from pty: %v", err)
-				}
-				return
-			}
+	// Handle pty -> websocket
+	// A multi-byte UTF-8 character can be split across stream reads.
+	// UTF8Decoder buffers incomplete sequences to ensure proper decoding.
+	decoder := NewUTF8Decoder()
 
-			// A multi-byte UTF-8 character can be split across stream reads.
-			// UTF8Decoder buffers incomplete sequences to ensure proper decoding.
-			decoded := decoder.Write(buf[:n])
+	if len(scrollback) > 0 {
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(decoder.Write(scrollback))); err != nil {
+			return
+		}
+	}
 
-			err = conn.WriteMessage(websocket.TextMessage, []byte(decoded))
-			if err != nil {
-				log.Printf("Failed to write to websocket: %v", err)
-				return
-			}
+	for chunk := range v.output {
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(decoder.Write(chunk))); err != nil {
+			return
 		}
-	}()
+	}
 
-	// Create a pty
-	err = common.SpawnTTY(common.SpawnTTYOptions{
-		Dir:    "/",
-		StdIn:  stdInReader,
-		StdOut: stdOutWriter,
-		Term:   "xterm-256color",
-		SizeCh: sizeCh,
-	})
-	if err != nil {
-		log.Printf("Failed to start pty: %v", err)
-		return
+	_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Errorf("Failed to write response: %v", err)
 	}
 }
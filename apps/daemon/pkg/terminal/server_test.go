@@ -0,0 +1,104 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package terminal_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/daytonaio/daemon/pkg/terminal"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+const testToken = "secret"
+
+func newTestServer(t *testing.T) (*terminal.Server, *httptest.Server) {
+	server := terminal.NewServer(terminal.ServerConfig{Token: testToken, Dir: t.TempDir()})
+	handler, err := server.Handler()
+	require.NoError(t, err)
+
+	httpServer := httptest.NewServer(handler)
+	t.Cleanup(httpServer.Close)
+
+	return server, httpServer
+}
+
+func dial(t *testing.T, httpServer *httptest.Server, query string) *websocket.Conn {
+	url := "ws" + strings.TrimPrefix(httpServer.URL, "http") + "/ws?" + query
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// readUntil reads output from the connection until it contains want.
+func readUntil(t *testing.T, conn *websocket.Conn, want string) string {
+	var output strings.Builder
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(10*time.Second)))
+	for !strings.Contains(output.String(), want) {
+		_, p, err := conn.ReadMessage()
+		require.NoError(t, err, "output so far: %q", output.String())
+		output.Write(p)
+	}
+	return output.String()
+}
+
+func TestServer_RequiresToken(t *testing.T) {
+	_, httpServer := newTestServer(t)
+
+	resp, err := http.Get(httpServer.URL + "/sessions")
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	url := "ws" + strings.TrimPrefix(httpServer.URL, "http") + "/ws"
+	_, resp, err = websocket.DefaultDialer.Dial(url, nil)
+	require.Error(t, err)
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestServer_SharedSessionSurvivesReconnect(t *testing.T) {
+	server, httpServer := newTestServer(t)
+
+	req, err := http.NewRequest(http.MethodPost, httpServer.URL+"/sessions", strings.NewReader(`{"name":"build"}`))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var info terminal.SessionInfo
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&info))
+	require.Equal(t, "build", info.Name)
+	t.Cleanup(func() { _ = server.Sessions().Kill(info.Id) })
+
+	writer := dial(t, httpServer, "token="+testToken+"&session="+info.Id)
+	watcher := dial(t, httpServer, "token="+testToken+"&session="+info.Id+"&mode=read-only")
+
+	// Input from read-only viewers is ignored
+	require.NoError(t, watcher.WriteMessage(websocket.TextMessage, []byte("echo ignored-$((1+1))\n")))
+	require.NoError(t, writer.WriteMessage(websocket.TextMessage, []byte("echo shared-$((20+22))\n")))
+
+	readUntil(t, watcher, "shared-42")
+	readUntil(t, writer, "shared-42")
+
+	// The session keeps running after every viewer disconnects and its
+	// scrollback is replayed on reattach
+	writer.Close()
+	watcher.Close()
+
+	reattached := dial(t, httpServer, "token="+testToken+"&session="+info.Id)
+	output := readUntil(t, reattached, "shared-42")
+	require.NotContains(t, output, "ignored-2")
+
+	sessions := server.Sessions().List()
+	require.Len(t, sessions, 1)
+	require.Equal(t, info.Id, sessions[0].Id)
+}
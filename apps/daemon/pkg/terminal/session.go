@@ -0,0 +1,325 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package terminal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/creack/pty"
+	"github.com/daytonaio/daemon/pkg/common"
+	"github.com/daytonaio/daemon/pkg/recording"
+	"github.com/google/uuid"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// output kept per session and replayed to viewers when they attach
+	scrollbackSize = 256 * 1024
+	// output chunks buffered per viewer before it is disconnected as too slow
+	viewerBufferSize = 256
+)
+
+var ErrSessionNotFound = errors.New("session not found")
+
+type SessionInfo struct {
+	Id        string    `json:"id"`
+	Name      string    `json:"name,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	Viewers   int       `json:"viewers"`
+	Rows      uint16    `json:"rows"`
+	Cols      uint16    `json:"cols"`
+}
+
+// Session is a shell running in a PTY that outlives the connections viewing
+// it. Output is fanned out to every attached viewer and kept in a scrollback
+// buffer for viewers attaching later.
+type Session struct {
+	id        string
+	name      string
+	createdAt time.Time
+
+	cmd      *exec.Cmd
+	ptmx     *os.File
+	done     chan struct{}
+	recorder *recording.Recorder
+
+	mu         sync.Mutex
+	scrollback []byte
+	viewers    map[*viewer]struct{}
+	size       pty.Winsize
+}
+
+type viewer struct {
+	output   chan []byte
+	readOnly bool
+}
+
+func startSession(name, dir string, recordings *recording.Store) (*Session, error) {
+	shell := common.GetShell()
+	cmd := exec.Command(shell)
+	cmd.Dir = dir
+
+	cmd.Env = append(cmd.Env, "TERM=xterm-256color")
+	cmd.Env = append(cmd.Env, os.Environ()...)
+	cmd.Env = append(cmd.Env, fmt.Sprintf("SHELL=%s", shell))
+
+	size := pty.Winsize{Rows: 24, Cols: 80}
+	ptmx, err := pty.StartWithSize(cmd, &size)
+	if err != nil {
+		return nil, err
+	}
+
+	title := "terminal"
+	if name != "" {
+		title = fmt.Sprintf("terminal: %s", name)
+	}
+
+	recorder, err := recordings.Start(recording.StartOptions{
+		Title:   title,
+		Command: shell,
+		Width:   int(size.Cols),
+		Height:  int(size.Rows),
+		Env:     map[string]string{"SHELL": shell, "TERM": "xterm-256color"},
+	})
+	if err != nil {
+		// Sessions are still usable when they cannot be recorded
+		log.Errorf("Failed to start recording terminal session: %v", err)
+	}
+
+	s := &Session{
+		id:        uuid.NewString(),
+		name:      name,
+		createdAt: time.Now(),
+		cmd:       cmd,
+		ptmx:      ptmx,
+		done:      make(chan struct{}),
+		recorder:  recorder,
+		viewers:   make(map[*viewer]struct{}),
+		size:      size,
+	}
+
+	go s.readOutput()
+
+	return s, nil
+}
+
+func (s *Session) readOutput() {
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := s.ptmx.Read(buf)
+		if n > 0 {
+			s.broadcast(buf[:n])
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	_ = s.cmd.Wait()
+	s.ptmx.Close()
+	if err := s.recorder.Close(); err != nil {
+		log.Errorf("Failed to close recording %s: %v", s.recorder.Id(), err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for v := range s.viewers {
+		close(v.output)
+		delete(s.viewers, v)
+	}
+	close(s.done)
+}
+
+func (s *Session) broadcast(data []byte) {
+	chunk := make([]byte, len(data))
+	copy(chunk, data)
+
+	s.recorder.Output(chunk)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.scrollback = append(s.scrollback, chunk...)
+	if len(s.scrollback) > scrollbackSize {
+		s.scrollback = s.scrollback[len(s.scrollback)-scrollbackSize:]
+	}
+
+	for v := range s.viewers {
+		select {
+		case v.output <- chunk:
+		default:
+			// Disconnect viewers that cannot keep up, they can reattach and
+			// catch up from the scrollback
+			close(v.output)
+			delete(s.viewers, v)
+		}
+	}
+}
+
+// attach registers a viewer and returns the scrollback to replay to it.
+// Output produced after the snapshot is delivered to the viewer's channel.
+func (s *Session) attach(readOnly bool) (*viewer, []byte) {
+	v := &viewer{
+		output:   make(chan []byte, viewerBufferSize),
+		readOnly: readOnly,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	select {
+	case <-s.done:
+		close(v.output)
+	default:
+		s.viewers[v] = struct{}{}
+	}
+
+	scrollback := make([]byte, len(s.scrollback))
+	copy(scrollback, s.scrollback)
+
+	return v, scrollback
+}
+
+func (s *Session) detach(v *viewer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.viewers[v]; ok {
+		close(v.output)
+		delete(s.viewers, v)
+	}
+}
+
+func (s *Session) Write(p []byte) (int, error) {
+	s.recorder.Input(p)
+	return s.ptmx.Write(p)
+}
+
+func (s *Session) Resize(rows, cols uint16) error {
+	if rows == 0 || cols == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.size = pty.Winsize{Rows: rows, Cols: cols}
+	s.recorder.Resize(int(cols), int(rows))
+	return pty.Setsize(s.ptmx, &s.size)
+}
+
+func (s *Session) Kill() error {
+	if err := s.cmd.Process.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		return err
+	}
+
+	select {
+	case <-s.done:
+	case <-time.After(2 * time.Second):
+		// Background jobs may keep the PTY open after the shell exits
+		s.ptmx.Close()
+		<-s.done
+	}
+
+	return nil
+}
+
+func (s *Session) Info() SessionInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return SessionInfo{
+		Id:        s.id,
+		Name:      s.name,
+		CreatedAt: s.createdAt,
+		Viewers:   len(s.viewers),
+		Rows:      s.size.Rows,
+		Cols:      s.size.Cols,
+	}
+}
+
+// SessionManager keeps track of the running terminal sessions.
+type SessionManager struct {
+	dir string
+	// Sessions are recorded when set
+	recordings *recording.Store
+	mu         sync.Mutex
+	sessions   map[string]*Session
+}
+
+func NewSessionManager(dir string, recordings *recording.Store) *SessionManager {
+	return &SessionManager{
+		dir:        dir,
+		recordings: recordings,
+		sessions:   make(map[string]*Session),
+	}
+}
+
+func (m *SessionManager) Create(name string) (*Session, error) {
+	session, err := startSession(name, m.dir, m.recordings)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	m.sessions[session.id] = session
+	m.mu.Unlock()
+
+	// Forget the session once its shell exits
+	go func() {
+		<-session.done
+		m.mu.Lock()
+		delete(m.sessions, session.id)
+		m.mu.Unlock()
+	}()
+
+	return session, nil
+}
+
+func (m *SessionManager) Get(id string) (*Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	session, ok := m.sessions[id]
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	return session, nil
+}
+
+func (m *SessionManager) List() []SessionInfo {
+	m.mu.Lock()
+	sessions := make([]*Session, 0, len(m.sessions))
+	for _, session := range m.sessions {
+		sessions = append(sessions, session)
+	}
+	m.mu.Unlock()
+
+	infos := make([]SessionInfo, 0, len(sessions))
+	for _, session := range sessions {
+		infos = append(infos, session.Info())
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].CreatedAt.Before(infos[j].CreatedAt)
+	})
+
+	return infos
+}
+
+func (m *SessionManager) Kill(id string) error {
+	session, err := m.Get(id)
+	if err != nil {
+		return err
+	}
+	return session.Kill()
+}
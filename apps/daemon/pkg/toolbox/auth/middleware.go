@@ -0,0 +1,92 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TokenHeader carries the toolbox token. The runner's proxy sets it with the
+// sandbox token unless the client already passed a token.
+const TokenHeader = "X-Daytona-Toolbox-Token"
+
+const claimsKey = "toolboxClaims"
+
+// Middleware authenticates requests with the token from the TokenHeader
+// header, a bearer Authorization header or, for WebSocket clients that cannot
+// set headers, the token query parameter. The token is removed from the
+// request so that it is never logged or forwarded to proxied ports.
+func Middleware(authenticator *Authenticator) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if !authenticator.Enabled() {
+			ctx.Set(claimsKey, &Claims{Scopes: []Scope{ScopeAll}})
+			ctx.Next()
+			return
+		}
+
+		token := takeToken(ctx.Request)
+		if token == "" {
+			ctx.AbortWithError(http.StatusUnauthorized, errors.New("toolbox token required"))
+			return
+		}
+
+		claims, err := authenticator.Authenticate(token)
+		if err != nil {
+			ctx.AbortWithError(http.StatusUnauthorized, err)
+			return
+		}
+
+		ctx.Set(claimsKey, claims)
+		ctx.Next()
+	}
+}
+
+// RequireScope rejects requests whose token does not grant access to area.
+func RequireScope(area Scope) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		claims := ClaimsFromContext(ctx)
+		if claims == nil || !allows(claims.Scopes, area, ctx.Request.Method) {
+			ctx.AbortWithError(http.StatusForbidden, fmt.Errorf("token does not grant the %s scope", area))
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+func ClaimsFromContext(ctx *gin.Context) *Claims {
+	value, ok := ctx.Get(claimsKey)
+	if !ok {
+		return nil
+	}
+	claims, _ := value.(*Claims)
+	return claims
+}
+
+func takeToken(req *http.Request) string {
+	if token := req.Header.Get(TokenHeader); token != "" {
+		req.Header.Del(TokenHeader)
+		return token
+	}
+
+	if bearer, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer "); ok && bearer != "" {
+		req.Header.Del("Authorization")
+		return bearer
+	}
+
+	query := req.URL.Query()
+	if token := query.Get("token"); token != "" {
+		query.Del("token")
+		req.URL.RawQuery = query.Encode()
+		req.RequestURI = req.URL.RequestURI()
+		return token
+	}
+
+	return ""
+}
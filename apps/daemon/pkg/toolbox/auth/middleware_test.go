@@ -0,0 +1,98 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package auth_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/daytonaio/daemon/pkg/toolbox/auth"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+const sandboxToken = "sandbox-token"
+
+func newRouter(authenticator *auth.Authenticator) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.GET("/version", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.Use(auth.Middleware(authenticator))
+
+	r.POST("/auth/tokens", auth.NewTokenController(authenticator).CreateToken)
+
+	files := r.Group("/files", auth.RequireScope(auth.ScopeFs))
+	files.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+	files.DELETE("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	process := r.Group("/process", auth.RequireScope(auth.ScopeProcess))
+	process.POST("/execute", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	r.GET("/config", auth.RequireScope(auth.ScopeAll), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	return r
+}
+
+func request(r *gin.Engine, method, path, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set(auth.TokenHeader, token)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestMiddleware_RequiresToken(t *testing.T) {
+	r := newRouter(auth.NewAuthenticator(sandboxToken))
+
+	require.Equal(t, http.StatusOK, request(r, http.MethodGet, "/version", "", "").Code)
+	require.Equal(t, http.StatusUnauthorized, request(r, http.MethodGet, "/files/", "", "").Code)
+	require.Equal(t, http.StatusUnauthorized, request(r, http.MethodGet, "/files/", "wrong", "").Code)
+	require.Equal(t, http.StatusOK, request(r, http.MethodGet, "/files/", sandboxToken, "").Code)
+	require.Equal(t, http.StatusOK, request(r, http.MethodGet, "/files/?token="+sandboxToken, "", "").Code)
+}
+
+func TestMiddleware_EnforcesScopes(t *testing.T) {
+	authenticator := auth.NewAuthenticator(sandboxToken)
+	r := newRouter(authenticator)
+
+	w := request(r, http.MethodPost, "/auth/tokens", sandboxToken, `{"name":"agent","scopes":["fs:read"]}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var created auth.CreateTokenResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+
+	require.Equal(t, http.StatusOK, request(r, http.MethodGet, "/files/", created.Token, "").Code)
+	require.Equal(t, http.StatusForbidden, request(r, http.MethodDelete, "/files/", created.Token, "").Code)
+	require.Equal(t, http.StatusForbidden, request(r, http.MethodPost, "/process/execute", created.Token, "").Code)
+	require.Equal(t, http.StatusForbidden, request(r, http.MethodGet, "/config", created.Token, "").Code)
+	require.Equal(t, http.StatusOK, request(r, http.MethodGet, "/config", sandboxToken, "").Code)
+
+	// Scoped tokens can only mint narrower tokens
+	w = request(r, http.MethodPost, "/auth/tokens", created.Token, `{"scopes":["fs"]}`)
+	require.Equal(t, http.StatusForbidden, w.Code)
+
+	// Tampering with the scopes invalidates the signature
+	payload, signature, _ := strings.Cut(created.Token, ".")
+	require.Equal(t, http.StatusUnauthorized, request(r, http.MethodGet, "/files/", payload+"x."+signature, "").Code)
+}
+
+func TestAuthenticator_RejectsExpiredTokens(t *testing.T) {
+	authenticator := auth.NewAuthenticator(sandboxToken)
+
+	token, err := authenticator.Mint(auth.Claims{
+		Scopes:    []auth.Scope{auth.ScopeGit},
+		ExpiresAt: time.Now().Add(-time.Minute).Unix(),
+	})
+	require.NoError(t, err)
+
+	_, err = authenticator.Authenticate(token)
+	require.ErrorIs(t, err, auth.ErrExpiredToken)
+}
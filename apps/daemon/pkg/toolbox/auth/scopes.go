@@ -0,0 +1,80 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// A Scope grants access to a toolbox API area, e.g. "git". Appending ":read"
+// restricts it to reading, e.g. "fs:read" only allows GET requests to the
+// file system API. ScopeAll grants access to everything, routes requiring it
+// are only reachable with the sandbox token.
+type Scope = string
+
+const (
+	ScopeAll         Scope = "*"
+	ScopeFs          Scope = "fs"
+	ScopeProcess     Scope = "process"
+	ScopeGit         Scope = "git"
+	ScopeLsp         Scope = "lsp"
+	ScopeComputerUse Scope = "computeruse"
+	ScopePort        Scope = "port"
+	ScopeProxy       Scope = "proxy"
+	ScopeRecordings  Scope = "recordings"
+	ScopeMetrics     Scope = "metrics"
+
+	readSuffix = ":read"
+)
+
+var areas = map[Scope]bool{
+	ScopeFs:          true,
+	ScopeProcess:     true,
+	ScopeGit:         true,
+	ScopeLsp:         true,
+	ScopeComputerUse: true,
+	ScopePort:        true,
+	ScopeProxy:       true,
+	ScopeRecordings:  true,
+	ScopeMetrics:     true,
+}
+
+func validateScope(scope Scope) error {
+	if scope == ScopeAll || areas[strings.TrimSuffix(scope, readSuffix)] {
+		return nil
+	}
+	return fmt.Errorf("unknown scope: %s", scope)
+}
+
+// allows reports whether scopes grant a request with the given method to area.
+func allows(scopes []Scope, area Scope, method string) bool {
+	for _, scope := range scopes {
+		if scope == ScopeAll || scope == area {
+			return true
+		}
+		if scope == area+readSuffix && isReadMethod(method) {
+			return true
+		}
+	}
+	return false
+}
+
+// covers reports whether scopes grant at least everything scope grants.
+func covers(scopes []Scope, scope Scope) bool {
+	for _, s := range scopes {
+		if s == ScopeAll || s == scope {
+			return true
+		}
+		if area, ok := strings.CutSuffix(scope, readSuffix); ok && s == area {
+			return true
+		}
+	}
+	return false
+}
+
+func isReadMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
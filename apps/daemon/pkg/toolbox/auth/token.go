@@ -0,0 +1,104 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrExpiredToken = errors.New("token expired")
+)
+
+// Claims describe what a token grants.
+type Claims struct {
+	Name   string  `json:"name,omitempty"`
+	Scopes []Scope `json:"scopes"`
+	// Unix time after which the token is rejected, zero for no expiry
+	ExpiresAt int64 `json:"exp,omitempty"`
+}
+
+// Authenticator validates the sandbox token injected by the runner and the
+// scoped tokens derived from it. Scoped tokens are signed with the sandbox
+// token so that they need no state in the daemon and survive restarts.
+type Authenticator struct {
+	token []byte
+}
+
+func NewAuthenticator(token string) *Authenticator {
+	return &Authenticator{token: []byte(token)}
+}
+
+// Enabled reports whether a sandbox token is configured. Without one every
+// request is allowed.
+func (a *Authenticator) Enabled() bool {
+	return len(a.token) > 0
+}
+
+func (a *Authenticator) Authenticate(token string) (*Claims, error) {
+	if subtle.ConstantTimeCompare([]byte(token), a.token) == 1 {
+		return &Claims{Scopes: []Scope{ScopeAll}}, nil
+	}
+
+	encodedPayload, encodedSignature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, a.sign(encodedPayload)) {
+		return nil, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if claims.ExpiresAt != 0 && time.Now().Unix() >= claims.ExpiresAt {
+		return nil, ErrExpiredToken
+	}
+
+	return &claims, nil
+}
+
+// Mint returns a scoped token granting claims.
+func (a *Authenticator) Mint(claims Claims) (string, error) {
+	if !a.Enabled() {
+		return "", errors.New("toolbox authentication is disabled")
+	}
+
+	for _, scope := range claims.Scopes {
+		if err := validateScope(scope); err != nil {
+			return "", err
+		}
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	encodedPayload := base64.RawURLEncoding.EncodeToString(payload)
+	return encodedPayload + "." + base64.RawURLEncoding.EncodeToString(a.sign(encodedPayload)), nil
+}
+
+func (a *Authenticator) sign(payload string) []byte {
+	mac := hmac.New(sha256.New, a.token)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
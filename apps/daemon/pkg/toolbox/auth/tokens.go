@@ -0,0 +1,99 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const defaultTokenTTL = time.Hour
+
+type CreateTokenRequest struct {
+	Name   string   `json:"name" validate:"optional"`
+	Scopes []string `json:"scopes" validate:"required"`
+	// Seconds until the token expires, defaults to an hour
+	ExpiresIn int64 `json:"expiresIn" validate:"optional"`
+} // @name CreateTokenRequest
+
+type CreateTokenResponse struct {
+	Token     string    `json:"token" validate:"required"`
+	Scopes    []string  `json:"scopes" validate:"required"`
+	ExpiresAt time.Time `json:"expiresAt" validate:"required"`
+} // @name CreateTokenResponse
+
+type TokenController struct {
+	authenticator *Authenticator
+}
+
+func NewTokenController(authenticator *Authenticator) *TokenController {
+	return &TokenController{
+		authenticator: authenticator,
+	}
+}
+
+// CreateToken mints a scoped token. Tokens can only be narrowed: the new
+// token is granted no scope the caller's token lacks and does not outlive it.
+func (t *TokenController) CreateToken(c *gin.Context) {
+	if !t.authenticator.Enabled() {
+		c.AbortWithError(http.StatusServiceUnavailable, errors.New("toolbox authentication is disabled"))
+		return
+	}
+
+	var req CreateTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithError(http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	if len(req.Scopes) == 0 {
+		c.AbortWithError(http.StatusBadRequest, errors.New("at least one scope is required"))
+		return
+	}
+	if req.ExpiresIn < 0 {
+		c.AbortWithError(http.StatusBadRequest, errors.New("expiresIn must be positive"))
+		return
+	}
+
+	caller := ClaimsFromContext(c)
+	for _, scope := range req.Scopes {
+		if err := validateScope(scope); err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+		if caller == nil || !covers(caller.Scopes, scope) {
+			c.AbortWithError(http.StatusForbidden, fmt.Errorf("token does not grant the %s scope", scope))
+			return
+		}
+	}
+
+	ttl := defaultTokenTTL
+	if req.ExpiresIn > 0 {
+		ttl = time.Duration(req.ExpiresIn) * time.Second
+	}
+	expiresAt := time.Now().Add(ttl)
+	if caller.ExpiresAt != 0 && expiresAt.Unix() > caller.ExpiresAt {
+		expiresAt = time.Unix(caller.ExpiresAt, 0)
+	}
+
+	token, err := t.authenticator.Mint(Claims{
+		Name:      req.Name,
+		Scopes:    req.Scopes,
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, CreateTokenResponse{
+		Token:     token,
+		Scopes:    req.Scopes,
+		ExpiresAt: time.Unix(expiresAt.Unix(), 0),
+	})
+}
@@ -6,7 +6,9 @@ package computeruse
 import (
 	"net/http"
 	"net/rpc"
+	"path/filepath"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/hashicorp/go-plugin"
@@ -46,6 +48,34 @@ type IComputerUse interface {
 	GetDisplayInfo() (*DisplayInfoResponse, error)
 	GetWindows() (*WindowsResponse, error)
 
+	// Window management methods
+	FocusWindow(*WindowRequest) (*WindowInfo, error)
+	MoveWindow(*MoveWindowRequest) (*WindowInfo, error)
+	ResizeWindow(*ResizeWindowRequest) (*WindowInfo, error)
+	MinimizeWindow(*WindowRequest) (*WindowInfo, error)
+	MaximizeWindow(*WindowRequest) (*WindowInfo, error)
+	CloseWindow(*WindowRequest) (*Empty, error)
+	LaunchApplication(*LaunchApplicationRequest) (*LaunchApplicationResponse, error)
+
+	// Clipboard methods
+	GetClipboard(*GetClipboardRequest) (*ClipboardResponse, error)
+	SetClipboard(*SetClipboardRequest) (*Empty, error)
+
+	// Accessibility methods
+	GetAccessibilityTree(*AccessibilityTreeRequest) (*AccessibilityTreeResponse, error)
+	InvokeAccessibilityAction(*AccessibilityActionRequest) (*AccessibilityNode, error)
+
+	// Screen change methods
+	Diff(*DiffRequest) (*DiffResponse, error)
+	WaitForChange(*WaitForChangeRequest) (*WaitForScreenResponse, error)
+	WaitForStable(*WaitForStableRequest) (*WaitForScreenResponse, error)
+
+	// Screen recording methods
+	StartScreenRecording(*StartScreenRecordingRequest) (*ScreenRecording, error)
+	StopScreenRecording(*ScreenRecordingRequest) (*ScreenRecording, error)
+	ListScreenRecordings() (*ScreenRecordingsResponse, error)
+	GetScreenRecording(*ScreenRecordingRequest) (*ScreenRecording, error)
+
 	// Status method
 	GetStatus() (*StatusResponse, error)
 }
@@ -180,6 +210,190 @@ type WindowInfo struct {
 	IsActive bool `json:"isActive"`
 }
 
+// Window management structs
+type WindowRequest struct {
+	ID int `json:"id"` // X window id as listed by the windows endpoint
+}
+
+type MoveWindowRequest struct {
+	ID int `json:"id"`
+	Position
+}
+
+type ResizeWindowRequest struct {
+	ID int `json:"id"`
+	Size
+}
+
+type LaunchApplicationRequest struct {
+	Command string            `json:"command" binding:"required"`
+	Args    []string          `json:"args"`
+	Env     map[string]string `json:"env"`
+	Cwd     string            `json:"cwd"`
+	// Milliseconds to wait for the window of the application, defaults to 10000
+	Timeout int `json:"timeout"`
+}
+
+type LaunchApplicationResponse struct {
+	PID int `json:"pid"`
+	// Nil when no window was mapped before the timeout
+	Window *WindowInfo `json:"window,omitempty"`
+}
+
+// Clipboard structs
+type GetClipboardRequest struct {
+	Format string `json:"format" form:"format"` // "text" (default) or "image"
+}
+
+type SetClipboardRequest struct {
+	// Exactly one of text or image, a base64 encoded PNG or JPEG
+	Text  string `json:"text"`
+	Image string `json:"image"`
+}
+
+type ClipboardResponse struct {
+	Format string `json:"format"`
+	Text   string `json:"text,omitempty"`
+	Image  string `json:"image,omitempty"` // base64 encoded PNG
+}
+
+// Accessibility structs
+type AccessibilityTreeRequest struct {
+	Scope       string `json:"scope" form:"scope"`             // "focused" (default) for the focused window or "all"
+	MaxDepth    int    `json:"maxDepth" form:"maxDepth"`       // defaults to 30
+	VisibleOnly bool   `json:"visibleOnly" form:"visibleOnly"` // skips nodes that are not showing
+}
+
+type AccessibilityTreeResponse struct {
+	// The focused window, or every application with its windows
+	Nodes []AccessibilityNode `json:"nodes"`
+	// Set when the tree was cut short because it has too many nodes
+	Truncated bool `json:"truncated"`
+}
+
+type AccessibilityNode struct {
+	// Child indexes from the desktop to the node, e.g. "2/0/5"
+	Path        string   `json:"path"`
+	Role        string   `json:"role"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	States      []string `json:"states"`
+	// Screen coordinates, nil for nodes that are not drawn
+	Bounds  *Rectangle `json:"bounds,omitempty"`
+	Actions []string   `json:"actions,omitempty"`
+	// Contents of editable text and value of sliders, spin buttons and the like
+	Value    string              `json:"value,omitempty"`
+	Children []AccessibilityNode `json:"children,omitempty"`
+}
+
+type AccessibilityActionRequest struct {
+	Path string `json:"path" binding:"required"`
+	// Name of one of the node's actions, defaults to its first action
+	Action string `json:"action"`
+	// Sets the text or numeric value of the node instead of invoking an action
+	Value *string `json:"value"`
+}
+
+// Screen change structs
+type Rectangle struct {
+	Position
+	Size
+}
+
+type DiffRequest struct {
+	// Base64 encoded PNG or JPEG images as returned by the screenshot
+	// endpoints. The current screen is captured when after is empty.
+	Before string `json:"before"`
+	After  string `json:"after"`
+	// Region to capture when after is empty, the whole display when width or
+	// height is zero. The capture is scaled to the size of before.
+	Position
+	Size
+	// Per channel difference that is ignored, use around 16 when comparing
+	// JPEG screenshots
+	Tolerance int `json:"tolerance"`
+}
+
+type DiffResponse struct {
+	Changed      bool    `json:"changed"`
+	ChangedRatio float64 `json:"changedRatio"` // fraction of pixels that differ
+	// Bounding boxes of the changed areas relative to the compared images
+	Regions []Rectangle `json:"regions"`
+}
+
+type WaitForChangeRequest struct {
+	// Region to watch, the whole display when width or height is zero
+	Position
+	Size
+	Threshold float64 `json:"threshold"` // fraction of pixels that must change, defaults to any change
+	Tolerance int     `json:"tolerance"`
+	Scale     float64 `json:"scale"`    // 0.1-1.0, compares downscaled captures for speed
+	Interval  int     `json:"interval"` // milliseconds between captures, defaults to 100
+	Timeout   int     `json:"timeout"`  // milliseconds, defaults to 10000
+}
+
+type WaitForStableRequest struct {
+	// Region to watch, the whole display when width or height is zero
+	Position
+	Size
+	Duration  int     `json:"duration"`  // milliseconds the screen must stay unchanged, defaults to 500
+	Threshold float64 `json:"threshold"` // fraction of pixels allowed to change, defaults to none
+	Tolerance int     `json:"tolerance"`
+	Scale     float64 `json:"scale"`    // 0.1-1.0, compares downscaled captures for speed
+	Interval  int     `json:"interval"` // milliseconds between captures, defaults to 100
+	Timeout   int     `json:"timeout"`  // milliseconds, defaults to 10000
+}
+
+type WaitForScreenResponse struct {
+	// False when the timeout passed first
+	Satisfied bool  `json:"satisfied"`
+	ElapsedMs int64 `json:"elapsedMs"`
+	// Last comparison, in coordinates of the scaled captures
+	DiffResponse
+}
+
+// Screen recording structs
+type StartScreenRecordingRequest struct {
+	// Region to capture, the whole display when width or height is zero
+	Position
+	Size
+	FPS        int  `json:"fps"` // frames per second, defaults to 10
+	ShowCursor bool `json:"showCursor"`
+	// "mp4" records through ffmpeg, "mjpeg" captures screenshots and is used
+	// when ffmpeg is not installed. Defaults to the best available format.
+	Format string `json:"format"`
+	// Seconds after which the recording stops by itself. Defaults to no limit
+	// for mp4 and to 10 minutes for the much larger mjpeg files.
+	MaxDuration int `json:"maxDuration"`
+}
+
+type ScreenRecordingRequest struct {
+	// Defaults to the active recording when stopping
+	ID string `json:"id"`
+}
+
+type ScreenRecording struct {
+	ID     string `json:"id"`
+	Format string `json:"format"`
+	Position
+	Size
+	FPS        int  `json:"fps"`
+	ShowCursor bool `json:"showCursor"`
+	// seconds, zero when the recording has no limit
+	MaxDuration int        `json:"maxDuration,omitempty"`
+	Status      string     `json:"status"` // recording, completed or failed
+	Error       string     `json:"error,omitempty"`
+	StartedAt   time.Time  `json:"startedAt"`
+	EndedAt     *time.Time `json:"endedAt,omitempty"`
+	SizeBytes   int64      `json:"sizeBytes"`
+	// Location of the video file on the sandbox
+	Path string `json:"path"`
+}
+
+type ScreenRecordingsResponse struct {
+	Recordings []ScreenRecording `json:"recordings"`
+}
+
 type StatusResponse struct {
 	Status string `json:"status"`
 }
@@ -457,6 +671,146 @@ func WrapWindowsHandler(fn func() (*WindowsResponse, error)) gin.HandlerFunc {
 	}
 }
 
+// windowRequest parses the window id from the path, in decimal or 0x prefixed
+// hexadecimal as printed by wmctrl and xdotool
+func windowRequest(c *gin.Context) (*WindowRequest, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 0, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid window id"})
+		return nil, false
+	}
+	return &WindowRequest{ID: int(id)}, true
+}
+
+func WrapWindowHandler(fn func(*WindowRequest) (*WindowInfo, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req, ok := windowRequest(c)
+		if !ok {
+			return
+		}
+
+		response, err := fn(req)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+func WrapMoveWindowHandler(fn func(*MoveWindowRequest) (*WindowInfo, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		window, ok := windowRequest(c)
+		if !ok {
+			return
+		}
+
+		var req MoveWindowRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid position"})
+			return
+		}
+		req.ID = window.ID
+
+		response, err := fn(&req)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+func WrapResizeWindowHandler(fn func(*ResizeWindowRequest) (*WindowInfo, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		window, ok := windowRequest(c)
+		if !ok {
+			return
+		}
+
+		var req ResizeWindowRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid size"})
+			return
+		}
+		req.ID = window.ID
+
+		response, err := fn(&req)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+func WrapCloseWindowHandler(fn func(*WindowRequest) (*Empty, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req, ok := windowRequest(c)
+		if !ok {
+			return
+		}
+
+		response, err := fn(req)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+func WrapLaunchApplicationHandler(fn func(*LaunchApplicationRequest) (*LaunchApplicationResponse, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req LaunchApplicationRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid application parameters"})
+			return
+		}
+
+		response, err := fn(&req)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+func WrapGetClipboardHandler(fn func(*GetClipboardRequest) (*ClipboardResponse, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req GetClipboardRequest
+		if err := c.ShouldBindQuery(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid parameters"})
+			return
+		}
+
+		response, err := fn(&req)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+func WrapSetClipboardHandler(fn func(*SetClipboardRequest) (*Empty, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req SetClipboardRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid clipboard contents"})
+			return
+		}
+
+		response, err := fn(&req)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
 func WrapStatusHandler(fn func() (*StatusResponse, error)) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		response, err := fn()
@@ -467,3 +821,170 @@ func WrapStatusHandler(fn func() (*StatusResponse, error)) gin.HandlerFunc {
 		c.JSON(http.StatusOK, response)
 	}
 }
+
+func WrapAccessibilityTreeHandler(fn func(*AccessibilityTreeRequest) (*AccessibilityTreeResponse, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req AccessibilityTreeRequest
+		if err := c.ShouldBindQuery(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid parameters"})
+			return
+		}
+
+		response, err := fn(&req)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+func WrapAccessibilityActionHandler(fn func(*AccessibilityActionRequest) (*AccessibilityNode, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req AccessibilityActionRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid action parameters"})
+			return
+		}
+
+		response, err := fn(&req)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+func WrapDiffHandler(fn func(*DiffRequest) (*DiffResponse, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req DiffRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid diff parameters"})
+			return
+		}
+
+		response, err := fn(&req)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+func WrapWaitForChangeHandler(fn func(*WaitForChangeRequest) (*WaitForScreenResponse, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req WaitForChangeRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid wait parameters"})
+			return
+		}
+
+		response, err := fn(&req)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+func WrapWaitForStableHandler(fn func(*WaitForStableRequest) (*WaitForScreenResponse, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req WaitForStableRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid wait parameters"})
+			return
+		}
+
+		response, err := fn(&req)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+func WrapStartScreenRecordingHandler(fn func(*StartScreenRecordingRequest) (*ScreenRecording, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req StartScreenRecordingRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid recording parameters"})
+			return
+		}
+
+		response, err := fn(&req)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+func WrapStopScreenRecordingHandler(fn func(*ScreenRecordingRequest) (*ScreenRecording, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req ScreenRecordingRequest
+		// The body is optional, the active recording is stopped by default
+		if c.Request.ContentLength != 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid recording parameters"})
+				return
+			}
+		}
+
+		response, err := fn(&req)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+func WrapScreenRecordingsHandler(fn func() (*ScreenRecordingsResponse, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		response, err := fn()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+func WrapScreenRecordingHandler(fn func(*ScreenRecordingRequest) (*ScreenRecording, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		response, err := fn(&ScreenRecordingRequest{ID: c.Param("id")})
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// WrapDownloadScreenRecordingHandler serves the video file of a finished
+// recording, the plugin runs on the same machine so the file is read directly.
+func WrapDownloadScreenRecordingHandler(fn func(*ScreenRecordingRequest) (*ScreenRecording, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		recording, err := fn(&ScreenRecordingRequest{ID: c.Param("id")})
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if recording.Status == "recording" {
+			c.JSON(http.StatusConflict, gin.H{"error": "recording is still in progress"})
+			return
+		}
+		// Only serve the video file of the requested recording
+		name := recording.ID + "." + recording.Format
+		if recording.ID != c.Param("id") || filepath.Base(recording.Path) != name {
+			c.JSON(http.StatusNotFound, gin.H{"error": "recording file not found"})
+			return
+		}
+		c.FileAttachment(recording.Path, name)
+	}
+}
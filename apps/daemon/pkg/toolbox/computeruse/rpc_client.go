@@ -144,6 +144,117 @@ func (m *ComputerUseRPCClient) GetWindows() (*WindowsResponse, error) {
 	return &resp, err
 }
 
+// Window management methods
+func (m *ComputerUseRPCClient) FocusWindow(request *WindowRequest) (*WindowInfo, error) {
+	var resp WindowInfo
+	err := m.client.Call("Plugin.FocusWindow", request, &resp)
+	return &resp, err
+}
+
+func (m *ComputerUseRPCClient) MoveWindow(request *MoveWindowRequest) (*WindowInfo, error) {
+	var resp WindowInfo
+	err := m.client.Call("Plugin.MoveWindow", request, &resp)
+	return &resp, err
+}
+
+func (m *ComputerUseRPCClient) ResizeWindow(request *ResizeWindowRequest) (*WindowInfo, error) {
+	var resp WindowInfo
+	err := m.client.Call("Plugin.ResizeWindow", request, &resp)
+	return &resp, err
+}
+
+func (m *ComputerUseRPCClient) MinimizeWindow(request *WindowRequest) (*WindowInfo, error) {
+	var resp WindowInfo
+	err := m.client.Call("Plugin.MinimizeWindow", request, &resp)
+	return &resp, err
+}
+
+func (m *ComputerUseRPCClient) MaximizeWindow(request *WindowRequest) (*WindowInfo, error) {
+	var resp WindowInfo
+	err := m.client.Call("Plugin.MaximizeWindow", request, &resp)
+	return &resp, err
+}
+
+func (m *ComputerUseRPCClient) CloseWindow(request *WindowRequest) (*Empty, error) {
+	err := m.client.Call("Plugin.CloseWindow", request, new(Empty))
+	return new(Empty), err
+}
+
+func (m *ComputerUseRPCClient) LaunchApplication(request *LaunchApplicationRequest) (*LaunchApplicationResponse, error) {
+	var resp LaunchApplicationResponse
+	err := m.client.Call("Plugin.LaunchApplication", request, &resp)
+	return &resp, err
+}
+
+// Clipboard methods
+func (m *ComputerUseRPCClient) GetClipboard(request *GetClipboardRequest) (*ClipboardResponse, error) {
+	var resp ClipboardResponse
+	err := m.client.Call("Plugin.GetClipboard", request, &resp)
+	return &resp, err
+}
+
+func (m *ComputerUseRPCClient) SetClipboard(request *SetClipboardRequest) (*Empty, error) {
+	err := m.client.Call("Plugin.SetClipboard", request, new(Empty))
+	return new(Empty), err
+}
+
+// Accessibility methods
+func (m *ComputerUseRPCClient) GetAccessibilityTree(request *AccessibilityTreeRequest) (*AccessibilityTreeResponse, error) {
+	var resp AccessibilityTreeResponse
+	err := m.client.Call("Plugin.GetAccessibilityTree", request, &resp)
+	return &resp, err
+}
+
+func (m *ComputerUseRPCClient) InvokeAccessibilityAction(request *AccessibilityActionRequest) (*AccessibilityNode, error) {
+	var resp AccessibilityNode
+	err := m.client.Call("Plugin.InvokeAccessibilityAction", request, &resp)
+	return &resp, err
+}
+
+// Screen change methods
+func (m *ComputerUseRPCClient) Diff(request *DiffRequest) (*DiffResponse, error) {
+	var resp DiffResponse
+	err := m.client.Call("Plugin.Diff", request, &resp)
+	return &resp, err
+}
+
+func (m *ComputerUseRPCClient) WaitForChange(request *WaitForChangeRequest) (*WaitForScreenResponse, error) {
+	var resp WaitForScreenResponse
+	err := m.client.Call("Plugin.WaitForChange", request, &resp)
+	return &resp, err
+}
+
+func (m *ComputerUseRPCClient) WaitForStable(request *WaitForStableRequest) (*WaitForScreenResponse, error) {
+	var resp WaitForScreenResponse
+	err := m.client.Call("Plugin.WaitForStable", request, &resp)
+	return &resp, err
+}
+
+// Screen recording methods
+func (m *ComputerUseRPCClient) StartScreenRecording(request *StartScreenRecordingRequest) (*ScreenRecording, error) {
+	var resp ScreenRecording
+	err := m.client.Call("Plugin.StartScreenRecording", request, &resp)
+	return &resp, err
+}
+
+func (m *ComputerUseRPCClient) StopScreenRecording(request *ScreenRecordingRequest) (*ScreenRecording, error) {
+	var resp ScreenRecording
+	err := m.client.Call("Plugin.StopScreenRecording", request, &resp)
+	return &resp, err
+}
+
+func (m *ComputerUseRPCClient) ListScreenRecordings() (*ScreenRecordingsResponse, error) {
+	var resp ScreenRecordingsResponse
+	err := m.client.Call("Plugin.ListScreenRecordings", new(any), &resp)
+	return &resp, err
+}
+
+func (m *ComputerUseRPCClient) GetScreenRecording(request *ScreenRecordingRequest) (*ScreenRecording, error) {
+	var resp ScreenRecording
+	err := m.client.Call("Plugin.GetScreenRecording", request, &resp)
+	return &resp, err
+}
+
 // Status method
 func (m *ComputerUseRPCClient) GetStatus() (*StatusResponse, error) {
 	var resp StatusResponse
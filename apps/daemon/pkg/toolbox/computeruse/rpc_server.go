@@ -182,6 +182,165 @@ func (m *ComputerUseRPCServer) GetWindows(arg any, resp *WindowsResponse) error
 	return nil
 }
 
+// Window management methods
+func (m *ComputerUseRPCServer) FocusWindow(arg *WindowRequest, resp *WindowInfo) error {
+	response, err := m.Impl.FocusWindow(arg)
+	if err != nil {
+		return err
+	}
+	*resp = *response
+	return nil
+}
+
+func (m *ComputerUseRPCServer) MoveWindow(arg *MoveWindowRequest, resp *WindowInfo) error {
+	response, err := m.Impl.MoveWindow(arg)
+	if err != nil {
+		return err
+	}
+	*resp = *response
+	return nil
+}
+
+func (m *ComputerUseRPCServer) ResizeWindow(arg *ResizeWindowRequest, resp *WindowInfo) error {
+	response, err := m.Impl.ResizeWindow(arg)
+	if err != nil {
+		return err
+	}
+	*resp = *response
+	return nil
+}
+
+func (m *ComputerUseRPCServer) MinimizeWindow(arg *WindowRequest, resp *WindowInfo) error {
+	response, err := m.Impl.MinimizeWindow(arg)
+	if err != nil {
+		return err
+	}
+	*resp = *response
+	return nil
+}
+
+func (m *ComputerUseRPCServer) MaximizeWindow(arg *WindowRequest, resp *WindowInfo) error {
+	response, err := m.Impl.MaximizeWindow(arg)
+	if err != nil {
+		return err
+	}
+	*resp = *response
+	return nil
+}
+
+func (m *ComputerUseRPCServer) CloseWindow(arg *WindowRequest, resp *Empty) error {
+	_, err := m.Impl.CloseWindow(arg)
+	return err
+}
+
+func (m *ComputerUseRPCServer) LaunchApplication(arg *LaunchApplicationRequest, resp *LaunchApplicationResponse) error {
+	response, err := m.Impl.LaunchApplication(arg)
+	if err != nil {
+		return err
+	}
+	*resp = *response
+	return nil
+}
+
+// Clipboard methods
+func (m *ComputerUseRPCServer) GetClipboard(arg *GetClipboardRequest, resp *ClipboardResponse) error {
+	response, err := m.Impl.GetClipboard(arg)
+	if err != nil {
+		return err
+	}
+	*resp = *response
+	return nil
+}
+
+func (m *ComputerUseRPCServer) SetClipboard(arg *SetClipboardRequest, resp *Empty) error {
+	_, err := m.Impl.SetClipboard(arg)
+	return err
+}
+
+// Accessibility methods
+func (m *ComputerUseRPCServer) GetAccessibilityTree(arg *AccessibilityTreeRequest, resp *AccessibilityTreeResponse) error {
+	response, err := m.Impl.GetAccessibilityTree(arg)
+	if err != nil {
+		return err
+	}
+	*resp = *response
+	return nil
+}
+
+func (m *ComputerUseRPCServer) InvokeAccessibilityAction(arg *AccessibilityActionRequest, resp *AccessibilityNode) error {
+	response, err := m.Impl.InvokeAccessibilityAction(arg)
+	if err != nil {
+		return err
+	}
+	*resp = *response
+	return nil
+}
+
+// Screen change methods
+func (m *ComputerUseRPCServer) Diff(arg *DiffRequest, resp *DiffResponse) error {
+	response, err := m.Impl.Diff(arg)
+	if err != nil {
+		return err
+	}
+	*resp = *response
+	return nil
+}
+
+func (m *ComputerUseRPCServer) WaitForChange(arg *WaitForChangeRequest, resp *WaitForScreenResponse) error {
+	response, err := m.Impl.WaitForChange(arg)
+	if err != nil {
+		return err
+	}
+	*resp = *response
+	return nil
+}
+
+func (m *ComputerUseRPCServer) WaitForStable(arg *WaitForStableRequest, resp *WaitForScreenResponse) error {
+	response, err := m.Impl.WaitForStable(arg)
+	if err != nil {
+		return err
+	}
+	*resp = *response
+	return nil
+}
+
+// Screen recording methods
+func (m *ComputerUseRPCServer) StartScreenRecording(arg *StartScreenRecordingRequest, resp *ScreenRecording) error {
+	response, err := m.Impl.StartScreenRecording(arg)
+	if err != nil {
+		return err
+	}
+	*resp = *response
+	return nil
+}
+
+func (m *ComputerUseRPCServer) StopScreenRecording(arg *ScreenRecordingRequest, resp *ScreenRecording) error {
+	response, err := m.Impl.StopScreenRecording(arg)
+	if err != nil {
+		return err
+	}
+	*resp = *response
+	return nil
+}
+
+func (m *ComputerUseRPCServer) ListScreenRecordings(arg any, resp *ScreenRecordingsResponse) error {
+	response, err := m.Impl.ListScreenRecordings()
+	if err != nil {
+		return err
+	}
+	*resp = *response
+	return nil
+}
+
+func (m *ComputerUseRPCServer) GetScreenRecording(arg *ScreenRecordingRequest, resp *ScreenRecording) error {
+	response, err := m.Impl.GetScreenRecording(arg)
+	if err != nil {
+		return err
+	}
+	*resp = *response
+	return nil
+}
+
 // Status method
 func (m *ComputerUseRPCServer) GetStatus(arg any, resp *StatusResponse) error {
 	response, err := m.Impl.GetStatus()
@@ -3,4 +3,9 @@
 
 package config
 
+import "time"
+
 const TOOLBOX_API_PORT = 2280
+
+// Default timeout of commands run through /process/execute
+const DEFAULT_EXECUTE_TIMEOUT = 360 * time.Second
@@ -42,6 +42,13 @@ func DeleteFile(c *gin.Context) {
 		return
 	}
 
+	if recursive {
+		if err := checkSubtree(path); err != nil {
+			c.AbortWithError(http.StatusForbidden, err)
+			return
+		}
+	}
+
 	var deleteErr error
 	if recursive {
 		deleteErr = os.RemoveAll(path)
@@ -10,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/daytonaio/daemon/pkg/telemetry"
 	"github.com/gin-gonic/gin"
 )
 
@@ -54,4 +55,6 @@ func DownloadFile(c *gin.Context) {
 	c.Header("Pragma", "public")
 
 	c.File(absPath)
+
+	telemetry.FsBytesTransferred.WithLabelValues(telemetry.DirectionDownload).Add(float64(c.Writer.Size()))
 }
@@ -28,6 +28,14 @@ func FindInFiles(c *gin.Context) {
 			return filepath.SkipDir
 		}
 
+		// The policy middleware only checked the root of the walk
+		if checkPath(filePath) != nil {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
 		if !info.Mode().IsRegular() {
 			return nil
 		}
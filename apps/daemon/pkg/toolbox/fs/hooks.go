@@ -0,0 +1,37 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package fs
+
+import (
+	"path/filepath"
+	"sync"
+)
+
+var (
+	writeHooksMu sync.RWMutex
+	writeHooks   []func(path string)
+)
+
+// OnFileWritten registers a hook called with the absolute path of every file
+// whose content is written through the file system API.
+func OnFileWritten(hook func(path string)) {
+	writeHooksMu.Lock()
+	defer writeHooksMu.Unlock()
+
+	writeHooks = append(writeHooks, hook)
+}
+
+func notifyFileWritten(path string) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return
+	}
+
+	writeHooksMu.RLock()
+	defer writeHooksMu.RUnlock()
+
+	for _, hook := range writeHooks {
+		hook(absPath)
+	}
+}
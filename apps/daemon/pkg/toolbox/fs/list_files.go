@@ -33,7 +33,12 @@ func ListFiles(c *gin.Context) {
 
 	var fileInfos []FileInfo = make([]FileInfo, 0)
 	for _, file := range files {
-		info, err := getFileInfo(filepath.Join(path, file.Name()))
+		filePath := filepath.Join(path, file.Name())
+		if checkPath(filePath) != nil {
+			continue
+		}
+
+		info, err := getFileInfo(filePath)
 		if err != nil {
 			continue
 		}
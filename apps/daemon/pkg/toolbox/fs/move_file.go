@@ -36,6 +36,11 @@ func MoveFile(c *gin.Context) {
 		return
 	}
 
+	if err := checkSubtree(absSourcePath); err != nil {
+		c.AbortWithError(http.StatusForbidden, err)
+		return
+	}
+
 	// Check if source exists
 	sourceInfo, err := os.Stat(absSourcePath)
 	if err != nil {
@@ -0,0 +1,200 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package fs
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PathPolicy restricts the paths the file system API may access. An empty
+// allow list allows every path, denied paths take precedence.
+type PathPolicy struct {
+	AllowedPaths []string
+	DeniedPaths  []string
+}
+
+var (
+	policyMu sync.RWMutex
+	policy   PathPolicy
+)
+
+// SetPathPolicy replaces the path policy, paths are made absolute and have
+// their symlinks resolved so that they match the checked paths.
+func SetPathPolicy(p PathPolicy) error {
+	allowed, err := resolvePaths(p.AllowedPaths)
+	if err != nil {
+		return err
+	}
+	denied, err := resolvePaths(p.DeniedPaths)
+	if err != nil {
+		return err
+	}
+
+	policyMu.Lock()
+	defer policyMu.Unlock()
+
+	policy = PathPolicy{
+		AllowedPaths: allowed,
+		DeniedPaths:  denied,
+	}
+	return nil
+}
+
+// PathPolicyMiddleware rejects requests whose path, source or destination
+// query parameters are not allowed by the path policy.
+func PathPolicyMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, param := range []string{"path", "source", "destination"} {
+			path, ok := c.GetQuery(param)
+			if !ok {
+				continue
+			}
+			if err := checkPath(path); err != nil {
+				c.AbortWithError(http.StatusForbidden, err)
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+func checkPath(path string) error {
+	policyMu.RLock()
+	defer policyMu.RUnlock()
+
+	if len(policy.AllowedPaths) == 0 && len(policy.DeniedPaths) == 0 {
+		return nil
+	}
+
+	resolved, err := resolvePath(path)
+	if err != nil {
+		return fmt.Errorf("invalid path %s: %w", path, err)
+	}
+
+	for _, denied := range policy.DeniedPaths {
+		if isWithin(resolved, denied) {
+			return fmt.Errorf("access to %s is denied", path)
+		}
+	}
+
+	if len(policy.AllowedPaths) == 0 {
+		return nil
+	}
+	for _, allowed := range policy.AllowedPaths {
+		if isWithin(resolved, allowed) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("access to %s is not allowed", path)
+}
+
+// CheckPathWithin rejects a path outside root or not allowed by the path
+// policy, so that APIs writing files on behalf of the caller outside of the
+// file system API get the same restrictions. Subtree is set for operations
+// that affect everything below the path.
+func CheckPathWithin(path, root string, subtree bool) error {
+	resolvedRoot, err := resolvePath(root)
+	if err != nil {
+		return fmt.Errorf("invalid path %s: %w", root, err)
+	}
+	resolved, err := resolvePath(path)
+	if err != nil {
+		return fmt.Errorf("invalid path %s: %w", path, err)
+	}
+	if !isWithin(resolved, resolvedRoot) {
+		return fmt.Errorf("%s is outside of %s", path, root)
+	}
+
+	if err := checkPath(path); err != nil {
+		return err
+	}
+	if subtree {
+		return checkSubtree(path)
+	}
+	return nil
+}
+
+// checkSubtree rejects operations that affect everything below a path, such
+// as moving or recursively deleting a directory, when a denied path is inside
+// it.
+func checkSubtree(path string) error {
+	policyMu.RLock()
+	defer policyMu.RUnlock()
+
+	if len(policy.DeniedPaths) == 0 {
+		return nil
+	}
+
+	resolved, err := resolvePath(path)
+	if err != nil {
+		return fmt.Errorf("invalid path %s: %w", path, err)
+	}
+
+	for _, denied := range policy.DeniedPaths {
+		if isWithin(denied, resolved) {
+			return fmt.Errorf("access to %s is denied, it contains a denied path", path)
+		}
+	}
+
+	return nil
+}
+
+func resolvePaths(paths []string) ([]string, error) {
+	resolved := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		r, err := resolvePath(path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid path %s: %w", path, err)
+		}
+		resolved = append(resolved, r)
+	}
+	return resolved, nil
+}
+
+// resolvePath returns the absolute path with symlinks resolved. Paths that do
+// not exist yet are resolved through their closest existing parent.
+func resolvePath(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+
+	existing := abs
+	rest := ""
+	for {
+		resolved, err := filepath.EvalSymlinks(existing)
+		if err == nil {
+			return filepath.Join(resolved, rest), nil
+		}
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			return abs, nil
+		}
+		rest = filepath.Join(filepath.Base(existing), rest)
+		existing = parent
+	}
+}
+
+func isWithin(path, root string) bool {
+	if path == root || root == string(filepath.Separator) {
+		return true
+	}
+	return strings.HasPrefix(path, root+string(filepath.Separator))
+}
@@ -0,0 +1,107 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package fs_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/daytonaio/daemon/pkg/toolbox/fs"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPathPolicyMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	root := t.TempDir()
+	workspace := filepath.Join(root, "workspace")
+	secrets := filepath.Join(workspace, "secrets")
+	require.NoError(t, os.MkdirAll(secrets, 0755))
+	// A link inside the workspace must not give access to paths outside it
+	require.NoError(t, os.Symlink(root, filepath.Join(workspace, "escape")))
+
+	require.NoError(t, fs.SetPathPolicy(fs.PathPolicy{
+		AllowedPaths: []string{workspace},
+		DeniedPaths:  []string{secrets},
+	}))
+	t.Cleanup(func() { _ = fs.SetPathPolicy(fs.PathPolicy{}) })
+
+	r := gin.New()
+	r.GET("/files/info", fs.PathPolicyMiddleware(), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	status := func(path string) int {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/files/info?path="+url.QueryEscape(path), nil))
+		return w.Code
+	}
+
+	require.Equal(t, http.StatusOK, status(workspace))
+	require.Equal(t, http.StatusOK, status(filepath.Join(workspace, "new", "file.txt")))
+	require.Equal(t, http.StatusForbidden, status(filepath.Join(secrets, "key")))
+	require.Equal(t, http.StatusForbidden, status(filepath.Join(workspace, "..", "other")))
+	require.Equal(t, http.StatusForbidden, status(filepath.Join(workspace, "escape", "other")))
+	require.Equal(t, http.StatusForbidden, status(root+"-sibling"))
+}
+
+func TestPathPolicy_DeniedPathsBelowRequestedPath(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	home := t.TempDir()
+	ssh := filepath.Join(home, ".ssh")
+	require.NoError(t, os.MkdirAll(ssh, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(ssh, "id_rsa"), []byte("BEGIN PRIVATE KEY"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(home, "notes.txt"), []byte("BEGIN notes"), 0644))
+
+	require.NoError(t, fs.SetPathPolicy(fs.PathPolicy{
+		DeniedPaths: []string{ssh},
+	}))
+	t.Cleanup(func() { _ = fs.SetPathPolicy(fs.PathPolicy{}) })
+
+	r := gin.New()
+	files := r.Group("/files", fs.PathPolicyMiddleware())
+	files.GET("/", fs.ListFiles)
+	files.GET("/find", fs.FindInFiles)
+	files.GET("/search", fs.SearchFiles)
+	files.POST("/move", fs.MoveFile)
+	files.DELETE("/", fs.DeleteFile)
+
+	request := func(method, target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, target, nil))
+		return w
+	}
+
+	w := request(http.MethodGet, "/files/find?pattern=BEGIN&path="+url.QueryEscape(home))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), "notes.txt")
+	require.NotContains(t, w.Body.String(), "PRIVATE KEY")
+
+	w = request(http.MethodGet, "/files/search?pattern=*&path="+url.QueryEscape(home))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), "notes.txt")
+	require.NotContains(t, w.Body.String(), "id_rsa")
+
+	w = request(http.MethodGet, "/files/?path="+url.QueryEscape(home))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), "notes.txt")
+	require.NotContains(t, w.Body.String(), ".ssh")
+
+	destination := filepath.Join(t.TempDir(), "moved")
+	w = request(http.MethodPost, "/files/move?source="+url.QueryEscape(home)+"&destination="+url.QueryEscape(destination))
+	require.Equal(t, http.StatusForbidden, w.Code)
+	require.DirExists(t, ssh)
+
+	w = request(http.MethodDelete, "/files/?recursive=true&path="+url.QueryEscape(home))
+	require.Equal(t, http.StatusForbidden, w.Code)
+	require.DirExists(t, ssh)
+
+	// Files next to the denied path can still be deleted
+	w = request(http.MethodDelete, "/files/?path="+url.QueryEscape(filepath.Join(home, "notes.txt")))
+	require.Equal(t, http.StatusNoContent, w.Code)
+}
@@ -22,6 +22,15 @@ func ReplaceInFiles(c *gin.Context) {
 	results := make([]ReplaceResult, 0, len(req.Files))
 
 	for _, filePath := range req.Files {
+		if err := checkPath(filePath); err != nil {
+			results = append(results, ReplaceResult{
+				File:    filePath,
+				Success: false,
+				Error:   err.Error(),
+			})
+			continue
+		}
+
 		content, err := os.ReadFile(filePath)
 		if err != nil {
 			results = append(results, ReplaceResult{
@@ -49,6 +58,8 @@ func ReplaceInFiles(c *gin.Context) {
 			continue
 		}
 
+		notifyFileWritten(filePath)
+
 		results = append(results, ReplaceResult{
 			File:    filePath,
 			Success: true,
@@ -25,6 +25,13 @@ func SearchFiles(c *gin.Context) {
 		if err != nil {
 			return filepath.SkipDir
 		}
+		// The policy middleware only checked the root of the walk
+		if checkPath(path) != nil {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
 		if matched, _ := filepath.Match(pattern, info.Name()); matched {
 			matches = append(matches, path)
 		}
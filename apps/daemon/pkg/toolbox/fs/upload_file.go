@@ -7,6 +7,7 @@ import (
 	"errors"
 	"net/http"
 
+	"github.com/daytonaio/daemon/pkg/telemetry"
 	"github.com/gin-gonic/gin"
 )
 
@@ -28,5 +29,8 @@ func UploadFile(c *gin.Context) {
 		return
 	}
 
+	telemetry.FsBytesTransferred.WithLabelValues(telemetry.DirectionUpload).Add(float64(file.Size))
+	notifyFileWritten(path)
+
 	c.Status(http.StatusOK)
 }
@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/daytonaio/daemon/pkg/telemetry"
 	"github.com/gin-gonic/gin"
 )
 
@@ -55,6 +56,10 @@ func UploadFiles(c *gin.Context) {
 				errs = append(errs, fmt.Sprintf("file[%s]: missing .path metadata", idx))
 				continue
 			}
+			if err := checkPath(dest); err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", dest, err))
+				continue
+			}
 
 			if d := filepath.Dir(dest); d != "" {
 				if err := os.MkdirAll(d, 0o755); err != nil {
@@ -69,10 +74,15 @@ func UploadFiles(c *gin.Context) {
 				continue
 			}
 
-			if _, err := io.Copy(f, part); err != nil {
+			n, err := io.Copy(f, part)
+			f.Close()
+			telemetry.FsBytesTransferred.WithLabelValues(telemetry.DirectionUpload).Add(float64(n))
+			if err != nil {
 				errs = append(errs, fmt.Sprintf("%s: write: %v", dest, err))
+				continue
 			}
-			f.Close()
+
+			notifyFileWritten(dest)
 			continue
 		}
 	}
@@ -0,0 +1,45 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package git
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/daytonaio/daemon/pkg/git"
+	"github.com/gin-gonic/gin"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func GetBlame(c *gin.Context) {
+	path := c.Query("path")
+	if path == "" {
+		c.AbortWithError(http.StatusBadRequest, errors.New("path is required"))
+		return
+	}
+
+	file := c.Query("file")
+	if file == "" {
+		c.AbortWithError(http.StatusBadRequest, errors.New("file is required"))
+		return
+	}
+
+	gitService := git.Service{
+		ProjectDir: path,
+	}
+
+	lines, err := gitService.Blame(file, c.Query("ref"))
+	if err != nil {
+		if errors.Is(err, object.ErrFileNotFound) {
+			c.AbortWithError(http.StatusNotFound, err)
+			return
+		}
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, GitBlameResponse{
+		Lines: lines,
+	})
+}
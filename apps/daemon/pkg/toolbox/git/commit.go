@@ -25,6 +25,23 @@ func CommitChanges(c *gin.Context) {
 		ProjectDir: req.Path,
 	}
 
+	var signing *git.SigningOptions
+	if req.Signing != nil {
+		signing = &git.SigningOptions{}
+		if req.Signing.Method != nil {
+			signing.Method = *req.Signing.Method
+		}
+		if req.Signing.Key != nil {
+			signing.Key = *req.Signing.Key
+		}
+		if req.Signing.KeyID != nil {
+			signing.KeyId = *req.Signing.KeyID
+		}
+		if req.Signing.Passphrase != nil {
+			signing.Passphrase = *req.Signing.Passphrase
+		}
+	}
+
 	commitSha, err := gitService.Commit(req.Message, &go_git.CommitOptions{
 		Author: &object.Signature{
 			Name:  req.Author,
@@ -32,7 +49,7 @@ func CommitChanges(c *gin.Context) {
 			When:  time.Now(),
 		},
 		AllowEmptyCommits: req.AllowEmpty,
-	})
+	}, signing)
 
 	if err != nil {
 		c.AbortWithError(http.StatusBadRequest, err)
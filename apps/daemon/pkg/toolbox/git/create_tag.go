@@ -0,0 +1,53 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package git
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/daytonaio/daemon/pkg/git"
+	"github.com/gin-gonic/gin"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func CreateTag(c *gin.Context) {
+	var req GitCreateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithError(http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	var tagger *object.Signature
+	if req.Message != nil {
+		if req.Tagger == "" || req.Email == "" {
+			c.AbortWithError(http.StatusBadRequest, errors.New("tagger and email are required for annotated tags"))
+			return
+		}
+
+		tagger = &object.Signature{
+			Name:  req.Tagger,
+			Email: req.Email,
+			When:  time.Now(),
+		}
+	}
+
+	message := ""
+	if req.Message != nil {
+		message = *req.Message
+	}
+
+	gitService := git.Service{
+		ProjectDir: req.Path,
+	}
+
+	if err := gitService.CreateTag(req.Name, req.Ref, message, tagger); err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	c.Status(http.StatusCreated)
+}
@@ -0,0 +1,49 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package git
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/daytonaio/daemon/pkg/git"
+	"github.com/gin-gonic/gin"
+	go_git "github.com/go-git/go-git/v5"
+	go_git_http "github.com/go-git/go-git/v5/plumbing/transport/http"
+)
+
+func DeleteTag(c *gin.Context) {
+	var req GitDeleteTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithError(http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	gitService := git.Service{
+		ProjectDir: req.Path,
+	}
+
+	if err := gitService.DeleteTag(req.Name); err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	if req.Remote != nil {
+		var auth *go_git_http.BasicAuth
+		if req.Username != nil && req.Password != nil {
+			auth = &go_git_http.BasicAuth{
+				Username: *req.Username,
+				Password: *req.Password,
+			}
+		}
+
+		err := gitService.DeleteRemoteTag(*req.Remote, req.Name, auth)
+		if err != nil && err != go_git.NoErrAlreadyUpToDate {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	c.Status(http.StatusNoContent)
+}
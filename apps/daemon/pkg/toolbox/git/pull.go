@@ -14,7 +14,7 @@ import (
 )
 
 func PullChanges(c *gin.Context) {
-	var req GitRepoRequest
+	var req GitPullRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.AbortWithError(http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
 		return
@@ -32,7 +32,11 @@ func PullChanges(c *gin.Context) {
 		ProjectDir: req.Path,
 	}
 
-	err := gitService.Pull(auth)
+	err := gitService.Pull(&git.PullOptions{
+		Remote: req.Remote,
+		Branch: req.Branch,
+		Auth:   auth,
+	})
 	if err != nil && err != go_git.NoErrAlreadyUpToDate {
 		c.AbortWithError(http.StatusBadRequest, err)
 		return
@@ -14,7 +14,7 @@ import (
 )
 
 func PushChanges(c *gin.Context) {
-	var req GitRepoRequest
+	var req GitPushRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.AbortWithError(http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
 		return
@@ -32,7 +32,13 @@ func PushChanges(c *gin.Context) {
 		ProjectDir: req.Path,
 	}
 
-	err := gitService.Push(auth)
+	err := gitService.Push(&git.PushOptions{
+		Remote:         req.Remote,
+		Branch:         req.Branch,
+		SetUpstream:    req.SetUpstream,
+		ForceWithLease: req.ForceWithLease,
+		Auth:           auth,
+	})
 	if err != nil && err != go_git.NoErrAlreadyUpToDate {
 		c.AbortWithError(http.StatusBadRequest, err)
 		return
@@ -22,7 +22,12 @@ func GetStatus(c *gin.Context) {
 		ProjectDir: path,
 	}
 
-	status, err := gitService.GetGitStatus()
+	status, err := gitService.GetGitStatus(&git.StatusOptions{
+		DetectRenames:  c.Query("detectRenames") == "true",
+		IncludeIgnored: c.Query("includeIgnored") == "true",
+		LineStats:      c.Query("lineStats") == "true",
+		Paths:          c.QueryArray("pathspec"),
+	})
 	if err != nil {
 		c.AbortWithError(http.StatusBadRequest, err)
 		return
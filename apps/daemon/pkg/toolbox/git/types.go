@@ -3,6 +3,11 @@
 
 package git
 
+import (
+	"github.com/daytonaio/daemon/pkg/git"
+	"github.com/daytonaio/daemon/pkg/gitprovider"
+)
+
 type GitAddRequest struct {
 	Path string `json:"path" validate:"required"`
 	// files to add (use . for all files)
@@ -24,8 +29,21 @@ type GitCommitRequest struct {
 	Author     string `json:"author" validate:"required"`
 	Email      string `json:"email" validate:"required"`
 	AllowEmpty bool   `json:"allow_empty,omitempty"`
+	// signs the commit when set
+	Signing *GitCommitSigning `json:"signing,omitempty" validate:"optional"`
 } // @name GitCommitRequest
 
+type GitCommitSigning struct {
+	// ssh or gpg, defaults to the gpg.format git config value
+	Method *gitprovider.SigningMethod `json:"method,omitempty" validate:"optional"`
+	// armored GPG private key or OpenSSH private key
+	Key *string `json:"key,omitempty" validate:"optional"`
+	// GPG key id, SSH key file path or SSH public key held by the ssh-agent;
+	// when both key and key_id are omitted user.signingkey from the git config is used
+	KeyID      *string `json:"key_id,omitempty" validate:"optional"`
+	Passphrase *string `json:"passphrase,omitempty" validate:"optional"`
+} // @name GitCommitSigning
+
 type GitCommitResponse struct {
 	Hash string `json:"hash" validate:"required"`
 } // @name GitCommitResponse
@@ -54,3 +72,113 @@ type GitCheckoutRequest struct {
 	Path   string `json:"path" validate:"required"`
 	Branch string `json:"branch" validate:"required"`
 } // @name GitCheckoutRequest
+
+type GitAddWorktreeRequest struct {
+	// path of the main repository or any of its worktrees
+	Path string `json:"path" validate:"required"`
+	// directory in which the new worktree is created
+	WorktreePath string `json:"worktree_path" validate:"required"`
+	Branch       string `json:"branch,omitempty" validate:"optional"`
+	// commit, tag or branch the worktree starts from
+	Commitish    string `json:"commitish,omitempty" validate:"optional"`
+	CreateBranch bool   `json:"create_branch,omitempty" validate:"optional"`
+} // @name GitAddWorktreeRequest
+
+type GitRemoveWorktreeRequest struct {
+	Path         string `json:"path" validate:"required"`
+	WorktreePath string `json:"worktree_path" validate:"required"`
+	Force        bool   `json:"force,omitempty" validate:"optional"`
+} // @name GitRemoveWorktreeRequest
+
+type GitLockWorktreeRequest struct {
+	Path         string `json:"path" validate:"required"`
+	WorktreePath string `json:"worktree_path" validate:"required"`
+	Reason       string `json:"reason,omitempty" validate:"optional"`
+} // @name GitLockWorktreeRequest
+
+type ListWorktreeResponse struct {
+	Worktrees []git.GitWorktree `json:"worktrees" validate:"required"`
+} // @name ListWorktreeResponse
+
+type GitPushRequest struct {
+	Path     string  `json:"path" validate:"required"`
+	Username *string `json:"username,omitempty" validate:"optional"`
+	Password *string `json:"password,omitempty" validate:"optional"`
+	// remote to push to (defaults to origin)
+	Remote string `json:"remote,omitempty" validate:"optional"`
+	// remote branch name (defaults to the current branch name)
+	Branch         string `json:"branch,omitempty" validate:"optional"`
+	SetUpstream    bool   `json:"set_upstream,omitempty" validate:"optional"`
+	ForceWithLease bool   `json:"force_with_lease,omitempty" validate:"optional"`
+} // @name GitPushRequest
+
+type GitPullRequest struct {
+	Path     string  `json:"path" validate:"required"`
+	Username *string `json:"username,omitempty" validate:"optional"`
+	Password *string `json:"password,omitempty" validate:"optional"`
+	// remote to pull from (defaults to origin)
+	Remote string `json:"remote,omitempty" validate:"optional"`
+	// remote branch to merge (defaults to the upstream of the current branch)
+	Branch string `json:"branch,omitempty" validate:"optional"`
+} // @name GitPullRequest
+
+type GitFetchRequest struct {
+	Path     string  `json:"path" validate:"required"`
+	Username *string `json:"username,omitempty" validate:"optional"`
+	Password *string `json:"password,omitempty" validate:"optional"`
+	// remote to fetch from (defaults to origin)
+	Remote string `json:"remote,omitempty" validate:"optional"`
+	// refspecs to fetch (defaults to the remote's configured refspecs)
+	RefSpecs []string `json:"refspecs,omitempty" validate:"optional"`
+} // @name GitFetchRequest
+
+type GitAddRemoteRequest struct {
+	Path string `json:"path" validate:"required"`
+	Name string `json:"name" validate:"required"`
+	URL  string `json:"url" validate:"required"`
+} // @name GitAddRemoteRequest
+
+type GitRemoveRemoteRequest struct {
+	Path string `json:"path" validate:"required"`
+	Name string `json:"name" validate:"required"`
+} // @name GitRemoveRemoteRequest
+
+type ListRemoteResponse struct {
+	Remotes []git.GitRemote `json:"remotes" validate:"required"`
+} // @name ListRemoteResponse
+
+type GitCreateTagRequest struct {
+	Path string `json:"path" validate:"required"`
+	Name string `json:"name" validate:"required"`
+	// revision to tag (defaults to HEAD)
+	Ref string `json:"ref,omitempty" validate:"optional"`
+	// creates an annotated tag when set, a lightweight tag otherwise
+	Message *string `json:"message,omitempty" validate:"optional"`
+	Tagger  string  `json:"tagger,omitempty" validate:"optional"`
+	Email   string  `json:"email,omitempty" validate:"optional"`
+} // @name GitCreateTagRequest
+
+type GitDeleteTagRequest struct {
+	Path string `json:"path" validate:"required"`
+	Name string `json:"name" validate:"required"`
+	// also delete the tag from this remote
+	Remote   *string `json:"remote,omitempty" validate:"optional"`
+	Username *string `json:"username,omitempty" validate:"optional"`
+	Password *string `json:"password,omitempty" validate:"optional"`
+} // @name GitDeleteTagRequest
+
+type GitPushTagRequest struct {
+	Path     string  `json:"path" validate:"required"`
+	Name     string  `json:"name" validate:"required"`
+	Remote   string  `json:"remote,omitempty" validate:"optional"`
+	Username *string `json:"username,omitempty" validate:"optional"`
+	Password *string `json:"password,omitempty" validate:"optional"`
+} // @name GitPushTagRequest
+
+type ListTagResponse struct {
+	Tags []git.GitTag `json:"tags" validate:"required"`
+} // @name ListTagResponse
+
+type GitBlameResponse struct {
+	Lines []git.GitBlameLine `json:"lines" validate:"required"`
+} // @name GitBlameResponse
@@ -5,24 +5,24 @@ package lsp
 
 import (
 	"context"
-	"fmt"
+	"encoding/json"
 	"io"
-	"os"
 	"os/exec"
-	"strings"
 
 	"github.com/sourcegraph/jsonrpc2"
 )
 
 type Client struct {
 	conn *jsonrpc2.Conn
+	sync TextDocumentSyncOptions
 }
 
 type InitializeParams struct {
-	ProcessID    int                `json:"processId"`
-	ClientInfo   ClientInfo         `json:"clientInfo"`
-	RootURI      string             `json:"rootUri"`
-	Capabilities ClientCapabilities `json:"capabilities"`
+	ProcessID             int                    `json:"processId"`
+	ClientInfo            ClientInfo             `json:"clientInfo"`
+	RootURI               string                 `json:"rootUri"`
+	InitializationOptions map[string]interface{} `json:"initializationOptions,omitempty"`
+	Capabilities          ClientCapabilities     `json:"capabilities"`
 }
 
 type ClientInfo struct {
@@ -36,8 +36,37 @@ type ClientCapabilities struct {
 }
 
 type TextDocumentClientCapabilities struct {
-	Completion     CompletionClientCapabilities     `json:"completion"`
-	DocumentSymbol DocumentSymbolClientCapabilities `json:"documentSymbol"`
+	Completion         CompletionClientCapabilities         `json:"completion"`
+	DocumentSymbol     DocumentSymbolClientCapabilities     `json:"documentSymbol"`
+	PublishDiagnostics PublishDiagnosticsClientCapabilities `json:"publishDiagnostics"`
+	Hover              HoverClientCapabilities              `json:"hover"`
+	Rename             RenameClientCapabilities             `json:"rename"`
+	CodeAction         CodeActionClientCapabilities         `json:"codeAction"`
+}
+
+type HoverClientCapabilities struct {
+	ContentFormat []string `json:"contentFormat"`
+}
+
+type RenameClientCapabilities struct {
+	PrepareSupport bool `json:"prepareSupport"`
+}
+
+type CodeActionClientCapabilities struct {
+	CodeActionLiteralSupport CodeActionLiteralSupport `json:"codeActionLiteralSupport"`
+}
+
+type CodeActionLiteralSupport struct {
+	CodeActionKind CodeActionKindInfo `json:"codeActionKind"`
+}
+
+type CodeActionKindInfo struct {
+	ValueSet []string `json:"valueSet"`
+}
+
+type PublishDiagnosticsClientCapabilities struct {
+	RelatedInformation bool `json:"relatedInformation"`
+	VersionSupport     bool `json:"versionSupport"`
 }
 
 type CompletionClientCapabilities struct {
@@ -64,7 +93,13 @@ type SymbolKindInfo struct {
 }
 
 type WorkspaceClientCapabilities struct {
-	Symbol WorkspaceSymbolClientCapabilities `json:"symbol"`
+	Symbol        WorkspaceSymbolClientCapabilities `json:"symbol"`
+	WorkspaceEdit WorkspaceEditClientCapabilities   `json:"workspaceEdit"`
+}
+
+type WorkspaceEditClientCapabilities struct {
+	DocumentChanges    bool     `json:"documentChanges"`
+	ResourceOperations []string `json:"resourceOperations"`
 }
 
 type WorkspaceSymbolClientCapabilities struct {
@@ -243,20 +278,13 @@ func (c *Client) GetCompletion(ctx context.Context, uri string, position Positio
 	return &completionList, nil
 }
 
-func (c *Client) DidOpen(ctx context.Context, uri string, languageId string) error {
-	path := strings.TrimPrefix(uri, "file://")
-
-	content, err := os.ReadFile(path)
-	if err != nil {
-		return fmt.Errorf("failed to read file: %w", err)
-	}
-
+func (c *Client) DidOpen(ctx context.Context, uri string, languageId string, version int, text string) error {
 	params := map[string]interface{}{
 		"textDocument": map[string]interface{}{
 			"uri":        uri,
 			"languageId": languageId,
-			"version":    1,
-			"text":       string(content),
+			"version":    version,
+			"text":       text,
 		},
 	}
 
@@ -307,14 +335,24 @@ func parseCompletionItem(item map[string]interface{}) CompletionItem {
 }
 
 func (c *Client) Initialize(ctx context.Context, params InitializeParams) error {
-	var result interface{}
+	var result struct {
+		Capabilities struct {
+			TextDocumentSync json.RawMessage `json:"textDocumentSync"`
+		} `json:"capabilities"`
+	}
 	if err := c.conn.Call(ctx, "initialize", params, &result); err != nil {
 		return err
 	}
 
+	c.sync = parseTextDocumentSync(result.Capabilities.TextDocumentSync)
+
 	return c.conn.Notify(ctx, "initialized", nil)
 }
 
 func (c *Client) Shutdown(ctx context.Context) error {
-	return c.conn.Notify(ctx, "shutdown", nil)
+	if err := c.conn.Call(ctx, "shutdown", nil, nil); err != nil {
+		return err
+	}
+
+	return c.conn.Notify(ctx, "exit", nil)
 }
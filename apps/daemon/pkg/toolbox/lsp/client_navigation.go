@@ -0,0 +1,391 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+type TextDocumentPositionParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Position     Position               `json:"position"`
+}
+
+type TextEdit struct {
+	Range   Range  `json:"range" validate:"required"`
+	NewText string `json:"newText" validate:"required"`
+} // @name TextEdit
+
+type TextDocumentEdit struct {
+	TextDocument VersionedTextDocumentIdentifier `json:"textDocument" validate:"required"`
+	Edits        []TextEdit                      `json:"edits" validate:"required"`
+} // @name TextDocumentEdit
+
+// ResourceOperation is a create, rename or delete file operation of a workspace edit
+type ResourceOperation struct {
+	Kind   string `json:"kind" validate:"required"`
+	URI    string `json:"uri,omitempty" validate:"optional"`
+	OldURI string `json:"oldUri,omitempty" validate:"optional"`
+	NewURI string `json:"newUri,omitempty" validate:"optional"`
+} // @name ResourceOperation
+
+// DocumentChange is an entry of a workspace edit's documentChanges, exactly one
+// of its fields is set
+type DocumentChange struct {
+	TextDocumentEdit  *TextDocumentEdit  `json:"textDocumentEdit,omitempty" validate:"optional"`
+	ResourceOperation *ResourceOperation `json:"resourceOperation,omitempty" validate:"optional"`
+} // @name DocumentChange
+
+type WorkspaceEdit struct {
+	Changes map[string][]TextEdit `json:"changes,omitempty" validate:"optional"`
+	// Applied in order, later entries may edit files created by earlier ones
+	DocumentChanges []DocumentChange `json:"documentChanges,omitempty" validate:"optional"`
+} // @name WorkspaceEdit
+
+type LspHover struct {
+	// hover content as markdown or plain text
+	Contents string `json:"contents" validate:"required"`
+	Range    *Range `json:"range,omitempty" validate:"optional"`
+} // @name LspHover
+
+type ParameterInformation struct {
+	Label         interface{} `json:"label" validate:"required"`
+	Documentation interface{} `json:"documentation,omitempty" validate:"optional"`
+} // @name ParameterInformation
+
+type SignatureInformation struct {
+	Label           string                 `json:"label" validate:"required"`
+	Documentation   interface{}            `json:"documentation,omitempty" validate:"optional"`
+	Parameters      []ParameterInformation `json:"parameters,omitempty" validate:"optional"`
+	ActiveParameter *int                   `json:"activeParameter,omitempty" validate:"optional"`
+} // @name SignatureInformation
+
+type LspSignatureHelp struct {
+	Signatures      []SignatureInformation `json:"signatures" validate:"required"`
+	ActiveSignature *int                   `json:"activeSignature,omitempty" validate:"optional"`
+	ActiveParameter *int                   `json:"activeParameter,omitempty" validate:"optional"`
+} // @name LspSignatureHelp
+
+type FormattingOptions struct {
+	TabSize      int  `json:"tabSize" validate:"required"`
+	InsertSpaces bool `json:"insertSpaces" validate:"required"`
+} // @name FormattingOptions
+
+type CodeAction struct {
+	Title       string         `json:"title" validate:"required"`
+	Kind        string         `json:"kind,omitempty" validate:"optional"`
+	Diagnostics []Diagnostic   `json:"diagnostics,omitempty" validate:"optional"`
+	IsPreferred bool           `json:"isPreferred,omitempty" validate:"optional"`
+	Edit        *WorkspaceEdit `json:"edit,omitempty" validate:"optional"`
+	Command     *Command       `json:"command,omitempty" validate:"optional"`
+} // @name CodeAction
+
+type Command struct {
+	Title     string        `json:"title" validate:"required"`
+	Command   string        `json:"command" validate:"required"`
+	Arguments []interface{} `json:"arguments,omitempty" validate:"optional"`
+} // @name Command
+
+func positionParams(uri string, position Position) TextDocumentPositionParams {
+	return TextDocumentPositionParams{
+		TextDocument: TextDocumentIdentifier{URI: uri},
+		Position:     position,
+	}
+}
+
+func (c *Client) GetDefinition(ctx context.Context, uri string, position Position) ([]LspLocation, error) {
+	var result json.RawMessage
+	if err := c.conn.Call(ctx, "textDocument/definition", positionParams(uri, position), &result); err != nil {
+		return nil, err
+	}
+
+	return parseLocations(result)
+}
+
+func (c *Client) GetReferences(ctx context.Context, uri string, position Position, includeDeclaration bool) ([]LspLocation, error) {
+	params := map[string]interface{}{
+		"textDocument": TextDocumentIdentifier{URI: uri},
+		"position":     position,
+		"context": map[string]interface{}{
+			"includeDeclaration": includeDeclaration,
+		},
+	}
+
+	var result json.RawMessage
+	if err := c.conn.Call(ctx, "textDocument/references", params, &result); err != nil {
+		return nil, err
+	}
+
+	return parseLocations(result)
+}
+
+func (c *Client) GetHover(ctx context.Context, uri string, position Position) (*LspHover, error) {
+	var result *struct {
+		Contents json.RawMessage `json:"contents"`
+		Range    *Range          `json:"range,omitempty"`
+	}
+	if err := c.conn.Call(ctx, "textDocument/hover", positionParams(uri, position), &result); err != nil {
+		return nil, err
+	}
+
+	if result == nil {
+		return nil, nil
+	}
+
+	return &LspHover{
+		Contents: parseHoverContents(result.Contents),
+		Range:    result.Range,
+	}, nil
+}
+
+func (c *Client) GetSignatureHelp(ctx context.Context, uri string, position Position) (*LspSignatureHelp, error) {
+	var result *LspSignatureHelp
+	err := c.conn.Call(ctx, "textDocument/signatureHelp", positionParams(uri, position), &result)
+	return result, err
+}
+
+func (c *Client) Rename(ctx context.Context, uri string, position Position, newName string) (*WorkspaceEdit, error) {
+	params := map[string]interface{}{
+		"textDocument": TextDocumentIdentifier{URI: uri},
+		"position":     position,
+		"newName":      newName,
+	}
+
+	var result json.RawMessage
+	if err := c.conn.Call(ctx, "textDocument/rename", params, &result); err != nil {
+		return nil, err
+	}
+
+	return parseWorkspaceEdit(result)
+}
+
+func (c *Client) Formatting(ctx context.Context, uri string, options FormattingOptions) ([]TextEdit, error) {
+	params := map[string]interface{}{
+		"textDocument": TextDocumentIdentifier{URI: uri},
+		"options":      options,
+	}
+
+	var edits []TextEdit
+	if err := c.conn.Call(ctx, "textDocument/formatting", params, &edits); err != nil {
+		return nil, err
+	}
+
+	if edits == nil {
+		edits = []TextEdit{}
+	}
+
+	return edits, nil
+}
+
+func (c *Client) GetCodeActions(ctx context.Context, uri string, rng Range, diagnostics []Diagnostic, only []string) ([]CodeAction, error) {
+	if diagnostics == nil {
+		diagnostics = []Diagnostic{}
+	}
+
+	codeActionContext := map[string]interface{}{
+		"diagnostics": diagnostics,
+	}
+	if len(only) > 0 {
+		codeActionContext["only"] = only
+	}
+
+	params := map[string]interface{}{
+		"textDocument": TextDocumentIdentifier{URI: uri},
+		"range":        rng,
+		"context":      codeActionContext,
+	}
+
+	var result []json.RawMessage
+	if err := c.conn.Call(ctx, "textDocument/codeAction", params, &result); err != nil {
+		return nil, err
+	}
+
+	actions := make([]CodeAction, 0, len(result))
+	for _, raw := range result {
+		action, err := parseCodeAction(raw)
+		if err != nil {
+			return nil, err
+		}
+		actions = append(actions, *action)
+	}
+
+	return actions, nil
+}
+
+// parseLocations handles the Location | Location[] | LocationLink[] | null
+// result of definition and references requests.
+func parseLocations(raw json.RawMessage) ([]LspLocation, error) {
+	locations := []LspLocation{}
+
+	trimmed := strings.TrimSpace(string(raw))
+	if trimmed == "" || trimmed == "null" {
+		return locations, nil
+	}
+
+	if !strings.HasPrefix(trimmed, "[") {
+		raw = json.RawMessage("[" + trimmed + "]")
+	}
+
+	var items []struct {
+		URI                  string    `json:"uri"`
+		Range                *LspRange `json:"range"`
+		TargetURI            string    `json:"targetUri"`
+		TargetSelectionRange *LspRange `json:"targetSelectionRange"`
+	}
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return nil, fmt.Errorf("failed to parse locations: %w", err)
+	}
+
+	for _, item := range items {
+		if item.TargetURI != "" && item.TargetSelectionRange != nil {
+			locations = append(locations, LspLocation{URI: item.TargetURI, Range: *item.TargetSelectionRange})
+			continue
+		}
+		if item.Range != nil {
+			locations = append(locations, LspLocation{URI: item.URI, Range: *item.Range})
+		}
+	}
+
+	return locations, nil
+}
+
+// parseHoverContents flattens MarkupContent | MarkedString | MarkedString[]
+// into a single markdown string.
+func parseHoverContents(raw json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+
+	type markedString struct {
+		Kind     string `json:"kind"`
+		Language string `json:"language"`
+		Value    string `json:"value"`
+	}
+
+	format := func(m markedString) string {
+		if m.Language != "" {
+			return fmt.Sprintf("```%s\n%s\n```", m.Language, m.Value)
+		}
+		return m.Value
+	}
+
+	var content markedString
+	if err := json.Unmarshal(raw, &content); err == nil {
+		return format(content)
+	}
+
+	var parts []json.RawMessage
+	if err := json.Unmarshal(raw, &parts); err != nil {
+		return ""
+	}
+
+	values := make([]string, 0, len(parts))
+	for _, part := range parts {
+		values = append(values, parseHoverContents(part))
+	}
+
+	return strings.Join(values, "\n\n")
+}
+
+func parseWorkspaceEdit(raw json.RawMessage) (*WorkspaceEdit, error) {
+	trimmed := strings.TrimSpace(string(raw))
+	if trimmed == "" || trimmed == "null" {
+		return &WorkspaceEdit{}, nil
+	}
+
+	var result struct {
+		Changes         map[string][]TextEdit `json:"changes"`
+		DocumentChanges []json.RawMessage     `json:"documentChanges"`
+	}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse workspace edit: %w", err)
+	}
+
+	edit := &WorkspaceEdit{
+		Changes: result.Changes,
+	}
+
+	// documentChanges mixes text document edits and resource operations
+	for _, change := range result.DocumentChanges {
+		var kind struct {
+			Kind string `json:"kind"`
+		}
+		if err := json.Unmarshal(change, &kind); err != nil {
+			return nil, fmt.Errorf("failed to parse workspace edit: %w", err)
+		}
+
+		if kind.Kind != "" {
+			var operation ResourceOperation
+			if err := json.Unmarshal(change, &operation); err != nil {
+				return nil, fmt.Errorf("failed to parse workspace edit: %w", err)
+			}
+			edit.DocumentChanges = append(edit.DocumentChanges, DocumentChange{ResourceOperation: &operation})
+			continue
+		}
+
+		var documentEdit TextDocumentEdit
+		if err := json.Unmarshal(change, &documentEdit); err != nil {
+			return nil, fmt.Errorf("failed to parse workspace edit: %w", err)
+		}
+		edit.DocumentChanges = append(edit.DocumentChanges, DocumentChange{TextDocumentEdit: &documentEdit})
+	}
+
+	return edit, nil
+}
+
+// parseCodeAction handles both CodeAction literals and bare Commands.
+func parseCodeAction(raw json.RawMessage) (*CodeAction, error) {
+	var action struct {
+		Title       string          `json:"title"`
+		Kind        string          `json:"kind"`
+		Diagnostics []Diagnostic    `json:"diagnostics"`
+		IsPreferred bool            `json:"isPreferred"`
+		Edit        json.RawMessage `json:"edit"`
+		Command     json.RawMessage `json:"command"`
+		Arguments   []interface{}   `json:"arguments"`
+	}
+	if err := json.Unmarshal(raw, &action); err != nil {
+		return nil, fmt.Errorf("failed to parse code action: %w", err)
+	}
+
+	result := &CodeAction{
+		Title:       action.Title,
+		Kind:        action.Kind,
+		Diagnostics: action.Diagnostics,
+		IsPreferred: action.IsPreferred,
+	}
+
+	var commandName string
+	if err := json.Unmarshal(action.Command, &commandName); err == nil && commandName != "" {
+		// A bare Command has a string command field
+		result.Command = &Command{
+			Title:     action.Title,
+			Command:   commandName,
+			Arguments: action.Arguments,
+		}
+		return result, nil
+	}
+
+	if len(action.Command) > 0 && string(action.Command) != "null" {
+		var command Command
+		if err := json.Unmarshal(action.Command, &command); err != nil {
+			return nil, fmt.Errorf("failed to parse code action command: %w", err)
+		}
+		result.Command = &command
+	}
+
+	if len(action.Edit) > 0 && string(action.Edit) != "null" {
+		edit, err := parseWorkspaceEdit(action.Edit)
+		if err != nil {
+			return nil, err
+		}
+		result.Edit = edit
+	}
+
+	return result, nil
+}
@@ -0,0 +1,148 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package lsp
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+type Diagnostic struct {
+	Range    Range       `json:"range" validate:"required"`
+	Severity int         `json:"severity,omitempty" validate:"optional"`
+	Code     interface{} `json:"code,omitempty" validate:"optional"`
+	Source   string      `json:"source,omitempty" validate:"optional"`
+	Message  string      `json:"message" validate:"required"`
+} // @name Diagnostic
+
+type PublishDiagnosticsParams struct {
+	URI         string       `json:"uri"`
+	Version     *int         `json:"version,omitempty"`
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
+
+type DocumentDiagnostics struct {
+	URI         string       `json:"uri" validate:"required"`
+	LanguageId  string       `json:"languageId" validate:"required"`
+	Version     *int         `json:"version,omitempty" validate:"optional"`
+	Diagnostics []Diagnostic `json:"diagnostics" validate:"required"`
+	UpdatedAt   time.Time    `json:"updatedAt" validate:"required"`
+} // @name DocumentDiagnostics
+
+// DiagnosticsStore keeps the latest diagnostics published by the language
+// servers for each document URI and fans updates out to subscribers.
+type DiagnosticsStore struct {
+	mu        sync.RWMutex
+	documents map[string]DocumentDiagnostics
+	// subscriber channels and the URI they are limited to, empty for all
+	subscribers map[chan DocumentDiagnostics]string
+}
+
+func NewDiagnosticsStore() *DiagnosticsStore {
+	return &DiagnosticsStore{
+		documents:   make(map[string]DocumentDiagnostics),
+		subscribers: make(map[chan DocumentDiagnostics]string),
+	}
+}
+
+func (d *DiagnosticsStore) Publish(languageId string, params PublishDiagnosticsParams) {
+	diagnostics := params.Diagnostics
+	if diagnostics == nil {
+		diagnostics = []Diagnostic{}
+	}
+
+	update := DocumentDiagnostics{
+		URI:         params.URI,
+		LanguageId:  languageId,
+		Version:     params.Version,
+		Diagnostics: diagnostics,
+		UpdatedAt:   time.Now(),
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if len(diagnostics) == 0 {
+		delete(d.documents, params.URI)
+	} else {
+		d.documents[params.URI] = update
+	}
+
+	for ch, uri := range d.subscribers {
+		if uri != "" && uri != params.URI {
+			continue
+		}
+		select {
+		case ch <- update:
+		default:
+			// Drop updates for slow subscribers rather than blocking the language server
+		}
+	}
+}
+
+func (d *DiagnosticsStore) Get(uri string) DocumentDiagnostics {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.get(uri)
+}
+
+func (d *DiagnosticsStore) get(uri string) DocumentDiagnostics {
+	if document, ok := d.documents[uri]; ok {
+		return document
+	}
+
+	return DocumentDiagnostics{
+		URI:         uri,
+		Diagnostics: []Diagnostic{},
+	}
+}
+
+func (d *DiagnosticsStore) List() []DocumentDiagnostics {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.list()
+}
+
+func (d *DiagnosticsStore) list() []DocumentDiagnostics {
+	documents := make([]DocumentDiagnostics, 0, len(d.documents))
+	for _, document := range d.documents {
+		documents = append(documents, document)
+	}
+
+	sort.Slice(documents, func(i, j int) bool {
+		return documents[i].URI < documents[j].URI
+	})
+
+	return documents
+}
+
+// Subscribe returns a channel receiving the diagnostics updates of a document,
+// or of all documents when uri is empty, and a function that must be called to
+// stop receiving them. The channel first receives the current diagnostics, so
+// no update is missed between reading them and subscribing.
+func (d *DiagnosticsStore) Subscribe(uri string) (<-chan DocumentDiagnostics, func()) {
+	d.mu.Lock()
+
+	snapshot := []DocumentDiagnostics{d.get(uri)}
+	if uri == "" {
+		snapshot = d.list()
+	}
+
+	ch := make(chan DocumentDiagnostics, len(snapshot)+64)
+	for _, document := range snapshot {
+		ch <- document
+	}
+	d.subscribers[ch] = uri
+
+	d.mu.Unlock()
+
+	return ch, func() {
+		d.mu.Lock()
+		delete(d.subscribers, ch)
+		d.mu.Unlock()
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package lsp
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool {
+		return true
+	},
+}
+
+// Diagnostics returns the latest diagnostics of a document, or of all documents
+// when no uri is given. WebSocket requests receive them followed by every
+// subsequent update.
+func Diagnostics(c *gin.Context) {
+	uri := c.Query("uri")
+	store := GetLSPService().Diagnostics()
+
+	if c.Request.Header.Get("Upgrade") == "websocket" {
+		streamDiagnostics(c, store, uri)
+		return
+	}
+
+	if uri != "" {
+		c.JSON(http.StatusOK, store.Get(uri))
+		return
+	}
+
+	c.JSON(http.StatusOK, store.List())
+}
+
+func streamDiagnostics(c *gin.Context, store *DiagnosticsStore, uri string) {
+	updates, unsubscribe := store.Subscribe(uri)
+	defer unsubscribe()
+
+	ws, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	defer ws.Close()
+
+	// Detect the client closing the connection
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := ws.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case update := <-updates:
+			if err := ws.WriteJSON(update); err != nil {
+				log.Debug(err)
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package lsp_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/daytonaio/daemon/pkg/toolbox/lsp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnostics_PublishedByServer(t *testing.T) {
+	registerFakeLanguageServer(t)
+
+	projectDir := t.TempDir()
+	file := filepath.Join(projectDir, "main.fake")
+	require.NoError(t, os.WriteFile(file, []byte("foo()"), 0644))
+	uri := "file://" + file
+
+	service := lsp.GetLSPService()
+	require.NoError(t, service.Start("fake", projectDir))
+	defer func() {
+		_ = service.Shutdown("fake", projectDir)
+	}()
+
+	updates, unsubscribe := service.Diagnostics().Subscribe(uri)
+	defer unsubscribe()
+
+	// The current, empty, diagnostics of the document are sent first
+	require.Empty(t, (<-updates).Diagnostics)
+
+	server, err := service.Get("fake", projectDir)
+	require.NoError(t, err)
+	require.NoError(t, server.HandleDidOpen(context.Background(), uri))
+
+	select {
+	case update := <-updates:
+		require.Equal(t, uri, update.URI)
+		require.Equal(t, "fake", update.LanguageId)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for diagnostics")
+	}
+
+	document := service.Diagnostics().Get(uri)
+	require.Len(t, document.Diagnostics, 1)
+	require.Equal(t, "undefined: foo", document.Diagnostics[0].Message)
+}
+
+func TestDiagnosticsStore_ClearsEmptyDocuments(t *testing.T) {
+	store := lsp.NewDiagnosticsStore()
+
+	store.Publish("go", lsp.PublishDiagnosticsParams{
+		URI:         "file:///a.go",
+		Diagnostics: []lsp.Diagnostic{{Message: "unused variable"}},
+	})
+	require.Len(t, store.List(), 1)
+
+	updates, unsubscribe := store.Subscribe("")
+	defer unsubscribe()
+	require.Equal(t, "file:///a.go", (<-updates).URI)
+
+	store.Publish("go", lsp.PublishDiagnosticsParams{URI: "file:///a.go"})
+	require.Empty(t, (<-updates).Diagnostics)
+	require.Empty(t, store.List())
+	require.Empty(t, store.Get("file:///a.go").Diagnostics)
+}
@@ -0,0 +1,107 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+)
+
+type TextDocumentSyncKind int
+
+const (
+	TextDocumentSyncNone        TextDocumentSyncKind = 0
+	TextDocumentSyncFull        TextDocumentSyncKind = 1
+	TextDocumentSyncIncremental TextDocumentSyncKind = 2
+)
+
+// TextDocumentSyncOptions describes how a language server wants document
+// changes and saves to be sent.
+type TextDocumentSyncOptions struct {
+	Change          TextDocumentSyncKind
+	Save            bool
+	SaveIncludeText bool
+}
+
+// TextDocumentContentChangeEvent replaces the whole document when Range is
+// nil, otherwise the text within Range.
+type TextDocumentContentChangeEvent struct {
+	Range *Range `json:"range,omitempty" validate:"optional"`
+	Text  string `json:"text" validate:"required"`
+} // @name TextDocumentContentChangeEvent
+
+type DidChangeTextDocumentParams struct {
+	TextDocument   VersionedTextDocumentIdentifier  `json:"textDocument"`
+	ContentChanges []TextDocumentContentChangeEvent `json:"contentChanges"`
+}
+
+type DidSaveTextDocumentParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Text         *string                `json:"text,omitempty"`
+}
+
+// parseTextDocumentSync parses the textDocumentSync server capability, which
+// is either a TextDocumentSyncKind or a TextDocumentSyncOptions object.
+func parseTextDocumentSync(raw json.RawMessage) TextDocumentSyncOptions {
+	if len(raw) == 0 {
+		return TextDocumentSyncOptions{}
+	}
+
+	var kind TextDocumentSyncKind
+	if err := json.Unmarshal(raw, &kind); err == nil {
+		// Servers announcing only a kind still expect didSave notifications
+		return TextDocumentSyncOptions{
+			Change: kind,
+			Save:   kind != TextDocumentSyncNone,
+		}
+	}
+
+	var options struct {
+		Change TextDocumentSyncKind `json:"change"`
+		Save   json.RawMessage      `json:"save"`
+	}
+	if err := json.Unmarshal(raw, &options); err != nil {
+		return TextDocumentSyncOptions{}
+	}
+
+	sync := TextDocumentSyncOptions{
+		Change: options.Change,
+	}
+
+	var save bool
+	var saveOptions struct {
+		IncludeText bool `json:"includeText"`
+	}
+	if err := json.Unmarshal(options.Save, &save); err == nil {
+		sync.Save = save
+	} else if err := json.Unmarshal(options.Save, &saveOptions); err == nil {
+		sync.Save = true
+		sync.SaveIncludeText = saveOptions.IncludeText
+	}
+
+	return sync
+}
+
+func (c *Client) DidChange(ctx context.Context, uri string, version int, changes []TextDocumentContentChangeEvent) error {
+	params := DidChangeTextDocumentParams{
+		TextDocument: VersionedTextDocumentIdentifier{
+			URI:     uri,
+			Version: version,
+		},
+		ContentChanges: changes,
+	}
+
+	return c.conn.Notify(ctx, "textDocument/didChange", params)
+}
+
+func (c *Client) DidSave(ctx context.Context, uri string, text *string) error {
+	params := DidSaveTextDocumentParams{
+		TextDocument: TextDocumentIdentifier{
+			URI: uri,
+		},
+		Text: text,
+	}
+
+	return c.conn.Notify(ctx, "textDocument/didSave", params)
+}
@@ -0,0 +1,59 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package lsp_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/daytonaio/daemon/pkg/toolbox/lsp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocumentSync(t *testing.T) {
+	registerFakeLanguageServer(t)
+
+	projectDir := t.TempDir()
+	file := filepath.Join(projectDir, "main.fake")
+	require.NoError(t, os.WriteFile(file, []byte("foo()\n"), 0644))
+	uri := "file://" + file
+
+	service := lsp.GetLSPService()
+	require.NoError(t, service.Start("fake", projectDir))
+	defer func() {
+		_ = service.Shutdown("fake", projectDir)
+	}()
+
+	server, err := service.Get("fake", projectDir)
+	require.NoError(t, err)
+
+	// The fake server echoes the document text it has been sent as hover contents
+	serverContent := func() string {
+		hover, err := server.HandleHover(context.Background(), uri, lsp.Position{})
+		require.NoError(t, err)
+		return hover.Contents
+	}
+
+	require.Error(t, server.HandleDidChange(context.Background(), uri, []lsp.TextDocumentContentChangeEvent{{Text: "bar()\n"}}))
+
+	require.NoError(t, server.HandleDidOpen(context.Background(), uri))
+	require.Equal(t, "foo()\n", serverContent())
+
+	// Incremental changes are applied by the daemon and sent in full to servers using full sync
+	err = server.HandleDidChange(context.Background(), uri, []lsp.TextDocumentContentChangeEvent{{
+		Range: &lsp.Range{Start: lsp.Position{Line: 0, Character: 0}, End: lsp.Position{Line: 0, Character: 3}},
+		Text:  "bar",
+	}})
+	require.NoError(t, err)
+	require.Equal(t, "bar()\n", serverContent())
+
+	require.NoError(t, os.WriteFile(file, []byte("baz()\n"), 0644))
+	service.SyncFile(file)
+	require.Equal(t, "baz()\n", serverContent())
+
+	require.NoError(t, server.HandleDidClose(context.Background(), uri))
+	require.False(t, server.IsDocumentOpen(uri))
+}
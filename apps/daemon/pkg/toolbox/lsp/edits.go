@@ -0,0 +1,259 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package lsp
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/daytonaio/daemon/pkg/toolbox/fs"
+)
+
+// ErrEditNotAllowed is returned for edits that touch paths outside of the
+// project or not allowed by the file system path policy
+var ErrEditNotAllowed = errors.New("edit not allowed")
+
+// ApplyWorkspaceEdit writes a workspace edit to disk and returns the URIs of
+// the documents whose content changed. Document changes are applied in order,
+// so renamed documents are reported by their new URI. Nothing is applied when
+// any of the paths it touches is outside of projectDir.
+func ApplyWorkspaceEdit(projectDir string, edit *WorkspaceEdit) ([]string, error) {
+	if err := checkWorkspaceEdit(projectDir, edit); err != nil {
+		return nil, err
+	}
+
+	changed := []string{}
+
+	for _, change := range edit.DocumentChanges {
+		switch {
+		case change.TextDocumentEdit != nil:
+			documentEdit := change.TextDocumentEdit
+			if err := applyFileEdits(documentEdit.TextDocument.URI, documentEdit.Edits); err != nil {
+				return changed, err
+			}
+			if !slices.Contains(changed, documentEdit.TextDocument.URI) {
+				changed = append(changed, documentEdit.TextDocument.URI)
+			}
+		case change.ResourceOperation != nil:
+			operation := change.ResourceOperation
+			if err := applyResourceOperation(*operation); err != nil {
+				return changed, err
+			}
+			switch operation.Kind {
+			case "rename":
+				if i := slices.Index(changed, operation.OldURI); i >= 0 {
+					changed[i] = operation.NewURI
+				}
+			case "delete":
+				changed = slices.DeleteFunc(changed, func(uri string) bool {
+					return uri == operation.URI
+				})
+			}
+		}
+	}
+
+	uris := make([]string, 0, len(edit.Changes))
+	for uri := range edit.Changes {
+		uris = append(uris, uri)
+	}
+	sort.Strings(uris)
+
+	for _, uri := range uris {
+		if err := applyFileEdits(uri, edit.Changes[uri]); err != nil {
+			return changed, err
+		}
+		changed = append(changed, uri)
+	}
+
+	return changed, nil
+}
+
+func ApplyTextEdits(projectDir, uri string, edits []TextEdit) error {
+	if err := checkEditPath(projectDir, uri, false); err != nil {
+		return err
+	}
+	return applyFileEdits(uri, edits)
+}
+
+func checkWorkspaceEdit(projectDir string, edit *WorkspaceEdit) error {
+	for _, change := range edit.DocumentChanges {
+		var err error
+		switch {
+		case change.TextDocumentEdit != nil:
+			err = checkEditPath(projectDir, change.TextDocumentEdit.TextDocument.URI, false)
+		case change.ResourceOperation != nil:
+			operation := change.ResourceOperation
+			switch operation.Kind {
+			case "create":
+				err = checkEditPath(projectDir, operation.URI, false)
+			case "rename":
+				err = checkEditPath(projectDir, operation.OldURI, true)
+				if err == nil {
+					err = checkEditPath(projectDir, operation.NewURI, false)
+				}
+			case "delete":
+				err = checkEditPath(projectDir, operation.URI, true)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	for uri := range edit.Changes {
+		if err := checkEditPath(projectDir, uri, false); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkEditPath(projectDir, uri string, subtree bool) error {
+	if err := fs.CheckPathWithin(uriToPath(uri), projectDir, subtree); err != nil {
+		return fmt.Errorf("%w: %w", ErrEditNotAllowed, err)
+	}
+	return nil
+}
+
+func applyFileEdits(uri string, edits []TextEdit) error {
+	path := uriToPath(uri)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	updated, err := applyTextEdits(string(content), edits)
+	if err != nil {
+		return fmt.Errorf("failed to apply edits to %s: %w", path, err)
+	}
+
+	return os.WriteFile(path, []byte(updated), info.Mode().Perm())
+}
+
+func applyResourceOperation(operation ResourceOperation) error {
+	switch operation.Kind {
+	case "create":
+		path := uriToPath(operation.URI)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return err
+		}
+		return file.Close()
+	case "rename":
+		newPath := uriToPath(operation.NewURI)
+		if err := os.MkdirAll(filepath.Dir(newPath), 0755); err != nil {
+			return err
+		}
+		return os.Rename(uriToPath(operation.OldURI), newPath)
+	case "delete":
+		return os.RemoveAll(uriToPath(operation.URI))
+	default:
+		return fmt.Errorf("unsupported resource operation: %s", operation.Kind)
+	}
+}
+
+// applyTextEdits applies non-overlapping edits to content. Positions use
+// UTF-16 code unit offsets as required by the LSP specification.
+func applyTextEdits(content string, edits []TextEdit) (string, error) {
+	type span struct {
+		start, end int
+		text       string
+	}
+
+	lineStarts := computeLineStarts(content)
+
+	spans := make([]span, 0, len(edits))
+	for _, edit := range edits {
+		start, err := positionToOffset(content, lineStarts, edit.Range.Start)
+		if err != nil {
+			return "", err
+		}
+		end, err := positionToOffset(content, lineStarts, edit.Range.End)
+		if err != nil {
+			return "", err
+		}
+		if end < start {
+			return "", errors.New("edit range end is before its start")
+		}
+		spans = append(spans, span{start: start, end: end, text: edit.NewText})
+	}
+
+	// Stable sort keeps the order of inserts at the same position
+	sort.SliceStable(spans, func(i, j int) bool {
+		return spans[i].start < spans[j].start
+	})
+
+	var b strings.Builder
+	last := 0
+	for _, s := range spans {
+		if s.start < last {
+			return "", errors.New("overlapping edits")
+		}
+		b.WriteString(content[last:s.start])
+		b.WriteString(s.text)
+		last = s.end
+	}
+	b.WriteString(content[last:])
+
+	return b.String(), nil
+}
+
+func computeLineStarts(content string) []int {
+	starts := []int{0}
+	for i := 0; i < len(content); i++ {
+		if content[i] == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+	return starts
+}
+
+func positionToOffset(content string, lineStarts []int, position Position) (int, error) {
+	if position.Line < 0 || position.Character < 0 {
+		return 0, fmt.Errorf("invalid position %d:%d", position.Line, position.Character)
+	}
+	if position.Line >= len(lineStarts) {
+		return len(content), nil
+	}
+
+	offset := lineStarts[position.Line]
+	lineEnd := len(content)
+	if position.Line+1 < len(lineStarts) {
+		lineEnd = lineStarts[position.Line+1] - 1
+	}
+
+	units := 0
+	for offset < lineEnd && units < position.Character {
+		r, size := utf8.DecodeRuneInString(content[offset:lineEnd])
+		units += len(utf16.Encode([]rune{r}))
+		offset += size
+	}
+
+	return offset, nil
+}
+
+func uriToPath(uri string) string {
+	if u, err := url.Parse(uri); err == nil && u.Scheme == "file" {
+		return u.Path
+	}
+	return strings.TrimPrefix(uri, "file://")
+}
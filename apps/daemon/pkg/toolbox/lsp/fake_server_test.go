@@ -0,0 +1,133 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package lsp_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/daytonaio/daemon/pkg/toolbox/lsp"
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// The test binary doubles as a minimal language server when started with
+// FAKE_LSP_SERVER set, so LSP tests do not depend on real servers.
+func TestMain(m *testing.M) {
+	if os.Getenv("FAKE_LSP_SERVER") == "1" {
+		runFakeLanguageServer()
+		return
+	}
+
+	os.Exit(m.Run())
+}
+
+type stdio struct{}
+
+func (stdio) Read(p []byte) (int, error)  { return os.Stdin.Read(p) }
+func (stdio) Write(p []byte) (int, error) { return os.Stdout.Write(p) }
+func (stdio) Close() error                { return os.Stdin.Close() }
+
+func runFakeLanguageServer() {
+	// Document text as synced by the client, returned as hover contents
+	documents := map[string]string{}
+
+	handler := jsonrpc2.HandlerWithError(func(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) (interface{}, error) {
+		switch req.Method {
+		case "initialize":
+			return map[string]interface{}{
+				"capabilities": map[string]interface{}{
+					"textDocumentSync": lsp.TextDocumentSyncFull,
+				},
+			}, nil
+		case "textDocument/didOpen":
+			var params struct {
+				TextDocument struct {
+					URI  string `json:"uri"`
+					Text string `json:"text"`
+				} `json:"textDocument"`
+			}
+			if err := json.Unmarshal(*req.Params, &params); err != nil {
+				return nil, err
+			}
+			documents[params.TextDocument.URI] = params.TextDocument.Text
+			return nil, conn.Notify(ctx, "textDocument/publishDiagnostics", lsp.PublishDiagnosticsParams{
+				URI: params.TextDocument.URI,
+				Diagnostics: []lsp.Diagnostic{{
+					Severity: 1,
+					Source:   "fake",
+					Message:  "undefined: foo",
+				}},
+			})
+		case "textDocument/didChange":
+			var params lsp.DidChangeTextDocumentParams
+			if err := json.Unmarshal(*req.Params, &params); err != nil {
+				return nil, err
+			}
+			for _, change := range params.ContentChanges {
+				documents[params.TextDocument.URI] = change.Text
+			}
+		case "textDocument/hover":
+			var params lsp.TextDocumentPositionParams
+			if err := json.Unmarshal(*req.Params, &params); err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{"contents": documents[params.TextDocument.URI]}, nil
+		case "textDocument/definition":
+			var params lsp.TextDocumentPositionParams
+			if err := json.Unmarshal(*req.Params, &params); err != nil {
+				return nil, err
+			}
+			return lsp.LspLocation{
+				URI: params.TextDocument.URI,
+				Range: lsp.LspRange{
+					Start: lsp.LspPosition{Line: 0, Character: 0},
+					End:   lsp.LspPosition{Line: 0, Character: 3},
+				},
+			}, nil
+		case "textDocument/rename":
+			var params struct {
+				lsp.TextDocumentPositionParams
+				NewName string `json:"newName"`
+			}
+			if err := json.Unmarshal(*req.Params, &params); err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{
+				"changes": map[string][]lsp.TextEdit{
+					params.TextDocument.URI: {{
+						Range: lsp.Range{
+							Start: lsp.Position{Line: 0, Character: 0},
+							End:   lsp.Position{Line: 0, Character: 3},
+						},
+						NewText: params.NewName,
+					}},
+				},
+			}, nil
+		case "exit":
+			os.Exit(0)
+		}
+		return nil, nil
+	})
+
+	conn := jsonrpc2.NewConn(context.Background(), jsonrpc2.NewBufferedStream(stdio{}, jsonrpc2.VSCodeObjectCodec{}), handler)
+	<-conn.DisconnectNotify()
+}
+
+func registerFakeLanguageServer(t *testing.T) {
+	executable, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = lsp.GetLSPService().Registry().Register("fake", &lsp.LanguageServerConfig{
+		Command:    executable,
+		Env:        map[string]string{"FAKE_LSP_SERVER": "1"},
+		Extensions: []string{".fake"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
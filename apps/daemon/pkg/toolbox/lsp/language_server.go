@@ -0,0 +1,391 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package lsp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/sourcegraph/jsonrpc2"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// LanguageServer runs a language server declared in the Registry over stdio.
+// The process is supervised: when it exits unexpectedly it is restarted with
+// backoff and the open documents are reopened.
+type LanguageServer struct {
+	*LSPServerAbstract
+
+	config      *LanguageServerConfig
+	diagnostics *DiagnosticsStore
+
+	// guards the process state below
+	processMu     sync.Mutex
+	pathToProject string
+	cmd           *exec.Cmd
+	exited        chan struct{}
+	startedAt     time.Time
+	state         ServerState
+	stopping      bool
+	restarts      int
+	crashes       int
+}
+
+type ServerState string
+
+const (
+	ServerStateStopped    ServerState = "stopped"
+	ServerStateRunning    ServerState = "running"
+	ServerStateRestarting ServerState = "restarting"
+	ServerStateFailed     ServerState = "failed"
+)
+
+const (
+	// consecutive crashes after which the server is no longer restarted
+	maxRestartAttempts    = 5
+	initialRestartBackoff = time.Second
+	maxRestartBackoff     = 30 * time.Second
+	// a server running at least this long before exiting is considered to
+	// have been healthy, which resets its crash count
+	stableRunDuration = time.Minute
+	shutdownTimeout   = 5 * time.Second
+)
+
+func NewLanguageServer(languageId string, config *LanguageServerConfig, diagnostics *DiagnosticsStore) *LanguageServer {
+	return &LanguageServer{
+		LSPServerAbstract: &LSPServerAbstract{
+			languageId: languageId,
+		},
+		config:      config,
+		diagnostics: diagnostics,
+		state:       ServerStateStopped,
+	}
+}
+
+func (s *LanguageServer) Initialize(pathToProject string) error {
+	s.processMu.Lock()
+	s.pathToProject = pathToProject
+	s.stopping = false
+	s.crashes = 0
+	s.processMu.Unlock()
+
+	if err := s.start(); err != nil {
+		s.processMu.Lock()
+		s.state = ServerStateFailed
+		s.processMu.Unlock()
+		return err
+	}
+
+	s.setInitialized(true)
+	return nil
+}
+
+// start launches the server process, initializes the connection and starts
+// supervising the process.
+func (s *LanguageServer) start() error {
+	ctx := context.Background()
+
+	cmd := exec.Command(s.config.Command, s.config.Args...)
+	cmd.Dir = s.pathToProject
+	cmd.Env = os.Environ()
+	for key, value := range s.config.Env {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", key, value))
+	}
+
+	stream, err := NewStdioStream(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to create stdio stream: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start %s LSP server: %w", s.languageId, err)
+	}
+
+	conn := jsonrpc2.NewConn(ctx, jsonrpc2.NewBufferedStream(stream, jsonrpc2.VSCodeObjectCodec{}), jsonrpc2.HandlerWithError(s.handle))
+
+	client := &Client{conn: conn}
+
+	params := InitializeParams{
+		ProcessID: os.Getpid(),
+		ClientInfo: ClientInfo{
+			Name:    fmt.Sprintf("daytona-%s-lsp-client", s.languageId),
+			Version: "0.0.1",
+		},
+		RootURI:               "file://" + s.pathToProject,
+		InitializationOptions: s.config.InitializationOptions,
+		Capabilities: ClientCapabilities{
+			TextDocument: TextDocumentClientCapabilities{
+				Completion: CompletionClientCapabilities{
+					DynamicRegistration: true,
+					CompletionItem: CompletionItemCapabilities{
+						SnippetSupport:          true,
+						CommitCharactersSupport: true,
+						DocumentationFormat:     []string{"markdown", "plaintext"},
+						DeprecatedSupport:       true,
+						PreselectSupport:        true,
+					},
+					ContextSupport: true,
+				},
+				DocumentSymbol: DocumentSymbolClientCapabilities{
+					DynamicRegistration: true,
+					SymbolKind: SymbolKindInfo{
+						ValueSet: []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13},
+					},
+				},
+				PublishDiagnostics: PublishDiagnosticsClientCapabilities{
+					RelatedInformation: true,
+					VersionSupport:     true,
+				},
+				Hover: HoverClientCapabilities{
+					ContentFormat: []string{"markdown", "plaintext"},
+				},
+				CodeAction: CodeActionClientCapabilities{
+					CodeActionLiteralSupport: CodeActionLiteralSupport{
+						CodeActionKind: CodeActionKindInfo{
+							ValueSet: []string{"quickfix", "refactor", "refactor.extract", "refactor.inline", "refactor.rewrite", "source", "source.organizeImports"},
+						},
+					},
+				},
+			},
+			Workspace: WorkspaceClientCapabilities{
+				Symbol: WorkspaceSymbolClientCapabilities{
+					DynamicRegistration: true,
+				},
+				WorkspaceEdit: WorkspaceEditClientCapabilities{
+					DocumentChanges:    true,
+					ResourceOperations: []string{"create", "rename", "delete"},
+				},
+			},
+		},
+	}
+
+	if err := client.Initialize(ctx, params); err != nil {
+		conn.Close()
+		killerr := cmd.Process.Kill()
+		_ = cmd.Wait()
+		if killerr != nil {
+			return fmt.Errorf("failed to initialize %s LSP connection: %w, failed to kill process: %w", s.languageId, err, killerr)
+		}
+		return fmt.Errorf("failed to initialize %s LSP connection: %w", s.languageId, err)
+	}
+
+	s.processMu.Lock()
+	defer s.processMu.Unlock()
+
+	// The server may have been stopped while a restart was in progress
+	if s.stopping {
+		conn.Close()
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return fmt.Errorf("%s LSP server was stopped", s.languageId)
+	}
+
+	exited := make(chan struct{})
+	s.cmd = cmd
+	s.exited = exited
+	s.startedAt = time.Now()
+	s.state = ServerStateRunning
+	s.setClient(client)
+
+	go s.supervise(cmd, conn, exited)
+
+	return nil
+}
+
+func (s *LanguageServer) handle(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) (interface{}, error) {
+	log.Debugf("Received request: %s", req.Method)
+
+	switch req.Method {
+	case "textDocument/publishDiagnostics":
+		if req.Params == nil {
+			return nil, nil
+		}
+		var params PublishDiagnosticsParams
+		if err := json.Unmarshal(*req.Params, &params); err != nil {
+			log.Errorf("failed to parse %s diagnostics: %v", s.languageId, err)
+			return nil, nil
+		}
+		s.diagnostics.Publish(s.languageId, params)
+	default:
+		if req.Params != nil {
+			log.Debugf("Params: %+v", req.Params)
+		}
+	}
+
+	return nil, nil
+}
+
+// supervise waits for the server process to exit and restarts it unless it
+// was stopped on purpose.
+func (s *LanguageServer) supervise(cmd *exec.Cmd, conn *jsonrpc2.Conn, exited chan struct{}) {
+	err := cmd.Wait()
+	conn.Close()
+	close(exited)
+
+	s.processMu.Lock()
+	if s.stopping || s.cmd != cmd {
+		s.processMu.Unlock()
+		return
+	}
+
+	if time.Since(s.startedAt) >= stableRunDuration {
+		s.crashes = 0
+	}
+	s.crashes++
+	s.state = ServerStateRestarting
+	s.processMu.Unlock()
+
+	log.Warnf("%s language server exited unexpectedly: %v", s.languageId, err)
+
+	for {
+		s.processMu.Lock()
+		crashes := s.crashes
+		if crashes > maxRestartAttempts {
+			s.state = ServerStateFailed
+			s.processMu.Unlock()
+			s.setInitialized(false)
+			log.Errorf("%s language server failed %d times, giving up", s.languageId, maxRestartAttempts)
+			return
+		}
+		s.processMu.Unlock()
+
+		time.Sleep(restartBackoff(crashes))
+
+		s.processMu.Lock()
+		stopping := s.stopping
+		s.processMu.Unlock()
+		if stopping {
+			return
+		}
+
+		if err := s.start(); err != nil {
+			log.Errorf("failed to restart %s language server: %v", s.languageId, err)
+			s.processMu.Lock()
+			s.crashes++
+			s.processMu.Unlock()
+			continue
+		}
+
+		s.processMu.Lock()
+		s.restarts++
+		s.processMu.Unlock()
+
+		if err := s.replayDocuments(context.Background()); err != nil {
+			log.Errorf("failed to reopen documents in %s language server: %v", s.languageId, err)
+		}
+
+		log.Infof("Restarted %s language server", s.languageId)
+		return
+	}
+}
+
+func restartBackoff(crashes int) time.Duration {
+	backoff := initialRestartBackoff
+	for i := 1; i < crashes && backoff < maxRestartBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxRestartBackoff {
+		backoff = maxRestartBackoff
+	}
+	return backoff
+}
+
+func (s *LanguageServer) Shutdown() error {
+	s.processMu.Lock()
+	s.stopping = true
+	cmd := s.cmd
+	exited := s.exited
+	s.state = ServerStateStopped
+	s.processMu.Unlock()
+
+	s.setInitialized(false)
+
+	if cmd == nil {
+		return nil
+	}
+
+	select {
+	case <-exited:
+		// Not running, e.g. while waiting to be restarted
+		return nil
+	default:
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	err := s.getClient().Shutdown(ctx)
+
+	select {
+	case <-exited:
+	case <-ctx.Done():
+		if killErr := cmd.Process.Kill(); killErr != nil {
+			return fmt.Errorf("failed to kill %s LSP server: %w", s.languageId, killErr)
+		}
+		<-exited
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to shutdown %s LSP server: %w", s.languageId, err)
+	}
+	return nil
+}
+
+// Kill stops the server process without the shutdown handshake, it is used
+// when there is no time left to wait for the server to exit.
+func (s *LanguageServer) Kill() error {
+	s.processMu.Lock()
+	s.stopping = true
+	cmd := s.cmd
+	exited := s.exited
+	s.state = ServerStateStopped
+	s.processMu.Unlock()
+
+	s.setInitialized(false)
+
+	if cmd == nil {
+		return nil
+	}
+
+	select {
+	case <-exited:
+		return nil
+	default:
+	}
+
+	if err := cmd.Process.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		return fmt.Errorf("failed to kill %s LSP server: %w", s.languageId, err)
+	}
+	<-exited
+
+	return nil
+}
+
+func (s *LanguageServer) Info() LspServerInfo {
+	s.processMu.Lock()
+	defer s.processMu.Unlock()
+
+	info := LspServerInfo{
+		State:         string(s.state),
+		Restarts:      s.restarts,
+		OpenDocuments: s.openDocumentCount(),
+	}
+
+	if s.state == ServerStateRunning && s.cmd != nil {
+		startedAt := s.startedAt
+		info.Pid = s.cmd.Process.Pid
+		info.StartedAt = &startedAt
+		info.UptimeSeconds = int64(time.Since(startedAt).Seconds())
+		info.MemoryBytes = processMemory(info.Pid)
+	}
+
+	return info
+}
@@ -101,6 +101,66 @@ func DidClose(c *gin.Context) {
 	c.Status(http.StatusOK)
 }
 
+func DidChange(c *gin.Context) {
+	var req LspDidChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithError(http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	service := GetLSPService()
+	server, err := service.Get(req.LanguageId, req.PathToProject)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+	if !server.IsInitialized() {
+		c.AbortWithError(http.StatusBadRequest, errors.New("server not initialized"))
+		return
+	}
+	err = server.HandleDidChange(c.Request.Context(), req.Uri, req.ContentChanges)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	if req.Save {
+		err = server.HandleDidSave(c.Request.Context(), req.Uri)
+		if err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func DidSave(c *gin.Context) {
+	var req LspDocumentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithError(http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	service := GetLSPService()
+	server, err := service.Get(req.LanguageId, req.PathToProject)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+	if !server.IsInitialized() {
+		c.AbortWithError(http.StatusBadRequest, errors.New("server not initialized"))
+		return
+	}
+	err = server.HandleDidSave(c.Request.Context(), req.Uri)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
 func Completions(c *gin.Context) {
 	var req LspCompletionParams
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -215,3 +275,15 @@ func WorkspaceSymbols(c *gin.Context) {
 
 	c.JSON(http.StatusOK, symbols)
 }
+
+func Languages(c *gin.Context) {
+	c.JSON(http.StatusOK, LspLanguagesResponse{
+		Languages: GetLSPService().Registry().Languages(),
+	})
+}
+
+func Servers(c *gin.Context) {
+	c.JSON(http.StatusOK, LspServersResponse{
+		Servers: GetLSPService().Servers(),
+	})
+}
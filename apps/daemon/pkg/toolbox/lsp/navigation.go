@@ -0,0 +1,244 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package lsp
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// getInitializedServer returns the server for the language and project or
+// aborts the request when it does not exist or has not been started.
+func getInitializedServer(c *gin.Context, languageId, pathToProject string) (LSPServer, bool) {
+	service := GetLSPService()
+	server, err := service.Get(languageId, pathToProject)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return nil, false
+	}
+	if !server.IsInitialized() {
+		c.AbortWithError(http.StatusBadRequest, errors.New("server not initialized"))
+		return nil, false
+	}
+
+	return server, true
+}
+
+func Definition(c *gin.Context) {
+	var req LspPositionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithError(http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	server, ok := getInitializedServer(c, req.LanguageId, req.PathToProject)
+	if !ok {
+		return
+	}
+
+	locations, err := server.HandleDefinition(c.Request.Context(), req.Uri, req.Position)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, locations)
+}
+
+func References(c *gin.Context) {
+	var req LspReferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithError(http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	server, ok := getInitializedServer(c, req.LanguageId, req.PathToProject)
+	if !ok {
+		return
+	}
+
+	locations, err := server.HandleReferences(c.Request.Context(), req.Uri, req.Position, req.IncludeDeclaration)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, locations)
+}
+
+func Hover(c *gin.Context) {
+	var req LspPositionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithError(http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	server, ok := getInitializedServer(c, req.LanguageId, req.PathToProject)
+	if !ok {
+		return
+	}
+
+	hover, err := server.HandleHover(c.Request.Context(), req.Uri, req.Position)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	if hover == nil {
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	c.JSON(http.StatusOK, hover)
+}
+
+func SignatureHelp(c *gin.Context) {
+	var req LspPositionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithError(http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	server, ok := getInitializedServer(c, req.LanguageId, req.PathToProject)
+	if !ok {
+		return
+	}
+
+	help, err := server.HandleSignatureHelp(c.Request.Context(), req.Uri, req.Position)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	if help == nil {
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	c.JSON(http.StatusOK, help)
+}
+
+func Rename(c *gin.Context) {
+	var req LspRenameRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithError(http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	server, ok := getInitializedServer(c, req.LanguageId, req.PathToProject)
+	if !ok {
+		return
+	}
+
+	edit, err := server.HandleRename(c.Request.Context(), req.Uri, req.Position, req.NewName)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	response := LspRenameResponse{
+		Edit: edit,
+	}
+
+	if req.Apply {
+		response.Applied, err = ApplyWorkspaceEdit(req.PathToProject, edit)
+		// Open documents are synced even when the edit was applied partially
+		for _, uri := range response.Applied {
+			GetLSPService().SyncFile(uriToPath(uri))
+		}
+		if errors.Is(err, ErrEditNotAllowed) {
+			c.AbortWithError(http.StatusForbidden, err)
+			return
+		}
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to apply rename: %w", err))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func Formatting(c *gin.Context) {
+	var req LspFormattingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithError(http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	server, ok := getInitializedServer(c, req.LanguageId, req.PathToProject)
+	if !ok {
+		return
+	}
+
+	options := FormattingOptions{
+		TabSize:      4,
+		InsertSpaces: true,
+	}
+	if req.Options != nil {
+		options = *req.Options
+	}
+
+	edits, err := server.HandleFormatting(c.Request.Context(), req.Uri, options)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	if req.Apply && len(edits) > 0 {
+		err = ApplyTextEdits(req.PathToProject, req.Uri, edits)
+		if errors.Is(err, ErrEditNotAllowed) {
+			c.AbortWithError(http.StatusForbidden, err)
+			return
+		}
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, fmt.Errorf("failed to apply formatting: %w", err))
+			return
+		}
+		GetLSPService().SyncFile(uriToPath(req.Uri))
+	}
+
+	c.JSON(http.StatusOK, edits)
+}
+
+func CodeActions(c *gin.Context) {
+	var req LspCodeActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithError(http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	server, ok := getInitializedServer(c, req.LanguageId, req.PathToProject)
+	if !ok {
+		return
+	}
+
+	diagnostics := req.Diagnostics
+	if diagnostics == nil {
+		// Default to the diagnostics published for the document within the range
+		for _, diagnostic := range GetLSPService().Diagnostics().Get(req.Uri).Diagnostics {
+			if rangesOverlap(diagnostic.Range, req.Range) {
+				diagnostics = append(diagnostics, diagnostic)
+			}
+		}
+	}
+
+	actions, err := server.HandleCodeActions(c.Request.Context(), req.Uri, req.Range, diagnostics, req.Only)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, actions)
+}
+
+func rangesOverlap(a, b Range) bool {
+	before := func(x, y Position) bool {
+		return x.Line < y.Line || (x.Line == y.Line && x.Character < y.Character)
+	}
+	return !before(a.End, b.Start) && !before(b.End, a.Start)
+}
@@ -0,0 +1,184 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package lsp_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/daytonaio/daemon/pkg/toolbox/lsp"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNavigation_DefinitionAndRename(t *testing.T) {
+	registerFakeLanguageServer(t)
+
+	projectDir := t.TempDir()
+	file := filepath.Join(projectDir, "main.fake")
+	require.NoError(t, os.WriteFile(file, []byte("foo()\nfoo()\n"), 0644))
+	uri := "file://" + file
+
+	service := lsp.GetLSPService()
+	require.NoError(t, service.Start("fake", projectDir))
+	defer func() {
+		_ = service.Shutdown("fake", projectDir)
+	}()
+
+	server, err := service.Get("fake", projectDir)
+	require.NoError(t, err)
+
+	locations, err := server.HandleDefinition(context.Background(), uri, lsp.Position{Line: 1, Character: 1})
+	require.NoError(t, err)
+	require.Len(t, locations, 1)
+	require.Equal(t, uri, locations[0].URI)
+
+	edit, err := server.HandleRename(context.Background(), uri, lsp.Position{Line: 0, Character: 1}, "bar")
+	require.NoError(t, err)
+	require.Len(t, edit.Changes[uri], 1)
+
+	applied, err := lsp.ApplyWorkspaceEdit(projectDir, edit)
+	require.NoError(t, err)
+	require.Equal(t, []string{uri}, applied)
+
+	content, err := os.ReadFile(file)
+	require.NoError(t, err)
+	require.Equal(t, "bar()\nfoo()\n", string(content))
+}
+
+func TestApplyTextEdits(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "main.go")
+	// "é" is one UTF-16 code unit and two bytes, "😀" is two code units and four bytes
+	require.NoError(t, os.WriteFile(file, []byte("é😀x := 1\nreturn x\n"), 0644))
+
+	err := lsp.ApplyTextEdits(dir, "file://"+file, []lsp.TextEdit{
+		{Range: lsp.Range{Start: lsp.Position{Line: 1, Character: 7}, End: lsp.Position{Line: 1, Character: 8}}, NewText: "y"},
+		{Range: lsp.Range{Start: lsp.Position{Line: 0, Character: 3}, End: lsp.Position{Line: 0, Character: 4}}, NewText: "y"},
+	})
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(file)
+	require.NoError(t, err)
+	require.Equal(t, "é😀y := 1\nreturn y\n", string(content))
+
+	err = lsp.ApplyTextEdits(dir, "file://"+file, []lsp.TextEdit{
+		{Range: lsp.Range{Start: lsp.Position{Line: 0, Character: 0}, End: lsp.Position{Line: 0, Character: 4}}, NewText: "a"},
+		{Range: lsp.Range{Start: lsp.Position{Line: 0, Character: 2}, End: lsp.Position{Line: 0, Character: 5}}, NewText: "b"},
+	})
+	require.Error(t, err)
+}
+
+func TestApplyWorkspaceEdit_DocumentChangesInOrder(t *testing.T) {
+	dir := t.TempDir()
+	created := "file://" + filepath.Join(dir, "pkg", "new.go")
+	renamed := "file://" + filepath.Join(dir, "pkg", "renamed.go")
+
+	applied, err := lsp.ApplyWorkspaceEdit(dir, &lsp.WorkspaceEdit{
+		DocumentChanges: []lsp.DocumentChange{
+			{ResourceOperation: &lsp.ResourceOperation{Kind: "create", URI: created}},
+			{TextDocumentEdit: &lsp.TextDocumentEdit{
+				TextDocument: lsp.VersionedTextDocumentIdentifier{URI: created},
+				Edits:        []lsp.TextEdit{{NewText: "package pkg\n"}},
+			}},
+			{ResourceOperation: &lsp.ResourceOperation{Kind: "rename", OldURI: created, NewURI: renamed}},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{renamed}, applied)
+
+	content, err := os.ReadFile(filepath.Join(dir, "pkg", "renamed.go"))
+	require.NoError(t, err)
+	require.Equal(t, "package pkg\n", string(content))
+	require.NoFileExists(t, filepath.Join(dir, "pkg", "new.go"))
+}
+
+func TestApplyWorkspaceEdit_OutsideProject(t *testing.T) {
+	projectDir := t.TempDir()
+	inside := filepath.Join(projectDir, "main.go")
+	require.NoError(t, os.WriteFile(inside, []byte("package main\n"), 0644))
+
+	outsideDir := t.TempDir()
+	outside := filepath.Join(outsideDir, "secret.txt")
+	require.NoError(t, os.WriteFile(outside, []byte("secret\n"), 0644))
+	require.NoError(t, os.Symlink(outsideDir, filepath.Join(projectDir, "link")))
+
+	edits := []*lsp.WorkspaceEdit{
+		{Changes: map[string][]lsp.TextEdit{"file://" + outside: {{NewText: "x"}}}},
+		{Changes: map[string][]lsp.TextEdit{"file://" + filepath.Join(projectDir, "link", "secret.txt"): {{NewText: "x"}}}},
+		{Changes: map[string][]lsp.TextEdit{"file://" + filepath.Join(projectDir, "..", filepath.Base(outsideDir), "secret.txt"): {{NewText: "x"}}}},
+		{DocumentChanges: []lsp.DocumentChange{
+			{TextDocumentEdit: &lsp.TextDocumentEdit{
+				TextDocument: lsp.VersionedTextDocumentIdentifier{URI: "file://" + inside},
+				Edits:        []lsp.TextEdit{{NewText: "x"}},
+			}},
+			{ResourceOperation: &lsp.ResourceOperation{Kind: "delete", URI: "file://" + outsideDir}},
+		}},
+		{DocumentChanges: []lsp.DocumentChange{
+			{ResourceOperation: &lsp.ResourceOperation{Kind: "rename", OldURI: "file://" + inside, NewURI: "file://" + outside}},
+		}},
+	}
+
+	for _, edit := range edits {
+		applied, err := lsp.ApplyWorkspaceEdit(projectDir, edit)
+		require.ErrorIs(t, err, lsp.ErrEditNotAllowed)
+		require.Empty(t, applied)
+	}
+
+	require.ErrorIs(t, lsp.ApplyTextEdits(projectDir, "file://"+outside, []lsp.TextEdit{{NewText: "x"}}), lsp.ErrEditNotAllowed)
+
+	content, err := os.ReadFile(inside)
+	require.NoError(t, err)
+	require.Equal(t, "package main\n", string(content))
+	content, err = os.ReadFile(outside)
+	require.NoError(t, err)
+	require.Equal(t, "secret\n", string(content))
+}
+
+func TestRename_ApplySyncsOpenDocuments(t *testing.T) {
+	registerFakeLanguageServer(t)
+	gin.SetMode(gin.TestMode)
+
+	projectDir := t.TempDir()
+	file := filepath.Join(projectDir, "main.fake")
+	require.NoError(t, os.WriteFile(file, []byte("foo()\nfoo()\n"), 0644))
+	uri := "file://" + file
+
+	service := lsp.GetLSPService()
+	require.NoError(t, service.Start("fake", projectDir))
+	defer func() {
+		_ = service.Shutdown("fake", projectDir)
+	}()
+
+	server, err := service.Get("fake", projectDir)
+	require.NoError(t, err)
+	require.NoError(t, server.HandleDidOpen(context.Background(), uri))
+
+	body, err := json.Marshal(lsp.LspRenameRequest{
+		LanguageId:    "fake",
+		PathToProject: projectDir,
+		Uri:           uri,
+		Position:      lsp.Position{Line: 0, Character: 1},
+		NewName:       "bar",
+		Apply:         true,
+	})
+	require.NoError(t, err)
+
+	r := gin.New()
+	r.POST("/rename", lsp.Rename)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/rename", bytes.NewReader(body)))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	// The fake server echoes the document text it has been sent as hover contents
+	hover, err := server.HandleHover(context.Background(), uri, lsp.Position{})
+	require.NoError(t, err)
+	require.Equal(t, "bar()\nfoo()\n", hover.Contents)
+}
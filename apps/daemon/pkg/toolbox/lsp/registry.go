@@ -0,0 +1,218 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package lsp
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// LanguageServerConfig declares how to run a language server and which
+// documents it handles.
+type LanguageServerConfig struct {
+	Command string            `json:"command" validate:"required"`
+	Args    []string          `json:"args,omitempty" validate:"optional"`
+	Env     map[string]string `json:"env,omitempty" validate:"optional"`
+	// passed as initializationOptions in the initialize request
+	InitializationOptions map[string]interface{} `json:"initializationOptions,omitempty" validate:"optional"`
+	// file extensions (including the dot) of documents handled by the server
+	Extensions []string `json:"extensions,omitempty" validate:"optional"`
+	// additional language ids served by the same server, e.g. javascript for typescript
+	Aliases []string `json:"aliases,omitempty" validate:"optional"`
+} // @name LanguageServerConfig
+
+type registryFile struct {
+	Servers map[string]*LanguageServerConfig `json:"servers"`
+}
+
+// Registry maps language ids to language server configurations. It is seeded
+// with the built-in servers and extended by the daemon's LSP config file.
+type Registry struct {
+	mu      sync.RWMutex
+	servers map[string]*LanguageServerConfig
+	// language ids registered on top of the built-in servers
+	configured map[string]bool
+	// lowercase file extensions and aliases to the language id of the server
+	// handling them
+	extensions map[string]string
+	aliases    map[string]string
+}
+
+func defaultLanguageServers() map[string]*LanguageServerConfig {
+	return map[string]*LanguageServerConfig{
+		"typescript": {
+			Command:    "typescript-language-server",
+			Args:       []string{"--stdio"},
+			Extensions: []string{".ts", ".tsx", ".js", ".jsx", ".mjs", ".cjs"},
+			Aliases:    []string{"javascript", "typescriptreact", "javascriptreact"},
+		},
+		"python": {
+			Command:    "pylsp",
+			Extensions: []string{".py", ".pyi"},
+		},
+		"go": {
+			Command:    "gopls",
+			Extensions: []string{".go"},
+		},
+		"rust": {
+			Command:    "rust-analyzer",
+			Extensions: []string{".rs"},
+		},
+		"cpp": {
+			Command:    "clangd",
+			Extensions: []string{".c", ".h", ".cc", ".cpp", ".cxx", ".hpp", ".hh"},
+			Aliases:    []string{"c"},
+		},
+		"java": {
+			Command:    "jdtls",
+			Extensions: []string{".java"},
+		},
+	}
+}
+
+func NewRegistry() *Registry {
+	r := &Registry{
+		servers:    defaultLanguageServers(),
+		configured: make(map[string]bool),
+	}
+	r.index()
+	return r
+}
+
+// LoadFile merges the servers declared in a JSON config file into the registry.
+// Entries override built-in servers with the same language id. A missing file
+// is not an error.
+func (r *Registry) LoadFile(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read LSP config file: %w", err)
+	}
+
+	var file registryFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		return fmt.Errorf("failed to parse LSP config file %s: %w", path, err)
+	}
+
+	for languageId, config := range file.Servers {
+		if err := r.Register(languageId, config); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Registry) Register(languageId string, config *LanguageServerConfig) error {
+	if languageId == "" {
+		return fmt.Errorf("language id is required")
+	}
+	if config == nil || config.Command == "" {
+		return fmt.Errorf("command is required for language server %s", languageId)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.servers[languageId] = config
+	r.configured[languageId] = true
+	r.index()
+	return nil
+}
+
+// index rebuilds the extension and alias lookups. Configured servers take
+// precedence over built-in ones, among servers of the same kind the first
+// language id in alphabetical order wins and the conflict is logged.
+func (r *Registry) index() {
+	var builtIn, configured []string
+	for id := range r.servers {
+		if r.configured[id] {
+			configured = append(configured, id)
+		} else {
+			builtIn = append(builtIn, id)
+		}
+	}
+	sort.Strings(builtIn)
+	sort.Strings(configured)
+
+	r.extensions = make(map[string]string)
+	r.aliases = make(map[string]string)
+	for _, ids := range [][]string{builtIn, configured} {
+		r.indexNames(r.extensions, ids, "extension", func(config *LanguageServerConfig) []string {
+			return config.Extensions
+		})
+		r.indexNames(r.aliases, ids, "alias", func(config *LanguageServerConfig) []string {
+			return config.Aliases
+		})
+	}
+}
+
+func (r *Registry) indexNames(index map[string]string, ids []string, kind string, names func(*LanguageServerConfig) []string) {
+	claimed := make(map[string]string)
+	for _, id := range ids {
+		for _, name := range names(r.servers[id]) {
+			name = strings.ToLower(name)
+			if other, ok := claimed[name]; ok && other != id {
+				log.Warnf("The %s and %s language servers both handle the %s %s, using %s", other, id, kind, name, other)
+				continue
+			}
+			claimed[name] = id
+			index[name] = id
+		}
+	}
+}
+
+// Resolve returns the language id of the server handling languageId, which
+// may be the id itself or one of its aliases, and its configuration.
+func (r *Registry) Resolve(languageId string) (string, *LanguageServerConfig, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if config, ok := r.servers[languageId]; ok {
+		return languageId, config, true
+	}
+
+	if id, ok := r.aliases[strings.ToLower(languageId)]; ok {
+		return id, r.servers[id], true
+	}
+
+	return "", nil, false
+}
+
+// ResolveFile returns the language id of the server handling the file at path
+// based on its extension.
+func (r *Registry) ResolveFile(path string) (string, bool) {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == "" {
+		return "", false
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	id, ok := r.extensions[ext]
+	return id, ok
+}
+
+func (r *Registry) Languages() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	languages := make([]string, 0, len(r.servers))
+	for id := range r.servers {
+		languages = append(languages, id)
+	}
+	sort.Strings(languages)
+
+	return languages
+}
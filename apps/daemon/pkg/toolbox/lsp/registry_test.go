@@ -0,0 +1,90 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package lsp_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/daytonaio/daemon/pkg/toolbox/lsp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Defaults(t *testing.T) {
+	registry := lsp.NewRegistry()
+
+	id, config, ok := registry.Resolve("javascript")
+	require.True(t, ok)
+	require.Equal(t, "typescript", id)
+	require.Equal(t, "typescript-language-server", config.Command)
+
+	id, ok = registry.ResolveFile("/workspace/main.go")
+	require.True(t, ok)
+	require.Equal(t, "go", id)
+
+	_, _, ok = registry.Resolve("cobol")
+	require.False(t, ok)
+}
+
+func TestRegistry_LoadFile(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "lsp.json")
+	require.NoError(t, os.WriteFile(configPath, []byte(`{
+		"servers": {
+			"go": {"command": "/opt/gopls", "args": ["serve"], "initializationOptions": {"staticcheck": true}},
+			"zig": {"command": "zls", "extensions": [".zig"]}
+		}
+	}`), 0644))
+
+	registry := lsp.NewRegistry()
+	require.NoError(t, registry.LoadFile(configPath))
+
+	_, config, ok := registry.Resolve("go")
+	require.True(t, ok)
+	require.Equal(t, "/opt/gopls", config.Command)
+	require.Equal(t, []string{"serve"}, config.Args)
+	require.Equal(t, true, config.InitializationOptions["staticcheck"])
+
+	id, ok := registry.ResolveFile("build.ZIG")
+	require.True(t, ok)
+	require.Equal(t, "zig", id)
+
+	require.NoError(t, registry.LoadFile(filepath.Join(t.TempDir(), "missing.json")))
+
+	require.NoError(t, os.WriteFile(configPath, []byte(`{"servers": {"bad": {}}}`), 0644))
+	require.Error(t, registry.LoadFile(configPath))
+}
+
+func TestRegistry_ResolveFilePrecedence(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "lsp.json")
+	require.NoError(t, os.WriteFile(configPath, []byte(`{
+		"servers": {
+			"deno": {"command": "deno", "args": ["lsp"], "extensions": [".ts", ".mts"]},
+			"vue": {"command": "vue-language-server", "extensions": [".vue", ".mts"]}
+		}
+	}`), 0644))
+
+	registry := lsp.NewRegistry()
+	require.NoError(t, registry.LoadFile(configPath))
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "index.ts", want: "deno"},
+		{path: "index.tsx", want: "typescript"},
+		{path: "index.mts", want: "deno"},
+		{path: "App.vue", want: "vue"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			for range 10 {
+				id, ok := registry.ResolveFile(tt.path)
+				require.True(t, ok)
+				require.Equal(t, tt.want, id)
+			}
+		})
+	}
+}
@@ -5,25 +5,50 @@ package lsp
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"sync"
 )
 
 type LSPServer interface {
 	Initialize(pathToProject string) error
 	IsInitialized() bool
 	Shutdown() error
+	Kill() error
+	Info() LspServerInfo
 
 	HandleDidOpen(ctx context.Context, uri string) error
 	HandleDidClose(ctx context.Context, uri string) error
+	HandleDidChange(ctx context.Context, uri string, changes []TextDocumentContentChangeEvent) error
+	HandleDidSave(ctx context.Context, uri string) error
+	IsDocumentOpen(uri string) bool
 	HandleCompletions(ctx context.Context, params CompletionParams) (*CompletionList, error)
 	HandleDocumentSymbols(ctx context.Context, uri string) ([]LspSymbol, error)
 	HandleWorkspaceSymbols(ctx context.Context, query string) ([]LspSymbol, error)
+	HandleDefinition(ctx context.Context, uri string, position Position) ([]LspLocation, error)
+	HandleReferences(ctx context.Context, uri string, position Position, includeDeclaration bool) ([]LspLocation, error)
+	HandleHover(ctx context.Context, uri string, position Position) (*LspHover, error)
+	HandleSignatureHelp(ctx context.Context, uri string, position Position) (*LspSignatureHelp, error)
+	HandleRename(ctx context.Context, uri string, position Position, newName string) (*WorkspaceEdit, error)
+	HandleFormatting(ctx context.Context, uri string, options FormattingOptions) ([]TextEdit, error)
+	HandleCodeActions(ctx context.Context, uri string, rng Range, diagnostics []Diagnostic, only []string) ([]CodeAction, error)
 }
 
 type LSPServerAbstract struct {
-	client *Client
-
+	// guards client and initialized, which change when the server restarts
+	mu          sync.RWMutex
+	client      *Client
 	languageId  string
 	initialized bool
+
+	documentsMu sync.Mutex
+	documents   map[string]*openDocument
+}
+
+// openDocument is the content of a document as last sent to the server.
+type openDocument struct {
+	version int
+	content string
 }
 
 // Add new request types
@@ -32,27 +57,168 @@ type WorkspaceSymbolRequest struct {
 }
 
 func (s *LSPServerAbstract) IsInitialized() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	return s.initialized
 }
 
-func (s *LSPServerAbstract) HandleDidOpen(ctx context.Context, uri string) error {
-	if err := s.client.DidOpen(ctx, uri, s.languageId); err != nil {
-		return err
+func (s *LSPServerAbstract) getClient() *Client {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.client
+}
+
+func (s *LSPServerAbstract) setClient(client *Client) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.client = client
+}
+
+func (s *LSPServerAbstract) setInitialized(initialized bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.initialized = initialized
+}
+
+// replayDocuments reopens the tracked documents, e.g. after the server was
+// restarted.
+func (s *LSPServerAbstract) replayDocuments(ctx context.Context) error {
+	s.documentsMu.Lock()
+	defer s.documentsMu.Unlock()
+
+	client := s.getClient()
+	for uri, document := range s.documents {
+		if err := client.DidOpen(ctx, uri, s.languageId, document.version, document.content); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
+func (s *LSPServerAbstract) openDocumentCount() int {
+	s.documentsMu.Lock()
+	defer s.documentsMu.Unlock()
+
+	return len(s.documents)
+}
+
+func (s *LSPServerAbstract) HandleDidOpen(ctx context.Context, uri string) error {
+	content, err := os.ReadFile(uriToPath(uri))
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	s.documentsMu.Lock()
+	_, open := s.documents[uri]
+	s.documentsMu.Unlock()
+
+	// Reopening a document resyncs its content from disk
+	if open {
+		return s.HandleDidChange(ctx, uri, []TextDocumentContentChangeEvent{{Text: string(content)}})
+	}
+
+	s.documentsMu.Lock()
+	defer s.documentsMu.Unlock()
+
+	if s.documents == nil {
+		s.documents = make(map[string]*openDocument)
+	}
+	s.documents[uri] = &openDocument{
+		version: 1,
+		content: string(content),
+	}
+
+	return s.getClient().DidOpen(ctx, uri, s.languageId, 1, string(content))
+}
+
 func (s *LSPServerAbstract) HandleDidClose(ctx context.Context, uri string) error {
-	if err := s.client.NotifyDidClose(ctx, uri); err != nil {
+	s.documentsMu.Lock()
+	delete(s.documents, uri)
+	s.documentsMu.Unlock()
+
+	if err := s.getClient().NotifyDidClose(ctx, uri); err != nil {
 		return err
 	}
 
 	return nil
 }
 
+// HandleDidChange applies the changes to the tracked content of an open
+// document and forwards them using the sync kind the server asked for.
+func (s *LSPServerAbstract) HandleDidChange(ctx context.Context, uri string, changes []TextDocumentContentChangeEvent) error {
+	s.documentsMu.Lock()
+	defer s.documentsMu.Unlock()
+
+	document, ok := s.documents[uri]
+	if !ok {
+		return fmt.Errorf("document is not open: %s", uri)
+	}
+
+	content := document.content
+	for _, change := range changes {
+		if change.Range == nil {
+			content = change.Text
+			continue
+		}
+
+		var err error
+		content, err = applyTextEdits(content, []TextEdit{{Range: *change.Range, NewText: change.Text}})
+		if err != nil {
+			return fmt.Errorf("invalid content change: %w", err)
+		}
+	}
+
+	document.version++
+	document.content = content
+
+	client := s.getClient()
+	switch client.sync.Change {
+	case TextDocumentSyncNone:
+		return nil
+	case TextDocumentSyncFull:
+		changes = []TextDocumentContentChangeEvent{{Text: content}}
+	}
+
+	return client.DidChange(ctx, uri, document.version, changes)
+}
+
+func (s *LSPServerAbstract) HandleDidSave(ctx context.Context, uri string) error {
+	s.documentsMu.Lock()
+	defer s.documentsMu.Unlock()
+
+	document, ok := s.documents[uri]
+	if !ok {
+		return fmt.Errorf("document is not open: %s", uri)
+	}
+
+	client := s.getClient()
+	if !client.sync.Save {
+		return nil
+	}
+
+	var text *string
+	if client.sync.SaveIncludeText {
+		text = &document.content
+	}
+
+	return client.DidSave(ctx, uri, text)
+}
+
+func (s *LSPServerAbstract) IsDocumentOpen(uri string) bool {
+	s.documentsMu.Lock()
+	defer s.documentsMu.Unlock()
+
+	_, ok := s.documents[uri]
+	return ok
+}
+
 func (s *LSPServerAbstract) HandleCompletions(ctx context.Context, params CompletionParams) (*CompletionList, error) {
-	completions, err := s.client.GetCompletion(
+	completions, err := s.getClient().GetCompletion(
 		ctx,
 		params.TextDocument.URI,
 		params.Position,
@@ -66,7 +232,7 @@ func (s *LSPServerAbstract) HandleCompletions(ctx context.Context, params Comple
 }
 
 func (s *LSPServerAbstract) HandleDocumentSymbols(ctx context.Context, uri string) ([]LspSymbol, error) {
-	symbols, err := s.client.GetDocumentSymbols(ctx, uri)
+	symbols, err := s.getClient().GetDocumentSymbols(ctx, uri)
 	if err != nil {
 		return nil, err
 	}
@@ -75,10 +241,38 @@ func (s *LSPServerAbstract) HandleDocumentSymbols(ctx context.Context, uri strin
 }
 
 func (s *LSPServerAbstract) HandleWorkspaceSymbols(ctx context.Context, query string) ([]LspSymbol, error) {
-	symbols, err := s.client.GetWorkspaceSymbols(ctx, query)
+	symbols, err := s.getClient().GetWorkspaceSymbols(ctx, query)
 	if err != nil {
 		return nil, err
 	}
 
 	return symbols, nil
 }
+
+func (s *LSPServerAbstract) HandleDefinition(ctx context.Context, uri string, position Position) ([]LspLocation, error) {
+	return s.getClient().GetDefinition(ctx, uri, position)
+}
+
+func (s *LSPServerAbstract) HandleReferences(ctx context.Context, uri string, position Position, includeDeclaration bool) ([]LspLocation, error) {
+	return s.getClient().GetReferences(ctx, uri, position, includeDeclaration)
+}
+
+func (s *LSPServerAbstract) HandleHover(ctx context.Context, uri string, position Position) (*LspHover, error) {
+	return s.getClient().GetHover(ctx, uri, position)
+}
+
+func (s *LSPServerAbstract) HandleSignatureHelp(ctx context.Context, uri string, position Position) (*LspSignatureHelp, error) {
+	return s.getClient().GetSignatureHelp(ctx, uri, position)
+}
+
+func (s *LSPServerAbstract) HandleRename(ctx context.Context, uri string, position Position, newName string) (*WorkspaceEdit, error) {
+	return s.getClient().Rename(ctx, uri, position, newName)
+}
+
+func (s *LSPServerAbstract) HandleFormatting(ctx context.Context, uri string, options FormattingOptions) ([]TextEdit, error) {
+	return s.getClient().Formatting(ctx, uri, options)
+}
+
+func (s *LSPServerAbstract) HandleCodeActions(ctx context.Context, uri string, rng Range, diagnostics []Diagnostic, only []string) ([]CodeAction, error) {
+	return s.getClient().GetCodeActions(ctx, uri, rng, diagnostics, only)
+}
@@ -4,13 +4,41 @@
 package lsp
 
 import (
+	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
+	"os"
+	"sort"
 	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultIdleTTL  = 30 * time.Minute
+	minReapInterval = time.Second
+	maxReapInterval = time.Minute
 )
 
 type LSPService struct {
-	servers map[string]LSPServer
+	mu          sync.Mutex
+	servers     map[string]*managedServer
+	registry    *Registry
+	diagnostics *DiagnosticsStore
+	idleTTL     time.Duration
+	// wakes the idle reaper when the TTL changes
+	idleTTLChanged chan struct{}
+}
+
+// managedServer tracks when a server was last used so that idle servers can
+// be shut down.
+type managedServer struct {
+	server        LSPServer
+	languageId    string
+	pathToProject string
+	lastUsed      time.Time
 }
 
 var (
@@ -21,65 +49,261 @@ var (
 func GetLSPService() *LSPService {
 	once.Do(func() {
 		instance = &LSPService{
-			servers: make(map[string]LSPServer),
+			servers:        make(map[string]*managedServer),
+			registry:       NewRegistry(),
+			diagnostics:    NewDiagnosticsStore(),
+			idleTTL:        defaultIdleTTL,
+			idleTTLChanged: make(chan struct{}, 1),
 		}
+		go instance.reapIdleServers()
 	})
 	return instance
 }
 
-func (s *LSPService) Get(languageId string, pathToProject string) (LSPServer, error) {
-	key := generateKey(languageId, pathToProject)
+func (s *LSPService) Registry() *Registry {
+	return s.registry
+}
 
-	if server, ok := s.servers[key]; ok {
-		return server, nil
-	}
+func (s *LSPService) Diagnostics() *DiagnosticsStore {
+	return s.diagnostics
+}
+
+// SetIdleTTL sets how long a server may go unused before it is shut down.
+// A zero TTL keeps servers running until they are stopped.
+func (s *LSPService) SetIdleTTL(ttl time.Duration) {
+	s.mu.Lock()
+	s.idleTTL = ttl
+	s.mu.Unlock()
 
-	switch languageId {
-	case "typescript":
-		server := NewTypeScriptLSPServer()
-		s.servers[key] = server
-		return server, nil
-	case "python":
-		server := NewPythonLSPServer()
-		s.servers[key] = server
-		return server, nil
+	select {
+	case s.idleTTLChanged <- struct{}{}:
 	default:
+	}
+}
+
+func (s *LSPService) Get(languageId string, pathToProject string) (LSPServer, error) {
+	serverLanguageId, config, ok := s.registry.Resolve(languageId)
+	if !ok {
 		return nil, fmt.Errorf("unsupported language: %s", languageId)
 	}
+
+	key := generateKey(serverLanguageId, pathToProject)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if managed, ok := s.servers[key]; ok {
+		managed.lastUsed = time.Now()
+		return managed.server, nil
+	}
+
+	server := NewLanguageServer(serverLanguageId, config, s.diagnostics)
+	s.servers[key] = &managedServer{
+		server:        server,
+		languageId:    serverLanguageId,
+		pathToProject: pathToProject,
+		lastUsed:      time.Now(),
+	}
+	return server, nil
 }
 
 func (s *LSPService) Start(languageId string, pathToProject string) error {
-	key := generateKey(languageId, pathToProject)
+	server, err := s.Get(languageId, pathToProject)
+	if err != nil {
+		return err
+	}
 
-	server, ok := s.servers[key]
-	if ok {
-		if server.IsInitialized() {
-			return nil
-		}
-	} else {
-		newServer := NewTypeScriptLSPServer()
-		s.servers[key] = newServer
-		server = newServer
+	if server.IsInitialized() {
+		return nil
 	}
 
-	err := server.Initialize(pathToProject)
+	err = server.Initialize(pathToProject)
 	if err != nil {
-		return fmt.Errorf("failed to create TypeScript LSP server: %w", err)
+		return fmt.Errorf("failed to create %s LSP server: %w", languageId, err)
 	}
 
 	return nil
 }
 
 func (s *LSPService) Shutdown(languageId string, pathToProject string) error {
-	key := generateKey(languageId, pathToProject)
+	serverLanguageId, _, ok := s.registry.Resolve(languageId)
+	if !ok {
+		return fmt.Errorf("unsupported language: %s", languageId)
+	}
+
+	key := generateKey(serverLanguageId, pathToProject)
+
+	s.mu.Lock()
+	managed, ok := s.servers[key]
+	delete(s.servers, key)
+	s.mu.Unlock()
 
-	server, ok := s.servers[key]
 	if !ok {
 		return fmt.Errorf("no server for language: %s", languageId)
 	}
-	err := server.Shutdown()
-	delete(s.servers, key)
-	return err
+	return managed.server.Shutdown()
+}
+
+// ShutdownAll sends a shutdown request to every running server and waits
+// for them to exit. Servers still running once ctx is done are killed.
+func (s *LSPService) ShutdownAll(ctx context.Context) error {
+	s.mu.Lock()
+	servers := make([]*managedServer, 0, len(s.servers))
+	for key, managed := range s.servers {
+		servers = append(servers, managed)
+		delete(s.servers, key)
+	}
+	s.mu.Unlock()
+
+	errs := make(chan error, len(servers))
+	for _, managed := range servers {
+		go func() {
+			if !managed.server.IsInitialized() {
+				errs <- nil
+				return
+			}
+			errs <- managed.server.Shutdown()
+		}()
+	}
+
+	var shutdownErrs []error
+	for range servers {
+		select {
+		case err := <-errs:
+			if err != nil {
+				shutdownErrs = append(shutdownErrs, err)
+			}
+		case <-ctx.Done():
+			for _, managed := range servers {
+				if err := managed.server.Kill(); err != nil {
+					shutdownErrs = append(shutdownErrs, err)
+				}
+			}
+			return errors.Join(append(shutdownErrs, ctx.Err())...)
+		}
+	}
+
+	return errors.Join(shutdownErrs...)
+}
+
+// Servers returns the status of every server known to the service.
+func (s *LSPService) Servers() []LspServerInfo {
+	type entry struct {
+		managed  *managedServer
+		lastUsed time.Time
+	}
+
+	s.mu.Lock()
+	entries := make([]entry, 0, len(s.servers))
+	for _, managed := range s.servers {
+		entries = append(entries, entry{managed: managed, lastUsed: managed.lastUsed})
+	}
+	s.mu.Unlock()
+
+	servers := make([]LspServerInfo, 0, len(entries))
+	for _, e := range entries {
+		info := e.managed.server.Info()
+		info.LanguageId = e.managed.languageId
+		info.PathToProject = e.managed.pathToProject
+		info.LastUsedAt = e.lastUsed
+		servers = append(servers, info)
+	}
+
+	sort.Slice(servers, func(i, j int) bool {
+		if servers[i].PathToProject != servers[j].PathToProject {
+			return servers[i].PathToProject < servers[j].PathToProject
+		}
+		return servers[i].LanguageId < servers[j].LanguageId
+	})
+
+	return servers
+}
+
+// SyncFile notifies every language server that has the file open that its
+// content changed on disk and was saved. It is called for files written
+// through the toolbox file system API and by applied LSP edits.
+func (s *LSPService) SyncFile(path string) {
+	uri := "file://" + path
+
+	s.mu.Lock()
+	servers := make([]LSPServer, 0, len(s.servers))
+	for _, managed := range s.servers {
+		servers = append(servers, managed.server)
+	}
+	s.mu.Unlock()
+
+	var content []byte
+	for _, server := range servers {
+		if !server.IsInitialized() || !server.IsDocumentOpen(uri) {
+			continue
+		}
+
+		if content == nil {
+			var err error
+			content, err = os.ReadFile(path)
+			if err != nil {
+				log.Errorf("failed to read %s for LSP sync: %v", path, err)
+				return
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		err := server.HandleDidChange(ctx, uri, []TextDocumentContentChangeEvent{{Text: string(content)}})
+		if err == nil {
+			err = server.HandleDidSave(ctx, uri)
+		}
+		cancel()
+		if err != nil {
+			log.Errorf("failed to sync %s with language server: %v", path, err)
+		}
+	}
+}
+
+// reapIdleServers periodically shuts down servers that have not been used
+// for longer than the idle TTL.
+func (s *LSPService) reapIdleServers() {
+	for {
+		s.mu.Lock()
+		ttl := s.idleTTL
+		s.mu.Unlock()
+
+		interval := ttl / 4
+		if interval < minReapInterval {
+			interval = minReapInterval
+		} else if interval > maxReapInterval {
+			interval = maxReapInterval
+		}
+		select {
+		case <-time.After(interval):
+		case <-s.idleTTLChanged:
+			continue
+		}
+
+		if ttl <= 0 {
+			continue
+		}
+
+		s.mu.Lock()
+		idle := []*managedServer{}
+		for key, managed := range s.servers {
+			if time.Since(managed.lastUsed) > ttl {
+				idle = append(idle, managed)
+				delete(s.servers, key)
+			}
+		}
+		s.mu.Unlock()
+
+		for _, managed := range idle {
+			if !managed.server.IsInitialized() {
+				continue
+			}
+
+			log.Infof("Shutting down idle %s language server for %s", managed.languageId, managed.pathToProject)
+			if err := managed.server.Shutdown(); err != nil {
+				log.Errorf("failed to shut down idle %s language server: %v", managed.languageId, err)
+			}
+		}
+	}
 }
 
 func generateKey(languageId, pathToProject string) string {
@@ -0,0 +1,88 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package lsp_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/daytonaio/daemon/pkg/toolbox/lsp"
+	"github.com/stretchr/testify/require"
+)
+
+func findServer(service *lsp.LSPService, pathToProject string) (lsp.LspServerInfo, bool) {
+	for _, info := range service.Servers() {
+		if info.PathToProject == pathToProject {
+			return info, true
+		}
+	}
+	return lsp.LspServerInfo{}, false
+}
+
+func TestSupervisor_RestartsCrashedServer(t *testing.T) {
+	registerFakeLanguageServer(t)
+
+	projectDir := t.TempDir()
+	file := filepath.Join(projectDir, "main.fake")
+	require.NoError(t, os.WriteFile(file, []byte("foo()\n"), 0644))
+	uri := "file://" + file
+
+	service := lsp.GetLSPService()
+	require.NoError(t, service.Start("fake", projectDir))
+	defer func() {
+		_ = service.Shutdown("fake", projectDir)
+	}()
+
+	server, err := service.Get("fake", projectDir)
+	require.NoError(t, err)
+	require.NoError(t, server.HandleDidOpen(context.Background(), uri))
+
+	info, ok := findServer(service, projectDir)
+	require.True(t, ok)
+	require.Equal(t, "running", info.State)
+	require.Equal(t, "fake", info.LanguageId)
+	require.Equal(t, 1, info.OpenDocuments)
+	require.NotZero(t, info.Pid)
+
+	require.NoError(t, syscall.Kill(info.Pid, syscall.SIGKILL))
+
+	require.Eventually(t, func() bool {
+		restarted, ok := findServer(service, projectDir)
+		return ok && restarted.State == "running" && restarted.Restarts == 1 && restarted.Pid != info.Pid
+	}, 10*time.Second, 50*time.Millisecond)
+
+	// Open documents are replayed to the new process
+	hover, err := server.HandleHover(context.Background(), uri, lsp.Position{})
+	require.NoError(t, err)
+	require.Equal(t, "foo()\n", hover.Contents)
+}
+
+func TestSupervisor_ShutsDownIdleServers(t *testing.T) {
+	registerFakeLanguageServer(t)
+
+	projectDir := t.TempDir()
+
+	service := lsp.GetLSPService()
+	require.NoError(t, service.Start("fake", projectDir))
+
+	info, ok := findServer(service, projectDir)
+	require.True(t, ok)
+
+	service.SetIdleTTL(100 * time.Millisecond)
+	defer service.SetIdleTTL(30 * time.Minute)
+
+	require.Eventually(t, func() bool {
+		_, ok := findServer(service, projectDir)
+		return !ok
+	}, 10*time.Second, 50*time.Millisecond)
+
+	// The process has exited
+	require.Eventually(t, func() bool {
+		return syscall.Kill(info.Pid, 0) != nil
+	}, 10*time.Second, 50*time.Millisecond)
+}
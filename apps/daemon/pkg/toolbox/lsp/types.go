@@ -3,6 +3,8 @@
 
 package lsp
 
+import "time"
+
 type LspServerRequest struct {
 	LanguageId    string `json:"languageId" validate:"required"`
 	PathToProject string `json:"pathToProject" validate:"required"`
@@ -14,6 +16,15 @@ type LspDocumentRequest struct {
 	Uri           string `json:"uri" validate:"required"`
 } // @name LspDocumentRequest
 
+type LspDidChangeRequest struct {
+	LanguageId     string                           `json:"languageId" validate:"required"`
+	PathToProject  string                           `json:"pathToProject" validate:"required"`
+	Uri            string                           `json:"uri" validate:"required"`
+	ContentChanges []TextDocumentContentChangeEvent `json:"contentChanges" validate:"required"`
+	// also notify the server that the document was saved
+	Save bool `json:"save,omitempty" validate:"optional"`
+} // @name LspDidChangeRequest
+
 type LspCompletionParams struct {
 	LanguageId    string             `json:"languageId" validate:"required"`
 	PathToProject string             `json:"pathToProject" validate:"required"`
@@ -21,3 +32,75 @@ type LspCompletionParams struct {
 	Position      Position           `json:"position" validate:"required"`
 	Context       *CompletionContext `json:"context,omitempty" validate:"optional"`
 } // @name LspCompletionParams
+
+type LspServerInfo struct {
+	LanguageId    string `json:"languageId" validate:"required"`
+	PathToProject string `json:"pathToProject" validate:"required"`
+	// running, restarting, failed or stopped
+	State         string     `json:"state" validate:"required"`
+	Pid           int        `json:"pid,omitempty" validate:"optional"`
+	MemoryBytes   uint64     `json:"memoryBytes,omitempty" validate:"optional"`
+	StartedAt     *time.Time `json:"startedAt,omitempty" validate:"optional"`
+	UptimeSeconds int64      `json:"uptimeSeconds,omitempty" validate:"optional"`
+	Restarts      int        `json:"restarts" validate:"required"`
+	OpenDocuments int        `json:"openDocuments" validate:"required"`
+	LastUsedAt    time.Time  `json:"lastUsedAt" validate:"required"`
+} // @name LspServerInfo
+
+type LspServersResponse struct {
+	Servers []LspServerInfo `json:"servers" validate:"required"`
+} // @name LspServersResponse
+
+type LspLanguagesResponse struct {
+	Languages []string `json:"languages" validate:"required"`
+} // @name LspLanguagesResponse
+
+type LspPositionRequest struct {
+	LanguageId    string   `json:"languageId" validate:"required"`
+	PathToProject string   `json:"pathToProject" validate:"required"`
+	Uri           string   `json:"uri" validate:"required"`
+	Position      Position `json:"position" validate:"required"`
+} // @name LspPositionRequest
+
+type LspReferencesRequest struct {
+	LanguageId         string   `json:"languageId" validate:"required"`
+	PathToProject      string   `json:"pathToProject" validate:"required"`
+	Uri                string   `json:"uri" validate:"required"`
+	Position           Position `json:"position" validate:"required"`
+	IncludeDeclaration bool     `json:"includeDeclaration,omitempty" validate:"optional"`
+} // @name LspReferencesRequest
+
+type LspRenameRequest struct {
+	LanguageId    string   `json:"languageId" validate:"required"`
+	PathToProject string   `json:"pathToProject" validate:"required"`
+	Uri           string   `json:"uri" validate:"required"`
+	Position      Position `json:"position" validate:"required"`
+	NewName       string   `json:"newName" validate:"required"`
+	// write the resulting edit to disk
+	Apply bool `json:"apply,omitempty" validate:"optional"`
+} // @name LspRenameRequest
+
+type LspRenameResponse struct {
+	Edit *WorkspaceEdit `json:"edit" validate:"required"`
+	// URIs of the documents modified on disk when apply is set
+	Applied []string `json:"applied,omitempty" validate:"optional"`
+} // @name LspRenameResponse
+
+type LspFormattingRequest struct {
+	LanguageId    string             `json:"languageId" validate:"required"`
+	PathToProject string             `json:"pathToProject" validate:"required"`
+	Uri           string             `json:"uri" validate:"required"`
+	Options       *FormattingOptions `json:"options,omitempty" validate:"optional"`
+	// write the resulting edits to disk
+	Apply bool `json:"apply,omitempty" validate:"optional"`
+} // @name LspFormattingRequest
+
+type LspCodeActionRequest struct {
+	LanguageId    string       `json:"languageId" validate:"required"`
+	PathToProject string       `json:"pathToProject" validate:"required"`
+	Uri           string       `json:"uri" validate:"required"`
+	Range         Range        `json:"range" validate:"required"`
+	Diagnostics   []Diagnostic `json:"diagnostics,omitempty" validate:"optional"`
+	// code action kinds to request, e.g. quickfix or source.organizeImports
+	Only []string `json:"only,omitempty" validate:"optional"`
+} // @name LspCodeActionRequest
@@ -0,0 +1,29 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package toolbox
+
+import (
+	"github.com/daytonaio/daemon/pkg/telemetry"
+	"github.com/daytonaio/daemon/pkg/toolbox/lsp"
+)
+
+// lspServerCounts counts the language servers known to the LSP service by
+// language and state.
+func lspServerCounts() []telemetry.LabeledValue {
+	type key struct{ language, state string }
+
+	counts := map[key]float64{}
+	for _, server := range lsp.GetLSPService().Servers() {
+		counts[key{server.LanguageId, server.State}]++
+	}
+
+	values := make([]telemetry.LabeledValue, 0, len(counts))
+	for k, count := range counts {
+		values = append(values, telemetry.LabeledValue{
+			Labels: []string{k.language, k.state},
+			Value:  count,
+		})
+	}
+	return values
+}
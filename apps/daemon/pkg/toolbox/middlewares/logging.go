@@ -6,6 +6,7 @@ package middlewares
 import (
 	"time"
 
+	"github.com/daytonaio/common-go/pkg/logs"
 	"github.com/gin-gonic/gin"
 
 	log "github.com/sirupsen/logrus"
@@ -22,30 +23,34 @@ func LoggingMiddleware() gin.HandlerFunc {
 		reqMethod := ctx.Request.Method
 		reqUri := ctx.Request.RequestURI
 		statusCode := ctx.Writer.Status()
+		requestId := logs.RequestIdFromContext(ctx)
 
 		if len(ctx.Errors) > 0 {
 			log.WithFields(log.Fields{
-				"method":  reqMethod,
-				"URI":     reqUri,
-				"status":  statusCode,
-				"latency": latencyTime,
-				"error":   ctx.Errors.String(),
+				"method":            reqMethod,
+				"URI":               reqUri,
+				"status":            statusCode,
+				"latency":           latencyTime,
+				logs.RequestIdField: requestId,
+				"error":             ctx.Errors.String(),
 			}).Error("API ERROR")
 		} else {
 			fullPath := ctx.FullPath()
 			if ignoreLoggingPaths[fullPath] {
 				log.WithFields(log.Fields{
-					"method":  reqMethod,
-					"URI":     reqUri,
-					"status":  statusCode,
-					"latency": latencyTime,
+					"method":            reqMethod,
+					"URI":               reqUri,
+					"status":            statusCode,
+					"latency":           latencyTime,
+					logs.RequestIdField: requestId,
 				}).Debug("API REQUEST")
 			} else {
 				log.WithFields(log.Fields{
-					"method":  reqMethod,
-					"URI":     reqUri,
-					"status":  statusCode,
-					"latency": latencyTime,
+					"method":            reqMethod,
+					"URI":               reqUri,
+					"status":            statusCode,
+					"latency":           latencyTime,
+					logs.RequestIdField: requestId,
 				}).Info("API REQUEST")
 			}
 		}
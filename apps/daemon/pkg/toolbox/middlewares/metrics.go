@@ -0,0 +1,31 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package middlewares
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/daytonaio/daemon/pkg/telemetry"
+	"github.com/gin-gonic/gin"
+)
+
+func MetricsMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		startTime := time.Now()
+		ctx.Next()
+
+		// Label by route template rather than path to keep cardinality bounded
+		route := ctx.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		telemetry.RequestDuration.WithLabelValues(
+			ctx.Request.Method,
+			route,
+			strconv.Itoa(ctx.Writer.Status()),
+		).Observe(time.Since(startTime).Seconds())
+	}
+}
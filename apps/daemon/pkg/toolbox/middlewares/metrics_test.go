@@ -0,0 +1,31 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package middlewares_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/daytonaio/daemon/pkg/telemetry"
+	"github.com/daytonaio/daemon/pkg/toolbox/middlewares"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsMiddleware_LabelsByRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(middlewares.MetricsMiddleware())
+	r.GET("/files/info/:path", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for _, path := range []string{"/files/info/a", "/files/info/b", "/missing"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	// Both requests share the route template, unmatched paths share one series
+	require.Equal(t, 2, testutil.CollectAndCount(telemetry.RequestDuration))
+}
@@ -0,0 +1,26 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package middlewares
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StreamMiddleware cancels the request context of long-lived streams once
+// shutdown is done. Draining the server would otherwise wait for streams that
+// only end when their client disconnects.
+func StreamMiddleware(shutdown context.Context) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		requestCtx, cancel := context.WithCancel(ctx.Request.Context())
+		defer cancel()
+
+		stop := context.AfterFunc(shutdown, cancel)
+		defer stop()
+
+		ctx.Request = ctx.Request.WithContext(requestCtx)
+		ctx.Next()
+	}
+}
@@ -9,104 +9,269 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"slices"
+	"sort"
 	"strconv"
+	"sync"
 	"time"
 
-	"github.com/cakturk/go-netstat/netstat"
 	"github.com/gin-gonic/gin"
 	cmap "github.com/orcaman/concurrent-map/v2"
 )
 
 type portsDetector struct {
-	portMap cmap.ConcurrentMap[string, bool]
+	// keyed by protocol and port, e.g. tcp/8080
+	portMap cmap.ConcurrentMap[string, PortInfo]
+	ranges  PortRanges
+	// ports of the daemon's own servers, never reported
+	reservedPorts []uint
+
+	subscribersMu sync.Mutex
+	subscribers   map[chan PortEvent]struct{}
+	// Set once the detector stopped and closed the subscriber channels
+	stopped bool
 }
 
-func NewPortsDetector() *portsDetector {
+// NewPortsDetector creates a detector reporting listeners on ports within
+// ranges, except for the reserved ports. All ports are reported when no
+// ranges are given.
+func NewPortsDetector(ranges PortRanges, reservedPorts []int) *portsDetector {
+	if len(ranges) == 0 {
+		ranges = PortRanges{AllPorts}
+	}
+
+	reserved := make([]uint, 0, len(reservedPorts))
+	for _, port := range reservedPorts {
+		reserved = append(reserved, uint(port))
+	}
+
 	return &portsDetector{
-		portMap: cmap.New[bool](),
+		portMap:       cmap.New[PortInfo](),
+		ranges:        ranges,
+		reservedPorts: reserved,
+		subscribers:   make(map[chan PortEvent]struct{}),
 	}
 }
 
+// Start scans for listeners every second until ctx is done, then closes the
+// channels of the event subscribers.
 func (d *portsDetector) Start(ctx context.Context) {
+	defer d.closeSubscribers()
+
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		default:
-			time.Sleep(1 * time.Second)
-			// get only listening TCP sockets
-			tabs, err := netstat.TCPSocks(func(s *netstat.SockTabEntry) bool {
-				return s.State == netstat.Listen
-			})
+		case <-time.After(1 * time.Second):
+			freshMap, err := d.scan()
 			if err != nil {
 				continue
 			}
 
-			freshMap := map[string]bool{}
-			for _, e := range tabs {
-				s := strconv.Itoa(int(e.LocalAddr.Port))
-				freshMap[s] = true
-				d.portMap.Set(s, true)
+			d.resolveProcesses(freshMap)
+
+			for key, info := range freshMap {
+				existing, ok := d.portMap.Get(key)
+				if ok && existing.inode == info.inode {
+					continue
+				}
+				d.portMap.Set(key, info)
+
+				// Ports found by IsPortInUse have no bind address and are only
+				// enriched with process details
+				switch {
+				case !ok:
+					d.publish(PortEvent{Type: PortEventOpened, Timestamp: time.Now(), PortInfo: info})
+				case existing.BindAddress != "" && listenerChanged(existing, info):
+					d.publish(PortEvent{Type: PortEventChanged, Timestamp: time.Now(), PortInfo: info})
+				}
 			}
 
-			for _, port := range d.portMap.Keys() {
-				if !freshMap[port] {
-					d.portMap.Remove(port)
+			for key, info := range d.portMap.Items() {
+				if _, ok := freshMap[key]; !ok {
+					d.portMap.Remove(key)
+					d.publish(PortEvent{Type: PortEventClosed, Timestamp: time.Now(), PortInfo: info})
 				}
 			}
 		}
 	}
 }
 
-func (d *portsDetector) GetPorts(c *gin.Context) {
-	ports := PortList{
-		Ports: []uint{},
+// resolveProcesses sets the PID and command of the listeners whose socket is
+// not known yet. The processes of known sockets are kept from the last scan.
+func (d *portsDetector) resolveProcesses(freshMap map[string]PortInfo) {
+	inodes := map[string]struct{}{}
+	for key, info := range freshMap {
+		if existing, ok := d.portMap.Get(key); ok && existing.inode == info.inode {
+			continue
+		}
+		inodes[info.inode] = struct{}{}
+	}
+
+	pids := socketPids(inodes)
+
+	for key, info := range freshMap {
+		if existing, ok := d.portMap.Get(key); ok && existing.inode == info.inode {
+			freshMap[key] = existing
+			continue
+		}
+		if pid, ok := pids[info.inode]; ok {
+			info.Pid = pid
+			info.Command = processCommand(pid)
+			freshMap[key] = info
+		}
 	}
+}
+
+// listenerChanged reports whether the fresh scan found another listener than
+// the one known. An unknown PID is not a change, the process may not be
+// visible to the daemon.
+func listenerChanged(existing, fresh PortInfo) bool {
+	if existing.BindAddress != fresh.BindAddress {
+		return true
+	}
+	return fresh.Pid != 0 && fresh.Pid != existing.Pid
+}
 
-	for _, port := range d.portMap.Keys() {
-		portInt, err := strconv.Atoi(port)
+// scan lists the TCP and UDP listeners over IPv4 and IPv6. It fails only when
+// no socket table could be read, e.g. IPv6 tables are missing when IPv6 is
+// disabled.
+func (d *portsDetector) scan() (map[string]PortInfo, error) {
+	freshMap := map[string]PortInfo{}
+
+	var errs []error
+	for _, table := range socketTables {
+		sockets, err := readSockets(table)
 		if err != nil {
+			errs = append(errs, err)
 			continue
 		}
-		ports.Ports = append(ports.Ports, uint(portInt))
+
+		for _, s := range sockets {
+			if !d.isDetected(s.port) {
+				continue
+			}
+
+			key := portKey(table.protocol, s.port)
+			// Prefer the entry accepting connections on all interfaces
+			if info, ok := freshMap[key]; ok && !info.Loopback {
+				continue
+			}
+			freshMap[key] = PortInfo{
+				Port:        s.port,
+				Protocol:    table.protocol,
+				BindAddress: s.ip.String(),
+				Loopback:    s.ip.IsLoopback(),
+				inode:       s.inode,
+			}
+		}
+	}
+
+	if len(errs) == len(socketTables) {
+		return nil, errors.Join(errs...)
+	}
+
+	return freshMap, nil
+}
+
+func (d *portsDetector) isDetected(port uint) bool {
+	return d.ranges.Contains(port) && !slices.Contains(d.reservedPorts, port)
+}
+
+func portKey(protocol Protocol, port uint) string {
+	return fmt.Sprintf("%s/%d", protocol, port)
+}
+
+func (d *portsDetector) GetPorts(c *gin.Context) {
+	ports := PortList{
+		Ports:   []uint{},
+		Entries: []PortInfo{},
+	}
+
+	for _, info := range d.sortedPorts() {
+		ports.Entries = append(ports.Entries, info)
+		if info.Protocol == ProtocolTCP {
+			ports.Ports = append(ports.Ports, info.Port)
+		}
 	}
 
 	c.JSON(http.StatusOK, ports)
 }
 
+// sortedPorts returns the ports in use ordered by port and protocol
+func (d *portsDetector) sortedPorts() []PortInfo {
+	ports := make([]PortInfo, 0, d.portMap.Count())
+	for _, info := range d.portMap.Items() {
+		ports = append(ports, info)
+	}
+
+	sort.Slice(ports, func(i, j int) bool {
+		if ports[i].Port != ports[j].Port {
+			return ports[i].Port < ports[j].Port
+		}
+		return ports[i].Protocol < ports[j].Protocol
+	})
+
+	return ports
+}
+
 func (d *portsDetector) IsPortInUse(c *gin.Context) {
 	portParam := c.Param("port")
 
-	port, err := strconv.Atoi(portParam)
-	if err != nil {
-		c.AbortWithError(http.StatusBadRequest, errors.New("invalid port: must be a number between 3000 and 9999"))
+	port, err := strconv.ParseUint(portParam, 10, 16)
+	if err != nil || port == 0 {
+		c.AbortWithError(http.StatusBadRequest, errors.New("invalid port: must be a number between 1 and 65535"))
+		return
+	}
+
+	if !d.ranges.Contains(uint(port)) {
+		c.AbortWithError(http.StatusBadRequest, fmt.Errorf("port out of range: must be within %s", d.ranges))
 		return
 	}
 
-	if port < 3000 || port > 9999 {
-		c.AbortWithError(http.StatusBadRequest, errors.New("port out of range: must be between 3000 and 9999"))
+	if slices.Contains(d.reservedPorts, uint(port)) {
+		c.AbortWithError(http.StatusBadRequest, fmt.Errorf("port %d is reserved by the daemon", port))
 		return
 	}
 
-	portStr := strconv.Itoa(port)
+	protocol := Protocol(c.DefaultQuery("protocol", string(ProtocolTCP)))
+	if protocol != ProtocolTCP && protocol != ProtocolUDP {
+		c.AbortWithError(http.StatusBadRequest, fmt.Errorf("invalid protocol: %s", protocol))
+		return
+	}
 
-	if d.portMap.Has(portStr) {
+	key := portKey(protocol, uint(port))
+
+	if d.portMap.Has(key) {
 		c.JSON(http.StatusOK, IsPortInUseResponse{
 			IsInUse: true,
 		})
-	} else {
-		// If the port is not in the map, we check synchronously if it's in use and update the map
-		_, err := net.DialTimeout("tcp", fmt.Sprintf("localhost:%d", port), 50*time.Millisecond)
-		if err != nil {
-			c.JSON(http.StatusOK, IsPortInUseResponse{
-				IsInUse: false,
-			})
-		} else {
-			d.portMap.Set(portStr, true)
-			c.JSON(http.StatusOK, IsPortInUseResponse{
-				IsInUse: true,
-			})
-		}
+		return
 	}
 
+	// UDP listeners cannot be probed by connecting, rely on the detector
+	if protocol == ProtocolUDP {
+		c.JSON(http.StatusOK, IsPortInUseResponse{
+			IsInUse: false,
+		})
+		return
+	}
+
+	// If the port is not in the map, we check synchronously if it's in use and update the map
+	conn, err := net.DialTimeout("tcp", fmt.Sprintf("localhost:%d", port), 50*time.Millisecond)
+	if err != nil {
+		c.JSON(http.StatusOK, IsPortInUseResponse{
+			IsInUse: false,
+		})
+		return
+	}
+	conn.Close()
+
+	info := PortInfo{Port: uint(port), Protocol: ProtocolTCP}
+	d.portMap.Set(key, info)
+	d.publish(PortEvent{Type: PortEventOpened, Timestamp: time.Now(), PortInfo: info})
+
+	c.JSON(http.StatusOK, IsPortInUseResponse{
+		IsInUse: true,
+	})
 }
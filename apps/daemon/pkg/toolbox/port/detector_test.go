@@ -0,0 +1,233 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package port_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/daytonaio/daemon/pkg/toolbox/port"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPortsDetector_Events(t *testing.T) {
+	detector := port.NewPortsDetector(nil, nil)
+	events, unsubscribe := detector.Subscribe()
+	defer unsubscribe()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go detector.Start(ctx)
+
+	listener, err := net.Listen("tcp4", "127.0.0.1:0")
+	require.NoError(t, err)
+	listenPort := uint(listener.Addr().(*net.TCPAddr).Port)
+
+	next := func(eventType port.PortEventType, protocol port.Protocol, listenPort uint) port.PortEvent {
+		for {
+			select {
+			case event := <-events:
+				if event.Port == listenPort && event.Protocol == protocol && event.Type == eventType {
+					return event
+				}
+			case <-time.After(10 * time.Second):
+				t.Fatalf("timed out waiting for %s event", eventType)
+			}
+		}
+	}
+
+	opened := next(port.PortEventOpened, port.ProtocolTCP, listenPort)
+	require.True(t, opened.Loopback)
+	require.Equal(t, "127.0.0.1", opened.BindAddress)
+	require.Equal(t, os.Getpid(), opened.Pid)
+	require.NotEmpty(t, opened.Command)
+
+	require.NoError(t, listener.Close())
+	next(port.PortEventClosed, port.ProtocolTCP, listenPort)
+
+	udpConn, err := net.ListenPacket("udp6", "[::1]:0")
+	if err != nil {
+		t.Skipf("IPv6 is not available: %v", err)
+	}
+	defer udpConn.Close()
+
+	opened = next(port.PortEventOpened, port.ProtocolUDP, uint(udpConn.LocalAddr().(*net.UDPAddr).Port))
+	require.Equal(t, "::1", opened.BindAddress)
+}
+
+func TestPortsDetector_ChangedEvents(t *testing.T) {
+	detector := port.NewPortsDetector(nil, nil)
+	events, unsubscribe := detector.Subscribe()
+	defer unsubscribe()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go detector.Start(ctx)
+
+	listener, err := net.Listen("tcp4", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	listenPort := listener.Addr().(*net.TCPAddr).Port
+
+	next := func(eventType port.PortEventType) port.PortEvent {
+		for {
+			select {
+			case event := <-events:
+				if event.Port == uint(listenPort) && event.Protocol == port.ProtocolTCP && event.Type == eventType {
+					return event
+				}
+			case <-time.After(10 * time.Second):
+				t.Fatalf("timed out waiting for %s event", eventType)
+			}
+		}
+	}
+
+	require.Equal(t, "127.0.0.1", next(port.PortEventOpened).BindAddress)
+
+	// Listeners on IPv6 are scanned after IPv4 ones and replace loopback entries
+	listener6, err := net.Listen("tcp6", fmt.Sprintf("[::1]:%d", listenPort))
+	if err != nil {
+		t.Skipf("IPv6 is not available: %v", err)
+	}
+	defer listener6.Close()
+
+	require.Equal(t, "::1", next(port.PortEventChanged).BindAddress)
+}
+
+func TestPortsDetector_SubscribeSendsOpenPorts(t *testing.T) {
+	detector := port.NewPortsDetector(nil, nil)
+	events, unsubscribe := detector.Subscribe()
+	defer unsubscribe()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go detector.Start(ctx)
+
+	listener, err := net.Listen("tcp4", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	listenPort := uint(listener.Addr().(*net.TCPAddr).Port)
+
+	for opened := false; !opened; {
+		select {
+		case event := <-events:
+			opened = event.Port == listenPort && event.Type == port.PortEventOpened
+		case <-time.After(10 * time.Second):
+			t.Fatal("timed out waiting for the opened event")
+		}
+	}
+
+	// A late subscriber receives the open ports right away
+	late, unsubscribeLate := detector.Subscribe()
+	defer unsubscribeLate()
+
+	for {
+		select {
+		case event := <-late:
+			require.Equal(t, port.PortEventOpened, event.Type)
+			if event.Port == listenPort {
+				return
+			}
+		default:
+			t.Fatal("the open port was not sent on subscribe")
+		}
+	}
+}
+
+func TestPortsDetector_ReservedPorts(t *testing.T) {
+	reserved, err := net.Listen("tcp4", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer reserved.Close()
+	reservedPort := reserved.Addr().(*net.TCPAddr).Port
+
+	detector := port.NewPortsDetector(nil, []int{reservedPort})
+	events, unsubscribe := detector.Subscribe()
+	defer unsubscribe()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go detector.Start(ctx)
+
+	listener, err := net.Listen("tcp4", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	listenPort := uint(listener.Addr().(*net.TCPAddr).Port)
+
+	// Both listeners are found by the same scan
+	for opened := false; !opened; {
+		select {
+		case event := <-events:
+			require.NotEqual(t, uint(reservedPort), event.Port)
+			opened = event.Port == listenPort
+		case <-time.After(10 * time.Second):
+			t.Fatal("timed out waiting for the opened event")
+		}
+	}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/port", detector.GetPorts)
+	r.GET("/port/:port/in-use", detector.IsPortInUse)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/port", nil))
+	var ports port.PortList
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &ports))
+	require.Contains(t, ports.Ports, listenPort)
+	require.NotContains(t, ports.Ports, uint(reservedPort))
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/port/%d/in-use", reservedPort), nil))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestPortsDetector_StopClosesSubscriptions(t *testing.T) {
+	detector := port.NewPortsDetector(nil, nil)
+	events, unsubscribe := detector.Subscribe()
+	defer unsubscribe()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		detector.Start(ctx)
+		close(stopped)
+	}()
+	cancel()
+
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("detector did not stop")
+	}
+
+	for range events {
+	}
+
+	late, unsubscribeLate := detector.Subscribe()
+	defer unsubscribeLate()
+	_, ok := <-late
+	require.False(t, ok)
+}
+
+func TestParsePortRanges(t *testing.T) {
+	ranges, err := port.ParsePortRanges("80, 443,3000-9999")
+	require.NoError(t, err)
+	require.True(t, ranges.Contains(443))
+	require.True(t, ranges.Contains(5432))
+	require.False(t, ranges.Contains(10000))
+	require.Equal(t, "80,443,3000-9999", ranges.String())
+
+	_, err = port.ParsePortRanges("9999-3000")
+	require.Error(t, err)
+	_, err = port.ParsePortRanges("70000")
+	require.Error(t, err)
+}
@@ -0,0 +1,137 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package port
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool {
+		return true
+	},
+}
+
+// Subscribe returns a channel receiving every port event and a function that
+// must be called to stop receiving them. The channel first receives an opened
+// event for every port already in use, a port opened while subscribing may be
+// reported twice. The channel is closed when the detector stops.
+func (d *portsDetector) Subscribe() (<-chan PortEvent, func()) {
+	d.subscribersMu.Lock()
+
+	ports := d.sortedPorts()
+	ch := make(chan PortEvent, len(ports)+64)
+	if d.stopped {
+		close(ch)
+	} else {
+		now := time.Now()
+		for _, info := range ports {
+			ch <- PortEvent{Type: PortEventOpened, Timestamp: now, PortInfo: info}
+		}
+		d.subscribers[ch] = struct{}{}
+	}
+
+	d.subscribersMu.Unlock()
+
+	return ch, func() {
+		d.subscribersMu.Lock()
+		delete(d.subscribers, ch)
+		d.subscribersMu.Unlock()
+	}
+}
+
+func (d *portsDetector) closeSubscribers() {
+	d.subscribersMu.Lock()
+	defer d.subscribersMu.Unlock()
+
+	d.stopped = true
+	for ch := range d.subscribers {
+		close(ch)
+		delete(d.subscribers, ch)
+	}
+}
+
+func (d *portsDetector) publish(event PortEvent) {
+	d.subscribersMu.Lock()
+	defer d.subscribersMu.Unlock()
+
+	for ch := range d.subscribers {
+		select {
+		case ch <- event:
+		default:
+			// Drop events for slow subscribers rather than blocking detection
+		}
+	}
+}
+
+// GetPortEvents streams port opened and closed events over a WebSocket, or as
+// server-sent events for plain HTTP requests, starting with an opened event
+// for every port already in use.
+func (d *portsDetector) GetPortEvents(c *gin.Context) {
+	events, unsubscribe := d.Subscribe()
+	defer unsubscribe()
+
+	if c.Request.Header.Get("Upgrade") == "websocket" {
+		streamPortEvents(c, events)
+		return
+	}
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			c.SSEvent(string(event.Type), event)
+			return true
+		}
+	})
+}
+
+func streamPortEvents(c *gin.Context, events <-chan PortEvent) {
+	ws, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	defer ws.Close()
+
+	// Detect the client closing the connection
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := ws.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := ws.WriteJSON(event); err != nil {
+				log.Debug(err)
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,21 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package port
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// processCommand returns the command line of a process, or an empty string
+// when it cannot be read.
+func processCommand(pid int) string {
+	cmdline, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid))
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(strings.ReplaceAll(string(cmdline), "\x00", " "))
+}
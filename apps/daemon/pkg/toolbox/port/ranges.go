@@ -0,0 +1,82 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package port
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type PortRange struct {
+	Start uint
+	End   uint
+}
+
+var AllPorts = PortRange{Start: 1, End: 65535}
+
+type PortRanges []PortRange
+
+// ParsePortRanges parses a comma separated list of ports and port ranges,
+// e.g. "80,443,3000-9999".
+func ParsePortRanges(value string) (PortRanges, error) {
+	ranges := PortRanges{}
+
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		start, end, isRange := strings.Cut(part, "-")
+		if !isRange {
+			end = start
+		}
+
+		startPort, err := parsePort(start)
+		if err != nil {
+			return nil, err
+		}
+		endPort, err := parsePort(end)
+		if err != nil {
+			return nil, err
+		}
+		if endPort < startPort {
+			return nil, fmt.Errorf("invalid port range: %s", part)
+		}
+
+		ranges = append(ranges, PortRange{Start: startPort, End: endPort})
+	}
+
+	return ranges, nil
+}
+
+func parsePort(value string) (uint, error) {
+	port, err := strconv.ParseUint(strings.TrimSpace(value), 10, 16)
+	if err != nil || port == 0 {
+		return 0, fmt.Errorf("invalid port: %s", value)
+	}
+	return uint(port), nil
+}
+
+func (r PortRanges) Contains(port uint) bool {
+	for _, portRange := range r {
+		if port >= portRange.Start && port <= portRange.End {
+			return true
+		}
+	}
+	return false
+}
+
+func (r PortRanges) String() string {
+	parts := make([]string, 0, len(r))
+	for _, portRange := range r {
+		if portRange.Start == portRange.End {
+			parts = append(parts, strconv.FormatUint(uint64(portRange.Start), 10))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", portRange.Start, portRange.End))
+		}
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,163 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package port
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	stateListen = 0x0a
+	stateClose  = 0x07
+)
+
+type socketTable struct {
+	protocol Protocol
+	path     string
+	// UDP sockets have no listening state, unconnected bound sockets are
+	// reported as closed
+	state uint64
+}
+
+var socketTables = []socketTable{
+	{protocol: ProtocolTCP, path: "/proc/net/tcp", state: stateListen},
+	{protocol: ProtocolTCP, path: "/proc/net/tcp6", state: stateListen},
+	{protocol: ProtocolUDP, path: "/proc/net/udp", state: stateClose},
+	{protocol: ProtocolUDP, path: "/proc/net/udp6", state: stateClose},
+}
+
+type socket struct {
+	ip    net.IP
+	port  uint
+	inode string
+}
+
+// readSockets returns the listening sockets of a socket table.
+func readSockets(table socketTable) ([]socket, error) {
+	file, err := os.Open(table.path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	sockets := []socket{}
+
+	scanner := bufio.NewScanner(file)
+	// Discard the header
+	scanner.Scan()
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 {
+			return nil, fmt.Errorf("%s: not enough fields: %v", table.path, fields)
+		}
+
+		state, err := strconv.ParseUint(fields[3], 16, 8)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid state %s", table.path, fields[3])
+		}
+		if state != table.state {
+			continue
+		}
+
+		_, remotePort, err := parseSocketAddress(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", table.path, err)
+		}
+		if remotePort != 0 {
+			continue
+		}
+
+		ip, port, err := parseSocketAddress(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", table.path, err)
+		}
+
+		sockets = append(sockets, socket{ip: ip, port: port, inode: fields[9]})
+	}
+
+	return sockets, scanner.Err()
+}
+
+// parseSocketAddress parses an address of a socket table, e.g.
+// 0100007F:1F90. IPv4 addresses are one and IPv6 addresses four 32-bit words
+// in host byte order.
+func parseSocketAddress(value string) (net.IP, uint, error) {
+	address, portValue, ok := strings.Cut(value, ":")
+	if !ok {
+		return nil, 0, fmt.Errorf("invalid address %s", value)
+	}
+
+	ip, err := hex.DecodeString(address)
+	if err != nil || (len(ip) != net.IPv4len && len(ip) != net.IPv6len) {
+		return nil, 0, fmt.Errorf("invalid address %s", value)
+	}
+	for i := 0; i < len(ip); i += 4 {
+		ip[i], ip[i+1], ip[i+2], ip[i+3] = ip[i+3], ip[i+2], ip[i+1], ip[i]
+	}
+
+	port, err := strconv.ParseUint(portValue, 16, 16)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid address %s", value)
+	}
+
+	return net.IP(ip), uint(port), nil
+}
+
+// socketPids returns the PIDs of the processes holding the sockets with the
+// given inodes. It reads the file descriptors of every process, so callers
+// should only pass sockets they have not resolved before.
+func socketPids(inodes map[string]struct{}) map[string]int {
+	pids := map[string]int{}
+	if len(inodes) == 0 {
+		return pids
+	}
+
+	procs, err := os.ReadDir("/proc")
+	if err != nil {
+		return pids
+	}
+
+	for _, proc := range procs {
+		pid, err := strconv.Atoi(proc.Name())
+		if err != nil {
+			continue
+		}
+
+		fdDir := filepath.Join("/proc", proc.Name(), "fd")
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue
+		}
+
+		for _, fd := range fds {
+			link, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
+			if err != nil {
+				continue
+			}
+
+			inode, ok := strings.CutPrefix(link, "socket:[")
+			if !ok {
+				continue
+			}
+			inode = strings.TrimSuffix(inode, "]")
+
+			if _, ok := inodes[inode]; ok {
+				pids[inode] = pid
+				if len(pids) == len(inodes) {
+					return pids
+				}
+			}
+		}
+	}
+
+	return pids
+}
@@ -3,10 +3,51 @@
 
 package port
 
+import "time"
+
 type PortList struct {
+	// TCP ports with a listener
 	Ports []uint `json:"ports"`
+	// every TCP and UDP listener
+	Entries []PortInfo `json:"entries"`
 } // @name PortList
 
 type IsPortInUseResponse struct {
 	IsInUse bool `json:"isInUse"`
 } // @name IsPortInUseResponse
+
+type Protocol string
+
+const (
+	ProtocolTCP Protocol = "tcp"
+	ProtocolUDP Protocol = "udp"
+)
+
+type PortInfo struct {
+	Port     uint     `json:"port" validate:"required"`
+	Protocol Protocol `json:"protocol" validate:"required"`
+	// PID and command line of the listening process, if known
+	Pid         int    `json:"pid,omitempty" validate:"optional"`
+	Command     string `json:"command,omitempty" validate:"optional"`
+	BindAddress string `json:"bindAddress,omitempty" validate:"optional"`
+	// true when the port only accepts connections from within the sandbox
+	Loopback bool `json:"loopback" validate:"required"`
+
+	// inode of the listening socket, a new inode is a new listener
+	inode string
+} // @name PortInfo
+
+type PortEventType string
+
+const (
+	PortEventOpened PortEventType = "opened"
+	PortEventClosed PortEventType = "closed"
+	// another process now listens on the port, or on another address
+	PortEventChanged PortEventType = "changed"
+)
+
+type PortEvent struct {
+	Type      PortEventType `json:"type" validate:"required"`
+	Timestamp time.Time     `json:"timestamp" validate:"required"`
+	PortInfo
+} // @name PortEvent
@@ -12,10 +12,24 @@ import (
 
 	log "github.com/sirupsen/logrus"
 
+	"github.com/daytonaio/daemon/pkg/telemetry"
+	"github.com/daytonaio/daemon/pkg/toolbox/config"
 	"github.com/gin-gonic/gin"
 )
 
-func ExecuteCommand(c *gin.Context) {
+// ExecuteCommand runs a command to completion, killing it once the request
+// timeout or defaultTimeout elapses.
+func ExecuteCommand(defaultTimeout time.Duration) gin.HandlerFunc {
+	if defaultTimeout <= 0 {
+		defaultTimeout = config.DEFAULT_EXECUTE_TIMEOUT
+	}
+
+	return func(c *gin.Context) {
+		executeCommand(c, defaultTimeout)
+	}
+}
+
+func executeCommand(c *gin.Context, defaultTimeout time.Duration) {
 	var request ExecuteRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
 		c.AbortWithError(http.StatusBadRequest, errors.New("command is required"))
@@ -28,13 +42,16 @@ func ExecuteCommand(c *gin.Context) {
 		return
 	}
 
+	telemetry.ExecutionsInFlight.WithLabelValues(telemetry.ExecutionKindExecute).Inc()
+	defer telemetry.ExecutionsInFlight.WithLabelValues(telemetry.ExecutionKindExecute).Dec()
+
 	cmd := exec.Command(cmdParts[0], cmdParts[1:]...)
 	if request.Cwd != nil {
 		cmd.Dir = *request.Cwd
 	}
 
 	// set maximum execution time
-	timeout := 360 * time.Second
+	timeout := defaultTimeout
 	if request.Timeout != nil && *request.Timeout > 0 {
 		timeout = time.Duration(*request.Timeout) * time.Second
 	}
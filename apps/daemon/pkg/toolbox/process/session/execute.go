@@ -14,10 +14,14 @@ import (
 	"time"
 
 	"github.com/daytonaio/daemon/internal/util"
+	"github.com/daytonaio/daemon/pkg/telemetry"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
+// Interval at which async commands are checked for completion
+const asyncCommandPollInterval = 250 * time.Millisecond
+
 // Add a standard error response struct
 type ErrorResponse struct {
 	Error string `json:"error"`
@@ -82,13 +86,23 @@ func (s *SessionController) SessionExecuteCommand(c *gin.Context) {
 		return
 	}
 
+	telemetry.ExecutionsInFlight.WithLabelValues(telemetry.ExecutionKindSession).Inc()
+
 	if request.RunAsync {
+		// Async commands are counted until their exit code is written
+		go func() {
+			defer telemetry.ExecutionsInFlight.WithLabelValues(telemetry.ExecutionKindSession).Dec()
+			waitForExitCode(session, exitCodeFilePath)
+		}()
+
 		c.JSON(http.StatusAccepted, SessionExecuteResponse{
 			CommandId: cmdId,
 		})
 		return
 	}
 
+	defer telemetry.ExecutionsInFlight.WithLabelValues(telemetry.ExecutionKindSession).Dec()
+
 	for {
 		select {
 		case <-session.ctx.Done():
@@ -132,3 +146,22 @@ func (s *SessionController) SessionExecuteCommand(c *gin.Context) {
 		}
 	}
 }
+
+// waitForExitCode returns once the exit code file of a command exists or the
+// session ended
+func waitForExitCode(session *session, exitCodeFilePath string) {
+	ticker := time.NewTicker(asyncCommandPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := os.Stat(exitCodeFilePath); err == nil {
+			return
+		}
+
+		select {
+		case <-session.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
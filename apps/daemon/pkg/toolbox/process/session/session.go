@@ -12,8 +12,10 @@ import (
 	"os/exec"
 	"strconv"
 	"strings"
+	"syscall"
 
 	"github.com/daytonaio/daemon/pkg/common"
+	"github.com/daytonaio/daemon/pkg/telemetry"
 	"github.com/gin-gonic/gin"
 )
 
@@ -25,6 +27,12 @@ func (s *SessionController) CreateSession(c *gin.Context) {
 	cmd := exec.CommandContext(ctx, common.GetShell())
 	cmd.Env = os.Environ()
 	cmd.Dir = s.projectDir
+	// Run the shell in its own process group so that commands it started can
+	// be signalled along with it
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
 
 	var request CreateSessionRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
@@ -60,8 +68,14 @@ func (s *SessionController) CreateSession(c *gin.Context) {
 		commands:    map[string]*Command{},
 		ctx:         ctx,
 		cancel:      cancel,
+		exited:      make(chan struct{}),
 	}
+	go func() {
+		_ = cmd.Wait()
+		close(session.exited)
+	}()
 	sessions[request.SessionId] = session
+	telemetry.ProcessSessions.Inc()
 
 	err = os.MkdirAll(session.Dir(s.configDir), 0755)
 	if err != nil {
@@ -83,6 +97,7 @@ func (s *SessionController) DeleteSession(c *gin.Context) {
 
 	session.cancel()
 	session.deleted = true
+	telemetry.ProcessSessions.Dec()
 
 	err := os.RemoveAll(session.Dir(s.configDir))
 	if err != nil {
@@ -0,0 +1,82 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+
+	"github.com/daytonaio/daemon/pkg/telemetry"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Exit code recorded for commands that were still running when the daemon
+// shut down, as if they had been terminated by SIGTERM
+const shutdownExitCode = 128 + int(syscall.SIGTERM)
+
+// Shutdown terminates every session shell. Shells and the commands they run
+// are sent SIGTERM and killed if they have not exited once ctx is done.
+// Commands that did not finish get an exit code so that clients polling them
+// see them complete.
+func (s *SessionController) Shutdown(ctx context.Context) error {
+	var errs []error
+
+	for _, session := range sessions {
+		if session.deleted {
+			continue
+		}
+
+		if err := syscall.Kill(-session.cmd.Process.Pid, syscall.SIGTERM); err != nil && !errors.Is(err, syscall.ESRCH) {
+			log.Errorf("Failed to signal session %s: %v", session.id, err)
+		}
+	}
+
+	for _, session := range sessions {
+		if session.deleted {
+			continue
+		}
+
+		select {
+		case <-session.exited:
+		case <-ctx.Done():
+			log.Warnf("Session %s did not exit in time, killing it", session.id)
+		}
+		// Kills the process group if it is still running and stops log
+		// streams following the session
+		session.cancel()
+		<-session.exited
+
+		if err := s.flushExitCodes(session); err != nil {
+			errs = append(errs, err)
+		}
+		session.deleted = true
+		telemetry.ProcessSessions.Dec()
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *SessionController) flushExitCodes(session *session) error {
+	for _, command := range session.commands {
+		if command.ExitCode != nil {
+			continue
+		}
+
+		_, exitCodeFilePath := command.LogFilePath(session.Dir(s.configDir))
+		if _, err := os.Stat(exitCodeFilePath); err == nil {
+			continue
+		}
+
+		err := os.WriteFile(exitCodeFilePath, []byte(fmt.Sprintf("%d\n", shutdownExitCode)), 0644)
+		if err != nil {
+			return fmt.Errorf("failed to write exit code of command %s: %w", command.Id, err)
+		}
+	}
+
+	return nil
+}
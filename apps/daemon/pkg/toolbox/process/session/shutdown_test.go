@@ -0,0 +1,66 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package session_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/daytonaio/daemon/pkg/telemetry"
+	"github.com/daytonaio/daemon/pkg/toolbox/process/session"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShutdown_TerminatesRunningCommands(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	configDir := t.TempDir()
+	controller := session.NewSessionController(configDir, t.TempDir())
+	r := gin.New()
+	r.POST("/session", controller.CreateSession)
+	r.POST("/session/:sessionId/exec", controller.SessionExecuteCommand)
+
+	request := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return w
+	}
+
+	require.Equal(t, http.StatusCreated, request(http.MethodPost, "/session", `{"sessionId":"shutdown"}`).Code)
+
+	inFlight := telemetry.ExecutionsInFlight.WithLabelValues(telemetry.ExecutionKindSession)
+	before := testutil.ToFloat64(inFlight)
+
+	w := request(http.MethodPost, "/session/shutdown/exec", `{"command":"sleep 30","runAsync":true}`)
+	require.Equal(t, http.StatusAccepted, w.Code)
+	require.Equal(t, before+1, testutil.ToFloat64(inFlight))
+
+	var executed session.SessionExecuteResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &executed))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	require.NoError(t, controller.Shutdown(ctx))
+	require.Less(t, time.Since(start), 5*time.Second)
+
+	// The command is reported as terminated rather than running forever
+	exitCode, err := os.ReadFile(filepath.Join(configDir, "sessions", "shutdown", *executed.CommandId, "exit_code"))
+	require.NoError(t, err)
+	require.Equal(t, "143", strings.TrimSpace(string(exitCode)))
+
+	require.Eventually(t, func() bool {
+		return testutil.ToFloat64(inFlight) == before
+	}, 5*time.Second, 50*time.Millisecond)
+}
@@ -39,6 +39,8 @@ type session struct {
 	ctx         context.Context
 	cancel      context.CancelFunc
 	deleted     bool
+	// closed once the shell has exited
+	exited chan struct{}
 }
 
 func (s *session) Dir(configDir string) string {
@@ -6,13 +6,37 @@ package proxy
 import (
 	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
+	"slices"
+	"strconv"
 	"strings"
 
 	common_errors "github.com/daytonaio/common-go/pkg/errors"
 	"github.com/gin-gonic/gin"
 )
 
+// NewProxyTargetGetter returns the target getter of the /proxy route. The
+// reserved ports are the daemon's own servers, which must only be reachable
+// with their own authentication and not through the proxy scope.
+func NewProxyTargetGetter(reservedPorts []int) func(*gin.Context) (*url.URL, map[string]string, error) {
+	return func(ctx *gin.Context) (*url.URL, map[string]string, error) {
+		port, err := strconv.Atoi(ctx.Param("port"))
+		if err != nil || port < 1 || port > 65535 {
+			ctx.AbortWithError(http.StatusBadRequest, errors.New("invalid target port"))
+			return nil, nil, errors.New("invalid target port")
+		}
+
+		if slices.Contains(reservedPorts, port) {
+			err := fmt.Errorf("port %d cannot be proxied", port)
+			ctx.AbortWithError(http.StatusForbidden, err)
+			return nil, nil, err
+		}
+
+		return GetProxyTarget(ctx)
+	}
+}
+
 func GetProxyTarget(ctx *gin.Context) (*url.URL, map[string]string, error) {
 	targetPort := ctx.Param("port")
 	if targetPort == "" {
@@ -0,0 +1,41 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package proxy_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/daytonaio/daemon/pkg/toolbox/proxy"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProxyTargetGetter_RefusesReservedPorts(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	getTarget := proxy.NewProxyTargetGetter([]int{2280, 22222})
+
+	r := gin.New()
+	r.Any("/proxy/:port/*path", func(c *gin.Context) {
+		target, _, err := getTarget(c)
+		if err != nil {
+			return
+		}
+		c.String(http.StatusOK, target.String())
+	})
+
+	for path, status := range map[string]int{
+		"/proxy/22222/ws":  http.StatusForbidden,
+		"/proxy/2280/ws":   http.StatusForbidden,
+		"/proxy/abc/":      http.StatusBadRequest,
+		"/proxy/70000/":    http.StatusBadRequest,
+		"/proxy/3000/api/": http.StatusOK,
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		require.Equal(t, status, w.Code, path)
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package recordings
+
+import (
+	"errors"
+	"net/http"
+	"path/filepath"
+
+	"github.com/daytonaio/daemon/pkg/recording"
+	"github.com/gin-gonic/gin"
+)
+
+var errRecordingDisabled = errors.New("session recording is disabled")
+
+type RecordingsController struct {
+	store *recording.Store
+}
+
+// NewRecordingsController returns a controller serving the recordings in
+// store. A nil store means session recording is disabled.
+func NewRecordingsController(store *recording.Store) *RecordingsController {
+	return &RecordingsController{
+		store: store,
+	}
+}
+
+func (r *RecordingsController) ListRecordings(c *gin.Context) {
+	if r.store == nil {
+		c.AbortWithError(http.StatusServiceUnavailable, errRecordingDisabled)
+		return
+	}
+
+	recordings, err := r.store.List()
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, recordings)
+}
+
+func (r *RecordingsController) GetRecording(c *gin.Context) {
+	if r.store == nil {
+		c.AbortWithError(http.StatusServiceUnavailable, errRecordingDisabled)
+		return
+	}
+
+	info, err := r.store.Get(c.Param("recordingId"))
+	if err != nil {
+		abortWithRecordingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, info)
+}
+
+// DownloadRecording serves the asciicast v2 file of a recording, playable
+// with asciinema.
+func (r *RecordingsController) DownloadRecording(c *gin.Context) {
+	if r.store == nil {
+		c.AbortWithError(http.StatusServiceUnavailable, errRecordingDisabled)
+		return
+	}
+
+	path, err := r.store.Path(c.Param("recordingId"))
+	if err != nil {
+		abortWithRecordingError(c, err)
+		return
+	}
+
+	c.Header("Content-Description", "File Transfer")
+	c.Header("Content-Type", "application/x-asciicast")
+	c.Header("Content-Disposition", "attachment; filename="+filepath.Base(path))
+	c.Header("Cache-Control", "no-cache")
+
+	c.File(path)
+}
+
+func abortWithRecordingError(c *gin.Context, err error) {
+	if errors.Is(err, recording.ErrRecordingNotFound) {
+		c.AbortWithError(http.StatusNotFound, err)
+		return
+	}
+	c.AbortWithError(http.StatusInternalServerError, err)
+}
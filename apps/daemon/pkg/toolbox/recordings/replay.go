@@ -0,0 +1,175 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package recordings
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/daytonaio/daemon/pkg/recording"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool {
+		return true
+	},
+}
+
+// ReplayRecording streams the events of a recording with their original
+// timing, over a WebSocket or as server-sent events for plain HTTP requests.
+// The header is sent first. The speed query parameter scales the playback
+// speed and maxIdle caps pauses between events, in seconds.
+func (r *RecordingsController) ReplayRecording(c *gin.Context) {
+	if r.store == nil {
+		c.AbortWithError(http.StatusServiceUnavailable, errRecordingDisabled)
+		return
+	}
+
+	speed, maxIdle, err := parseReplayOptions(c)
+	if err != nil {
+		c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	file, err := r.store.Open(c.Param("recordingId"))
+	if err != nil {
+		abortWithRecordingError(c, err)
+		return
+	}
+	defer file.Close()
+
+	reader, err := recording.NewReader(file)
+	if err != nil {
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	if c.Request.Header.Get("Upgrade") == "websocket" {
+		replayOverWebSocket(c, reader, speed, maxIdle)
+		return
+	}
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("Content-Type", "text/event-stream")
+
+	c.SSEvent("header", reader.Header)
+	c.Writer.Flush()
+
+	err = replay(c.Request.Context(), reader, speed, maxIdle, func(event recording.Event) error {
+		c.SSEvent("event", event)
+		c.Writer.Flush()
+		return nil
+	})
+	if err != nil && !errors.Is(err, context.Canceled) {
+		log.Error(err)
+	}
+}
+
+func replayOverWebSocket(c *gin.Context, reader *recording.Reader, speed float64, maxIdle time.Duration) {
+	ws, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	defer ws.Close()
+
+	// Stop replaying when the client closes the connection
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := ws.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	if err := ws.WriteJSON(reader.Header); err != nil {
+		log.Debug(err)
+		return
+	}
+
+	err = replay(ctx, reader, speed, maxIdle, func(event recording.Event) error {
+		return ws.WriteJSON(event)
+	})
+	if err != nil {
+		if !errors.Is(err, context.Canceled) {
+			log.Debug(err)
+		}
+		return
+	}
+
+	err = ws.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+	if err != nil {
+		log.Trace(err)
+	}
+}
+
+// replay emits every event of the recording after waiting for the time that
+// passed between it and the previous event.
+func replay(ctx context.Context, reader *recording.Reader, speed float64, maxIdle time.Duration, emit func(recording.Event) error) error {
+	previous := 0.0
+	for {
+		event, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		wait := time.Duration((event.Time - previous) / speed * float64(time.Second))
+		if maxIdle > 0 && wait > maxIdle {
+			wait = maxIdle
+		}
+		previous = event.Time
+
+		if wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+
+		if err := emit(event); err != nil {
+			return err
+		}
+	}
+}
+
+func parseReplayOptions(c *gin.Context) (float64, time.Duration, error) {
+	speed := 1.0
+	if value := c.Query("speed"); value != "" {
+		var err error
+		speed, err = strconv.ParseFloat(value, 64)
+		if err != nil || speed <= 0 {
+			return 0, 0, fmt.Errorf("invalid speed: %s", value)
+		}
+	}
+
+	var maxIdle time.Duration
+	if value := c.Query("maxIdle"); value != "" {
+		seconds, err := strconv.ParseFloat(value, 64)
+		if err != nil || seconds < 0 {
+			return 0, 0, fmt.Errorf("invalid maxIdle: %s", value)
+		}
+		maxIdle = time.Duration(seconds * float64(time.Second))
+	}
+
+	return speed, maxIdle, nil
+}
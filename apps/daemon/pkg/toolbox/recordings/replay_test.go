@@ -0,0 +1,113 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package recordings_test
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/daytonaio/daemon/pkg/recording"
+	"github.com/daytonaio/daemon/pkg/toolbox/recordings"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+// The pauses between events are 0.1s, 0.2s and 5s
+const recordingFile = `{"version":2,"width":80,"height":24}
+[0.1,"o","a"]
+[0.3,"o","b"]
+[5.3,"o","c"]
+`
+
+func newReplayServer(t *testing.T) (*httptest.Server, string) {
+	store, err := recording.NewStore(t.TempDir())
+	require.NoError(t, err)
+
+	id := uuid.NewString()
+	require.NoError(t, os.WriteFile(filepath.Join(store.Dir(), id+".cast"), []byte(recordingFile), 0600))
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/recordings/:recordingId/replay", recordings.NewRecordingsController(store).ReplayRecording)
+
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+
+	return server, id
+}
+
+func TestReplayRecording_SSE(t *testing.T) {
+	server, id := newReplayServer(t)
+
+	// At double speed the pauses are 0.05s, 0.1s and 2.5s capped to 0.2s
+	start := time.Now()
+	resp, err := http.Get(server.URL + "/recordings/" + id + "/replay?speed=2&maxIdle=0.2")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var events []string
+	var elapsed []time.Duration
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if event, ok := strings.CutPrefix(scanner.Text(), "event:"); ok {
+			events = append(events, event)
+			elapsed = append(elapsed, time.Since(start))
+		}
+	}
+	require.NoError(t, scanner.Err())
+
+	require.Equal(t, []string{"header", "event", "event", "event"}, events)
+	require.GreaterOrEqual(t, elapsed[1], 50*time.Millisecond)
+	require.GreaterOrEqual(t, elapsed[2], 150*time.Millisecond)
+	require.GreaterOrEqual(t, elapsed[3], 350*time.Millisecond)
+	require.Less(t, elapsed[3], 2*time.Second)
+}
+
+func TestReplayRecording_WebSocket(t *testing.T) {
+	server, id := newReplayServer(t)
+
+	start := time.Now()
+	ws, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/recordings/"+id+"/replay?maxIdle=0.5", nil)
+	require.NoError(t, err)
+	defer ws.Close()
+
+	var header recording.Header
+	require.NoError(t, ws.ReadJSON(&header))
+	require.Equal(t, 80, header.Width)
+
+	var data []string
+	for {
+		var event recording.Event
+		if err := ws.ReadJSON(&event); err != nil {
+			require.True(t, websocket.IsCloseError(err, websocket.CloseNormalClosure), err)
+			break
+		}
+		data = append(data, event.Data)
+	}
+
+	// 0.1s, 0.2s and 5s capped to 0.5s
+	require.Equal(t, []string{"a", "b", "c"}, data)
+	require.GreaterOrEqual(t, time.Since(start), 800*time.Millisecond)
+	require.Less(t, time.Since(start), 3*time.Second)
+}
+
+func TestReplayRecording_InvalidOptions(t *testing.T) {
+	server, id := newReplayServer(t)
+
+	for _, query := range []string{"speed=0", "speed=fast", "maxIdle=-1"} {
+		resp, err := http.Get(server.URL + "/recordings/" + id + "/replay?" + query)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode, query)
+	}
+}
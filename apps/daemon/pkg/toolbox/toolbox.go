@@ -5,14 +5,22 @@ package toolbox
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
 	"os"
 	"path"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/daytonaio/common-go/pkg/logs"
 	common_proxy "github.com/daytonaio/common-go/pkg/proxy"
 	"github.com/daytonaio/daemon/internal"
+	"github.com/daytonaio/daemon/pkg/recording"
+	"github.com/daytonaio/daemon/pkg/telemetry"
+	"github.com/daytonaio/daemon/pkg/toolbox/auth"
 	"github.com/daytonaio/daemon/pkg/toolbox/computeruse"
 	"github.com/daytonaio/daemon/pkg/toolbox/computeruse/manager"
 	"github.com/daytonaio/daemon/pkg/toolbox/config"
@@ -24,16 +32,79 @@ import (
 	"github.com/daytonaio/daemon/pkg/toolbox/process"
 	"github.com/daytonaio/daemon/pkg/toolbox/process/session"
 	"github.com/daytonaio/daemon/pkg/toolbox/proxy"
+	"github.com/daytonaio/daemon/pkg/toolbox/recordings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 
 	log "github.com/sirupsen/logrus"
 )
 
 type Server struct {
-	ProjectDir  string
+	ProjectDir string
+	// Defaults to config.TOOLBOX_API_PORT
+	Port        int
 	ComputerUse computeruse.IComputerUse
+	// Whether to load the computer-use plugin
+	ComputerUseEnabled bool
+	// Recordings of PTY sessions, nil when session recording is disabled
+	Recordings *recording.Store
+	// Sandbox token required on every request but /version and /ready, the
+	// API is not authenticated when empty
+	Token string
+	// Default timeout of commands run through /process/execute
+	ExecuteTimeout time.Duration
+	// Port ranges reported by the port detector, all ports when empty
+	PortRanges    string
+	LSPConfigPath string
+	// Idle TTL of language servers, the LSP service default when nil
+	LSPIdleTTL *time.Duration
+	PathPolicy fs.PathPolicy
+	// Upstream settings of the /proxy route
+	Proxy common_proxy.Config
+	// Ports of the daemon's other servers (terminal, SSH) that the /proxy route
+	// refuses and the port detector does not report, the toolbox port is always
+	// excluded
+	ReservedPorts []int
+	// Effective daemon configuration served by GET /config, secrets must be
+	// redacted
+	Config any
+
+	mu                sync.Mutex
+	httpServer        *http.Server
+	sessionController *session.SessionController
+	stopPortDetector  context.CancelFunc
+	ready             atomic.Bool
+	draining          atomic.Bool
+}
+
+// GetConfig returns the effective daemon configuration.
+func (s *Server) GetConfig(ctx *gin.Context) {
+	if s.Config == nil {
+		ctx.AbortWithError(http.StatusNotFound, errors.New("daemon configuration is not available"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, s.Config)
+}
+
+type ReadinessResponse struct {
+	Status string `json:"status" validate:"required"`
+} // @name ReadinessResponse
+
+// GetReadiness reports whether the toolbox accepts new work. It turns
+// not-ready as soon as the server starts draining on shutdown.
+func (s *Server) GetReadiness(ctx *gin.Context) {
+	switch {
+	case s.draining.Load():
+		ctx.JSON(http.StatusServiceUnavailable, ReadinessResponse{Status: "draining"})
+	case !s.ready.Load():
+		ctx.JSON(http.StatusServiceUnavailable, ReadinessResponse{Status: "starting"})
+	default:
+		ctx.JSON(http.StatusOK, ReadinessResponse{Status: "ready"})
+	}
 }
 
 type ProjectDirResponse struct {
@@ -56,6 +127,9 @@ func (s *Server) Start() error {
 
 	r := gin.New()
 	r.Use(gin.Recovery())
+	r.Use(otelgin.Middleware(telemetry.ServiceName))
+	r.Use(middlewares.MetricsMiddleware())
+	r.Use(logs.RequestIdMiddleware())
 	r.Use(middlewares.LoggingMiddleware())
 	r.Use(middlewares.ErrorMiddleware())
 	binding.Validator = new(DefaultValidator)
@@ -65,8 +139,21 @@ func (s *Server) Start() error {
 			"version": internal.Version,
 		})
 	})
+	r.GET("/ready", s.GetReadiness)
+
+	// Every route registered below requires a token
+	authenticator := auth.NewAuthenticator(s.Token)
+	if !authenticator.Enabled() {
+		log.Warn("Toolbox token is not set, the toolbox API is not authenticated")
+	}
+	r.Use(auth.Middleware(authenticator))
 
 	r.GET("/project-dir", s.GetProjectDir)
+	r.GET("/config", auth.RequireScope(auth.ScopeAll), s.GetConfig)
+	r.GET("/metrics", auth.RequireScope(auth.ScopeMetrics), gin.WrapH(promhttp.Handler()))
+
+	tokenController := auth.NewTokenController(authenticator)
+	r.POST("/auth/tokens", tokenController.CreateToken)
 
 	dirname, err := os.UserHomeDir()
 	if err != nil {
@@ -81,7 +168,15 @@ func (s *Server) Start() error {
 
 	log.Println("configDir", configDir)
 
-	fsController := r.Group("/files")
+	if err := fs.SetPathPolicy(s.PathPolicy); err != nil {
+		return fmt.Errorf("invalid file system path policy: %w", err)
+	}
+
+	// Cancelled when draining starts so that streams do not hold it up
+	streamsCtx, stopStreams := context.WithCancel(context.Background())
+	stream := middlewares.StreamMiddleware(streamsCtx)
+
+	fsController := r.Group("/files", auth.RequireScope(auth.ScopeFs), fs.PathPolicyMiddleware())
 	{
 		// read operations
 		fsController.GET("/", fs.ListFiles)
@@ -102,11 +197,11 @@ func (s *Server) Start() error {
 		fsController.DELETE("/", fs.DeleteFile)
 	}
 
-	processController := r.Group("/process")
+	sessionController := session.NewSessionController(configDir, s.ProjectDir)
+	processController := r.Group("/process", auth.RequireScope(auth.ScopeProcess))
 	{
-		processController.POST("/execute", process.ExecuteCommand)
+		processController.POST("/execute", process.ExecuteCommand(s.ExecuteTimeout))
 
-		sessionController := session.NewSessionController(configDir, s.ProjectDir)
 		sessionGroup := processController.Group("/session")
 		{
 			sessionGroup.GET("", sessionController.ListSessions)
@@ -115,15 +210,28 @@ func (s *Server) Start() error {
 			sessionGroup.GET("/:sessionId", sessionController.GetSession)
 			sessionGroup.DELETE("/:sessionId", sessionController.DeleteSession)
 			sessionGroup.GET("/:sessionId/command/:commandId", sessionController.GetSessionCommand)
-			sessionGroup.GET("/:sessionId/command/:commandId/logs", sessionController.GetSessionCommandLogs)
+			sessionGroup.GET("/:sessionId/command/:commandId/logs", stream, sessionController.GetSessionCommandLogs)
 		}
 	}
 
-	gitController := r.Group("/git")
+	recordingsController := recordings.NewRecordingsController(s.Recordings)
+	recordingsGroup := r.Group("/recordings", auth.RequireScope(auth.ScopeRecordings))
 	{
+		recordingsGroup.GET("", recordingsController.ListRecordings)
+		recordingsGroup.GET("/:recordingId", recordingsController.GetRecording)
+		recordingsGroup.GET("/:recordingId/download", recordingsController.DownloadRecording)
+		recordingsGroup.GET("/:recordingId/replay", stream, recordingsController.ReplayRecording)
+	}
+
+	gitController := r.Group("/git", auth.RequireScope(auth.ScopeGit))
+	{
+		gitController.GET("/blame", git.GetBlame)
 		gitController.GET("/branches", git.ListBranches)
+		gitController.GET("/file", git.GetFileAtRevision)
 		gitController.GET("/history", git.GetCommitHistory)
 		gitController.GET("/status", git.GetStatus)
+		gitController.GET("/remotes", git.ListRemotes)
+		gitController.GET("/tags", git.ListTags)
 
 		gitController.POST("/add", git.AddFiles)
 		gitController.POST("/branches", git.CreateBranch)
@@ -133,10 +241,49 @@ func (s *Server) Start() error {
 		gitController.POST("/commit", git.CommitChanges)
 		gitController.POST("/pull", git.PullChanges)
 		gitController.POST("/push", git.PushChanges)
+		gitController.POST("/fetch", git.FetchChanges)
+
+		gitController.POST("/remotes", git.AddRemote)
+		gitController.DELETE("/remotes", git.RemoveRemote)
+
+		gitController.POST("/tags", git.CreateTag)
+		gitController.POST("/tags/push", git.PushTag)
+		gitController.DELETE("/tags", git.DeleteTag)
+
+		gitController.GET("/worktrees", git.ListWorktrees)
+		gitController.POST("/worktrees", git.AddWorktree)
+		gitController.DELETE("/worktrees", git.RemoveWorktree)
+		gitController.POST("/worktrees/lock", git.LockWorktree)
+		gitController.POST("/worktrees/unlock", git.UnlockWorktree)
+		gitController.POST("/worktrees/prune", git.PruneWorktrees)
+	}
+
+	lspConfigPath := s.LSPConfigPath
+	if lspConfigPath == "" {
+		lspConfigPath = path.Join(configDir, "lsp.json")
+	}
+	if err := lsp.GetLSPService().Registry().LoadFile(lspConfigPath); err != nil {
+		log.Errorf("Failed to load LSP config: %v", err)
 	}
+	if s.LSPIdleTTL != nil {
+		lsp.GetLSPService().SetIdleTTL(*s.LSPIdleTTL)
+	}
+
+	telemetry.RegisterGaugeVecFunc(
+		"daytona_daemon_lsp_servers",
+		"Number of language servers by language and state",
+		[]string{"language", "state"},
+		lspServerCounts,
+	)
 
-	lspController := r.Group("/lsp")
+	// Keep documents open in language servers in sync with toolbox file writes
+	fs.OnFileWritten(lsp.GetLSPService().SyncFile)
+
+	lspController := r.Group("/lsp", auth.RequireScope(auth.ScopeLsp))
 	{
+		lspController.GET("/languages", lsp.Languages)
+		lspController.GET("/servers", lsp.Servers)
+
 		//	server process
 		lspController.POST("/start", lsp.Start)
 		lspController.POST("/stop", lsp.Stop)
@@ -145,7 +292,17 @@ func (s *Server) Start() error {
 		lspController.POST("/completions", lsp.Completions)
 		lspController.POST("/did-open", lsp.DidOpen)
 		lspController.POST("/did-close", lsp.DidClose)
-
+		lspController.POST("/did-change", lsp.DidChange)
+		lspController.POST("/did-save", lsp.DidSave)
+		lspController.POST("/definition", lsp.Definition)
+		lspController.POST("/references", lsp.References)
+		lspController.POST("/hover", lsp.Hover)
+		lspController.POST("/signature-help", lsp.SignatureHelp)
+		lspController.POST("/rename", lsp.Rename)
+		lspController.POST("/formatting", lsp.Formatting)
+		lspController.POST("/code-actions", lsp.CodeActions)
+
+		lspController.GET("/diagnostics", lsp.Diagnostics)
 		lspController.GET("/document-symbols", lsp.DocumentSymbols)
 		lspController.GET("/workspacesymbols", lsp.WorkspaceSymbols)
 	}
@@ -156,14 +313,16 @@ func (s *Server) Start() error {
 	if _, err := os.Stat(pluginPath); os.IsNotExist(err) {
 		pluginPath = path.Join(configDir, "daytona-computer-use")
 	}
-	s.ComputerUse, err = manager.GetComputerUse(pluginPath)
-	if err != nil {
-		log.Errorf("Failed to initialize computer-use plugin: %v", err)
-		log.Info("Continuing without computer-use functionality...")
+	if s.ComputerUseEnabled {
+		s.ComputerUse, err = manager.GetComputerUse(pluginPath)
+		if err != nil {
+			log.Errorf("Failed to initialize computer-use plugin: %v", err)
+			log.Info("Continuing without computer-use functionality...")
+		}
 	}
 
 	// Always register computer-use endpoints, but handle the case when plugin is nil
-	computerUseController := r.Group("/computeruse")
+	computerUseController := r.Group("/computeruse", auth.RequireScope(auth.ScopeComputerUse))
 	{
 		if s.ComputerUse != nil {
 			// Computer use status endpoint
@@ -199,6 +358,36 @@ func (s *Server) Start() error {
 			// Display info endpoints
 			computerUseController.GET("/display/info", computeruse.WrapDisplayInfoHandler(s.ComputerUse.GetDisplayInfo))
 			computerUseController.GET("/display/windows", computeruse.WrapWindowsHandler(s.ComputerUse.GetWindows))
+
+			// Window management endpoints
+			computerUseController.POST("/display/windows/:id/focus", computeruse.WrapWindowHandler(s.ComputerUse.FocusWindow))
+			computerUseController.POST("/display/windows/:id/move", computeruse.WrapMoveWindowHandler(s.ComputerUse.MoveWindow))
+			computerUseController.POST("/display/windows/:id/resize", computeruse.WrapResizeWindowHandler(s.ComputerUse.ResizeWindow))
+			computerUseController.POST("/display/windows/:id/minimize", computeruse.WrapWindowHandler(s.ComputerUse.MinimizeWindow))
+			computerUseController.POST("/display/windows/:id/maximize", computeruse.WrapWindowHandler(s.ComputerUse.MaximizeWindow))
+			computerUseController.POST("/display/windows/:id/close", computeruse.WrapCloseWindowHandler(s.ComputerUse.CloseWindow))
+			// Launching runs an arbitrary command, so it also requires the process scope
+			computerUseController.POST("/applications/launch", auth.RequireScope(auth.ScopeProcess), computeruse.WrapLaunchApplicationHandler(s.ComputerUse.LaunchApplication))
+
+			// Clipboard endpoints
+			computerUseController.GET("/clipboard", computeruse.WrapGetClipboardHandler(s.ComputerUse.GetClipboard))
+			computerUseController.POST("/clipboard", computeruse.WrapSetClipboardHandler(s.ComputerUse.SetClipboard))
+
+			// Accessibility endpoints
+			computerUseController.GET("/accessibility/tree", computeruse.WrapAccessibilityTreeHandler(s.ComputerUse.GetAccessibilityTree))
+			computerUseController.POST("/accessibility/action", computeruse.WrapAccessibilityActionHandler(s.ComputerUse.InvokeAccessibilityAction))
+
+			// Screen change endpoints
+			computerUseController.POST("/screenshot/diff", computeruse.WrapDiffHandler(s.ComputerUse.Diff))
+			computerUseController.POST("/screen/wait-for-change", computeruse.WrapWaitForChangeHandler(s.ComputerUse.WaitForChange))
+			computerUseController.POST("/screen/wait-for-stable", computeruse.WrapWaitForStableHandler(s.ComputerUse.WaitForStable))
+
+			// Screen recording endpoints
+			computerUseController.POST("/recording/start", computeruse.WrapStartScreenRecordingHandler(s.ComputerUse.StartScreenRecording))
+			computerUseController.POST("/recording/stop", computeruse.WrapStopScreenRecordingHandler(s.ComputerUse.StopScreenRecording))
+			computerUseController.GET("/recordings", computeruse.WrapScreenRecordingsHandler(s.ComputerUse.ListScreenRecordings))
+			computerUseController.GET("/recordings/:id", computeruse.WrapScreenRecordingHandler(s.ComputerUse.GetScreenRecording))
+			computerUseController.GET("/recordings/:id/download", computeruse.WrapDownloadScreenRecordingHandler(s.ComputerUse.GetScreenRecording))
 		} else {
 			// Register all endpoints with disabled middleware when plugin is not available
 			computerUseController.GET("/status", s.computerUseDisabledMiddleware())
@@ -223,38 +412,150 @@ func (s *Server) Start() error {
 			computerUseController.POST("/keyboard/hotkey", s.computerUseDisabledMiddleware())
 			computerUseController.GET("/display/info", s.computerUseDisabledMiddleware())
 			computerUseController.GET("/display/windows", s.computerUseDisabledMiddleware())
+			computerUseController.POST("/display/windows/:id/focus", s.computerUseDisabledMiddleware())
+			computerUseController.POST("/display/windows/:id/move", s.computerUseDisabledMiddleware())
+			computerUseController.POST("/display/windows/:id/resize", s.computerUseDisabledMiddleware())
+			computerUseController.POST("/display/windows/:id/minimize", s.computerUseDisabledMiddleware())
+			computerUseController.POST("/display/windows/:id/maximize", s.computerUseDisabledMiddleware())
+			computerUseController.POST("/display/windows/:id/close", s.computerUseDisabledMiddleware())
+			computerUseController.POST("/applications/launch", auth.RequireScope(auth.ScopeProcess), s.computerUseDisabledMiddleware())
+			computerUseController.GET("/clipboard", s.computerUseDisabledMiddleware())
+			computerUseController.POST("/clipboard", s.computerUseDisabledMiddleware())
+			computerUseController.GET("/accessibility/tree", s.computerUseDisabledMiddleware())
+			computerUseController.POST("/accessibility/action", s.computerUseDisabledMiddleware())
+			computerUseController.POST("/screenshot/diff", s.computerUseDisabledMiddleware())
+			computerUseController.POST("/screen/wait-for-change", s.computerUseDisabledMiddleware())
+			computerUseController.POST("/screen/wait-for-stable", s.computerUseDisabledMiddleware())
+			computerUseController.POST("/recording/start", s.computerUseDisabledMiddleware())
+			computerUseController.POST("/recording/stop", s.computerUseDisabledMiddleware())
+			computerUseController.GET("/recordings", s.computerUseDisabledMiddleware())
+			computerUseController.GET("/recordings/:id", s.computerUseDisabledMiddleware())
+			computerUseController.GET("/recordings/:id/download", s.computerUseDisabledMiddleware())
 		}
 	}
 
-	portDetector := port.NewPortsDetector()
+	var portRanges port.PortRanges
+	if s.PortRanges != "" {
+		portRanges, err = port.ParsePortRanges(s.PortRanges)
+		if err != nil {
+			log.Errorf("Invalid port ranges, detecting all ports: %v", err)
+		}
+	}
+
+	serverPort := s.Port
+	if serverPort == 0 {
+		serverPort = config.TOOLBOX_API_PORT
+	}
+	reservedPorts := append([]int{serverPort}, s.ReservedPorts...)
+
+	portDetector := port.NewPortsDetector(portRanges, reservedPorts)
 
-	portController := r.Group("/port")
+	portController := r.Group("/port", auth.RequireScope(auth.ScopePort))
 	{
 		portController.GET("", portDetector.GetPorts)
+		portController.GET("/events", stream, portDetector.GetPortEvents)
 		portController.GET("/:port/in-use", portDetector.IsPortInUse)
 	}
 
-	proxyController := r.Group("/proxy")
+	proxyController := r.Group("/proxy", auth.RequireScope(auth.ScopeProxy))
 	{
-		proxyController.Any("/:port/*path", common_proxy.NewProxyRequestHandler(proxy.GetProxyTarget))
+		proxyController.Any("/:port/*path", common_proxy.NewProxyRequestHandlerWithConfig(proxy.NewProxyTargetGetter(reservedPorts), s.Proxy))
 	}
 
-	go portDetector.Start(context.Background())
+	portDetectorCtx, stopPortDetector := context.WithCancel(context.Background())
+	go portDetector.Start(portDetectorCtx)
 
 	httpServer := &http.Server{
-		Addr:    fmt.Sprintf(":%d", config.TOOLBOX_API_PORT),
+		Addr:    fmt.Sprintf(":%d", serverPort),
 		Handler: r,
 	}
+	httpServer.RegisterOnShutdown(stopStreams)
+
+	s.mu.Lock()
+	if s.draining.Load() {
+		s.mu.Unlock()
+		stopPortDetector()
+		stopStreams()
+		return nil
+	}
+	s.httpServer = httpServer
+	s.sessionController = sessionController
+	s.stopPortDetector = stopPortDetector
+	s.mu.Unlock()
 
 	// Print to stdout so the runner can know that the daemon is ready
-	fmt.Println("Starting toolbox server on port", config.TOOLBOX_API_PORT)
+	fmt.Println("Starting toolbox server on port", serverPort)
 
 	listener, err := net.Listen("tcp", httpServer.Addr)
 	if err != nil {
 		return err
 	}
 
-	return httpServer.Serve(listener)
+	s.ready.Store(true)
+
+	err = httpServer.Serve(listener)
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// Shutdown drains the toolbox: the readiness endpoint reports draining, the
+// port detector and event streams are stopped, session shells are signalled
+// while in-flight requests are given until ctx is done to complete, and the
+// language servers and computer-use processes are stopped.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	s.draining.Store(true)
+	httpServer := s.httpServer
+	sessionController := s.sessionController
+	stopPortDetector := s.stopPortDetector
+	s.mu.Unlock()
+
+	var errs []error
+
+	// Closes the port event subscriptions
+	if stopPortDetector != nil {
+		stopPortDetector()
+	}
+
+	// Requests waiting on session commands only return once the shells exit,
+	// so sessions are stopped while the requests drain
+	sessionsStopped := make(chan error, 1)
+	if sessionController != nil {
+		log.Info("Stopping session shells...")
+		go func() {
+			sessionsStopped <- sessionController.Shutdown(ctx)
+		}()
+	} else {
+		sessionsStopped <- nil
+	}
+
+	if httpServer != nil {
+		log.Info("Draining toolbox requests...")
+		if err := httpServer.Shutdown(ctx); err != nil {
+			log.Warnf("Toolbox requests did not complete in time: %v", err)
+			httpServer.Close()
+		}
+	}
+
+	if err := <-sessionsStopped; err != nil {
+		errs = append(errs, fmt.Errorf("failed to stop sessions: %w", err))
+	}
+
+	log.Info("Stopping language servers...")
+	if err := lsp.GetLSPService().ShutdownAll(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to stop language servers: %w", err))
+	}
+
+	if s.ComputerUse != nil {
+		log.Info("Stopping computer use processes...")
+		if _, err := s.ComputerUse.Stop(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to stop computer use: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
 }
 
 // computerUseDisabledMiddleware returns a middleware that handles requests when computer-use is disabled
@@ -5,23 +5,26 @@ package config
 
 import (
 	"log"
+	"time"
 
+	"github.com/daytonaio/common-go/pkg/proxy"
 	"github.com/go-playground/validator/v10"
 	"github.com/joho/godotenv"
 	"github.com/kelseyhightower/envconfig"
 )
 
 type Config struct {
-	ProxyPort     int          `envconfig:"PROXY_PORT" validate:"required"`
-	ProxyDomain   string       `envconfig:"PROXY_DOMAIN" validate:"required"`
-	ProxyProtocol string       `envconfig:"PROXY_PROTOCOL" validate:"required"`
-	ProxyApiKey   string       `envconfig:"PROXY_API_KEY" validate:"required"`
-	TLSCertFile   string       `envconfig:"TLS_CERT_FILE"`
-	TLSKeyFile    string       `envconfig:"TLS_KEY_FILE"`
-	EnableTLS     bool         `envconfig:"ENABLE_TLS"`
-	DaytonaApiUrl string       `envconfig:"DAYTONA_API_URL" validate:"required"`
-	Oidc          OidcConfig   `envconfig:"OIDC"`
-	Redis         *RedisConfig `envconfig:"REDIS"`
+	ProxyPort     int            `envconfig:"PROXY_PORT" validate:"required"`
+	ProxyDomain   string         `envconfig:"PROXY_DOMAIN" validate:"required"`
+	ProxyProtocol string         `envconfig:"PROXY_PROTOCOL" validate:"required"`
+	ProxyApiKey   string         `envconfig:"PROXY_API_KEY" validate:"required"`
+	TLSCertFile   string         `envconfig:"TLS_CERT_FILE"`
+	TLSKeyFile    string         `envconfig:"TLS_KEY_FILE"`
+	EnableTLS     bool           `envconfig:"ENABLE_TLS"`
+	DaytonaApiUrl string         `envconfig:"DAYTONA_API_URL" validate:"required"`
+	Oidc          OidcConfig     `envconfig:"OIDC"`
+	Redis         *RedisConfig   `envconfig:"REDIS"`
+	Upstream      UpstreamConfig `envconfig:"UPSTREAM"`
 }
 
 type OidcConfig struct {
@@ -31,6 +34,34 @@ type OidcConfig struct {
 	Audience     string `envconfig:"AUDIENCE" validate:"required"`
 }
 
+// UpstreamConfig configures the connections to the runners, defaults are
+// taken from proxy.DefaultConfig
+type UpstreamConfig struct {
+	DialTimeout time.Duration `envconfig:"DIAL_TIMEOUT"`
+	// No limit when zero
+	ResponseHeaderTimeout time.Duration `envconfig:"RESPONSE_HEADER_TIMEOUT"`
+	IdleConnTimeout       time.Duration `envconfig:"IDLE_CONN_TIMEOUT"`
+	FlushInterval         time.Duration `envconfig:"FLUSH_INTERVAL"`
+	H2C                   bool          `envconfig:"H2C"`
+}
+
+func (c UpstreamConfig) ProxyConfig() proxy.Config {
+	config := proxy.Config{
+		DialTimeout:           c.DialTimeout,
+		ResponseHeaderTimeout: c.ResponseHeaderTimeout,
+		IdleConnTimeout:       c.IdleConnTimeout,
+		FlushInterval:         c.FlushInterval,
+		H2C:                   c.H2C,
+	}
+	if config.DialTimeout == 0 {
+		config.DialTimeout = proxy.DefaultConfig.DialTimeout
+	}
+	if config.IdleConnTimeout == 0 {
+		config.IdleConnTimeout = proxy.DefaultConfig.IdleConnTimeout
+	}
+	return config
+}
+
 type RedisConfig struct {
 	Host     *string `envconfig:"HOST"`
 	Port     *int    `envconfig:"PORT"`
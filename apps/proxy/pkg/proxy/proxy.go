@@ -112,6 +112,8 @@ func StartProxy(config *config.Config) error {
 		}
 	}))
 
+	proxyRequest := common_proxy.NewProxyRequestHandlerWithConfig(proxy.GetProxyTarget, config.Upstream.ProxyConfig())
+
 	router.Any("/*path", func(ctx *gin.Context) {
 		_, _, err := proxy.parseHost(ctx.Request.Host)
 		// if the host is not valid, we don't proxy the request
@@ -132,7 +134,7 @@ func StartProxy(config *config.Config) error {
 			return
 		}
 
-		common_proxy.NewProxyRequestHandler(proxy.GetProxyTarget)(ctx)
+		proxyRequest(ctx)
 	})
 
 	httpServer := &http.Server{
@@ -9,7 +9,9 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/daytonaio/common-go/pkg/proxy"
 	"github.com/go-playground/validator/v10"
 	"github.com/joho/godotenv"
 	"github.com/kelseyhightower/envconfig"
@@ -31,6 +33,12 @@ type Config struct {
 	AWSAccessKeyId     string `envconfig:"AWS_ACCESS_KEY_ID"`
 	AWSSecretAccessKey string `envconfig:"AWS_SECRET_ACCESS_KEY"`
 	AWSDefaultBucket   string `envconfig:"AWS_DEFAULT_BUCKET"`
+	// Upstream settings of the sandbox toolbox proxy
+	ProxyDialTimeout           time.Duration `envconfig:"PROXY_DIAL_TIMEOUT"`
+	ProxyResponseHeaderTimeout time.Duration `envconfig:"PROXY_RESPONSE_HEADER_TIMEOUT"`
+	ProxyIdleConnTimeout       time.Duration `envconfig:"PROXY_IDLE_CONN_TIMEOUT"`
+	ProxyFlushInterval         time.Duration `envconfig:"PROXY_FLUSH_INTERVAL"`
+	ProxyH2C                   bool          `envconfig:"PROXY_H2C"`
 }
 
 var DEFAULT_API_PORT int = 8080
@@ -66,9 +74,27 @@ func GetConfig() (*Config, error) {
 		config.ApiPort = DEFAULT_API_PORT
 	}
 
+	if config.ProxyDialTimeout == 0 {
+		config.ProxyDialTimeout = proxy.DefaultConfig.DialTimeout
+	}
+
+	if config.ProxyIdleConnTimeout == 0 {
+		config.ProxyIdleConnTimeout = proxy.DefaultConfig.IdleConnTimeout
+	}
+
 	return config, nil
 }
 
+func (c *Config) ProxyConfig() proxy.Config {
+	return proxy.Config{
+		DialTimeout:           c.ProxyDialTimeout,
+		ResponseHeaderTimeout: c.ProxyResponseHeaderTimeout,
+		IdleConnTimeout:       c.ProxyIdleConnTimeout,
+		FlushInterval:         c.ProxyFlushInterval,
+		H2C:                   c.ProxyH2C,
+	}
+}
+
 func GetContainerRuntime() string {
 	return config.ContainerRuntime
 }
@@ -8,11 +8,12 @@ import (
 	"io"
 	"os"
 	"os/signal"
-	"path/filepath"
+	"strconv"
 	"time"
 
 	golog "log"
 
+	"github.com/daytonaio/common-go/pkg/logs"
 	"github.com/daytonaio/runner/cmd/runner/config"
 	"github.com/daytonaio/runner/internal/util"
 	"github.com/daytonaio/runner/pkg/api"
@@ -42,6 +43,7 @@ func main() {
 		TLSCertFile: cfg.TLSCertFile,
 		TLSKeyFile:  cfg.TLSKeyFile,
 		EnableTLS:   cfg.EnableTLS,
+		Proxy:       cfg.ProxyConfig(),
 	})
 
 	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
@@ -132,17 +134,23 @@ func init() {
 
 	log.SetLevel(logLevel)
 
+	logFormat, err := logs.ParseFormat(os.Getenv("LOG_FORMAT"))
+	if err != nil {
+		log.Error(err)
+		os.Exit(1)
+	}
+
 	log.SetOutput(os.Stdout)
+	log.SetFormatter(logs.FileFormatter(logFormat))
 
 	logFilePath, logFilePathSet := os.LookupEnv("LOG_FILE_PATH")
 	if logFilePathSet {
-		logDir := filepath.Dir(logFilePath)
-		if err := os.MkdirAll(logDir, 0755); err != nil {
-			log.Error("Failed to create log directory:", err)
-			os.Exit(1)
-		}
-
-		file, err := os.OpenFile(logFilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+		file, err := logs.NewFileWriter(logs.FileConfig{
+			Path:       logFilePath,
+			MaxSizeMB:  intFromEnv("LOG_MAX_SIZE_MB"),
+			MaxAgeDays: intFromEnv("LOG_MAX_AGE_DAYS"),
+			MaxBackups: intFromEnv("LOG_MAX_BACKUPS"),
+		})
 		if err != nil {
 			log.Error(err)
 			os.Exit(1)
@@ -161,7 +169,24 @@ func init() {
 	zlog.Logger = zlog.Output(zerolog.ConsoleWriter{
 		Out:        &util.DebugLogWriter{},
 		TimeFormat: time.RFC3339,
+		NoColor:    true,
 	})
 
 	golog.SetOutput(&util.DebugLogWriter{})
 }
+
+// The logger is set up before the config is loaded, so the log settings are
+// read from the environment directly rather than through the config
+func intFromEnv(key string) int {
+	value, set := os.LookupEnv(key)
+	if !set || value == "" {
+		return 0
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		log.Warnf("Ignoring %s, it is not a number: %s", key, value)
+		return 0
+	}
+	return number
+}
@@ -8,3 +8,9 @@ const BEARER_AUTH_HEADER = "Bearer"
 const AUTHORIZATION_HEADER = "Authorization"
 
 const DAYTONA_AUTHORIZATION_HEADER = "X-Daytona-Authorization"
+
+// Header carrying the per-sandbox toolbox token to the daemon
+const DAYTONA_TOOLBOX_TOKEN_HEADER = "X-Daytona-Toolbox-Token"
+
+// Container environment variable holding the per-sandbox toolbox token
+const DAYTONA_TOOLBOX_TOKEN_ENV = "DAYTONA_TOOLBOX_TOKEN"
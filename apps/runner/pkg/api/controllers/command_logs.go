@@ -6,17 +6,19 @@ package controllers
 import (
 	"context"
 	"fmt"
-	"net/url"
+	"net/http"
 	"strings"
 
 	"github.com/daytonaio/common-go/pkg/errors"
-	"github.com/daytonaio/common-go/pkg/proxy"
+	"github.com/daytonaio/runner/internal/constants"
 	"github.com/gin-gonic/gin"
 	log "github.com/sirupsen/logrus"
 
 	"github.com/gorilla/websocket"
 )
 
+// ProxyCommandLogsStream streams the logs of a followed session command, which
+// the sandbox serves over a WebSocket, as a plain HTTP response
 func ProxyCommandLogsStream(ctx *gin.Context) {
 	targetURL, extraHeaders, err := getProxyTarget(ctx)
 	if err != nil {
@@ -24,16 +26,17 @@ func ProxyCommandLogsStream(ctx *gin.Context) {
 		return
 	}
 
-	if ctx.Query("follow") != "true" {
-		proxy.NewProxyRequestHandler(func(ctx *gin.Context) (*url.URL, map[string]string, error) {
-			return targetURL, extraHeaders, nil
-		})(ctx)
-		return
-	}
-
 	fullTargetURL := strings.Replace(targetURL.String(), "http://", "ws://", 1)
 
-	ws, _, err := websocket.DefaultDialer.DialContext(context.Background(), fullTargetURL+"?follow=true", nil)
+	header := http.Header{}
+	for key, value := range extraHeaders {
+		header.Set(key, value)
+	}
+	if token := ctx.GetHeader(constants.DAYTONA_TOOLBOX_TOKEN_HEADER); token != "" {
+		header.Set(constants.DAYTONA_TOOLBOX_TOKEN_HEADER, token)
+	}
+
+	ws, _, err := websocket.DefaultDialer.DialContext(context.Background(), fullTargetURL+"?follow=true", header)
 	if err != nil {
 		ctx.Error(errors.NewBadRequestError(fmt.Errorf("failed to create outgoing request: %w", err)))
 		return
@@ -11,11 +11,15 @@ import (
 	"strings"
 
 	proxy "github.com/daytonaio/common-go/pkg/proxy"
+	"github.com/daytonaio/runner/internal/constants"
 	"github.com/daytonaio/runner/pkg/common"
+	"github.com/daytonaio/runner/pkg/docker"
 	"github.com/daytonaio/runner/pkg/runner"
 	"github.com/gin-gonic/gin"
 )
 
+var commandLogsPathRegex = regexp.MustCompile(`^/process/session/.+/command/.+/logs$`)
+
 // ProxyRequest handles proxying requests to a sandbox's container
 //
 //	@Tags			toolbox
@@ -32,15 +36,19 @@ import (
 //	@Router			/sandboxes/{sandboxId}/toolbox/{path} [get]
 //	@Router			/sandboxes/{sandboxId}/toolbox/{path} [post]
 //	@Router			/sandboxes/{sandboxId}/toolbox/{path} [delete]
-func ProxyRequest(ctx *gin.Context) {
-	if regexp.MustCompile(`^/process/session/.+/command/.+/logs$`).MatchString(ctx.Param("path")) {
-		if ctx.Query("follow") == "true" {
-			ProxyCommandLogsStream(ctx)
-			return
+func ProxyRequest(config proxy.Config) gin.HandlerFunc {
+	proxyRequest := proxy.NewProxyRequestHandlerWithConfig(getProxyTarget, config)
+
+	return func(ctx *gin.Context) {
+		if commandLogsPathRegex.MatchString(ctx.Param("path")) {
+			if ctx.Query("follow") == "true" {
+				ProxyCommandLogsStream(ctx)
+				return
+			}
 		}
-	}
 
-	proxy.NewProxyRequestHandler(getProxyTarget)(ctx)
+		proxyRequest(ctx)
+	}
 }
 
 func getProxyTarget(ctx *gin.Context) (*url.URL, map[string]string, error) {
@@ -91,5 +99,16 @@ func getProxyTarget(ctx *gin.Context) (*url.URL, map[string]string, error) {
 		return nil, nil, fmt.Errorf("failed to parse target URL: %w", err)
 	}
 
-	return target, nil, nil
+	// Authenticate with the sandbox token unless the client passed its own,
+	// possibly narrower scoped, toolbox token
+	var extraHeaders map[string]string
+	if ctx.GetHeader(constants.DAYTONA_TOOLBOX_TOKEN_HEADER) == "" {
+		if token := docker.GetToolboxToken(&container); token != "" {
+			extraHeaders = map[string]string{
+				constants.DAYTONA_TOOLBOX_TOKEN_HEADER: token,
+			}
+		}
+	}
+
+	return target, extraHeaders, nil
 }
@@ -6,6 +6,7 @@ package middlewares
 import (
 	"time"
 
+	"github.com/daytonaio/common-go/pkg/logs"
 	"github.com/gin-gonic/gin"
 
 	log "github.com/sirupsen/logrus"
@@ -21,10 +22,11 @@ func LoggingMiddleware() gin.HandlerFunc {
 		latencyTime := endTime.Sub(startTime)
 
 		fields := log.Fields{
-			"method":  ctx.Request.Method,
-			"URI":     ctx.Request.RequestURI,
-			"status":  ctx.Writer.Status(),
-			"latency": latencyTime,
+			"method":            ctx.Request.Method,
+			"URI":               ctx.Request.RequestURI,
+			"status":            ctx.Writer.Status(),
+			"latency":           latencyTime,
+			logs.RequestIdField: logs.RequestIdFromContext(ctx),
 		}
 
 		// Determine log level based on status code and errors
@@ -21,6 +21,8 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/daytonaio/common-go/pkg/logs"
+	"github.com/daytonaio/common-go/pkg/proxy"
 	"github.com/daytonaio/runner/cmd/runner/config"
 	"github.com/daytonaio/runner/pkg/api/controllers"
 	"github.com/daytonaio/runner/pkg/api/docs"
@@ -40,6 +42,7 @@ type ApiServerConfig struct {
 	TLSCertFile string
 	TLSKeyFile  string
 	EnableTLS   bool
+	Proxy       proxy.Config
 }
 
 func NewApiServer(config ApiServerConfig) *ApiServer {
@@ -48,6 +51,7 @@ func NewApiServer(config ApiServerConfig) *ApiServer {
 		tlsCertFile: config.TLSCertFile,
 		tlsKeyFile:  config.TLSKeyFile,
 		enableTLS:   config.EnableTLS,
+		proxy:       config.Proxy,
 	}
 }
 
@@ -56,6 +60,7 @@ type ApiServer struct {
 	tlsCertFile string
 	tlsKeyFile  string
 	enableTLS   bool
+	proxy       proxy.Config
 	httpServer  *http.Server
 	router      *gin.Engine
 }
@@ -80,6 +85,7 @@ func (a *ApiServer) Start() error {
 		gin.SetMode(gin.DebugMode)
 	}
 
+	a.router.Use(logs.RequestIdMiddleware())
 	a.router.Use(middlewares.LoggingMiddleware())
 	a.router.Use(middlewares.ErrorMiddleware())
 
@@ -116,7 +122,7 @@ func (a *ApiServer) Start() error {
 
 		// Add proxy endpoint within the sandbox controller for toolbox
 		// Using Any() to handle all HTTP methods for the toolbox proxy
-		sandboxController.Any("/:sandboxId/toolbox/*path", controllers.ProxyRequest)
+		sandboxController.Any("/:sandboxId/toolbox/*path", controllers.ProxyRequest(a.proxy))
 	}
 
 	snapshotController := protected.Group("/snapshots")
@@ -5,18 +5,26 @@ package docker
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/daytonaio/runner/cmd/runner/config"
+	"github.com/daytonaio/runner/internal/constants"
 	"github.com/daytonaio/runner/pkg/api/dto"
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/network"
 
 	"github.com/docker/docker/api/types/container"
 )
 
 func (d *DockerClient) getContainerConfigs(ctx context.Context, sandboxDto dto.CreateSandboxDTO, volumeMountPathBinds []string) (*container.Config, *container.HostConfig, *network.NetworkingConfig, error) {
-	containerConfig := d.getContainerCreateConfig(sandboxDto)
+	containerConfig, err := d.getContainerCreateConfig(sandboxDto)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 
 	hostConfig, err := d.getContainerHostConfig(ctx, sandboxDto, volumeMountPathBinds)
 	if err != nil {
@@ -27,11 +35,17 @@ func (d *DockerClient) getContainerConfigs(ctx context.Context, sandboxDto dto.C
 	return containerConfig, hostConfig, networkingConfig, nil
 }
 
-func (d *DockerClient) getContainerCreateConfig(sandboxDto dto.CreateSandboxDTO) *container.Config {
+func (d *DockerClient) getContainerCreateConfig(sandboxDto dto.CreateSandboxDTO) (*container.Config, error) {
+	toolboxToken, err := generateToolboxToken()
+	if err != nil {
+		return nil, err
+	}
+
 	envVars := []string{
 		"DAYTONA_SANDBOX_ID=" + sandboxDto.Id,
 		"DAYTONA_SANDBOX_SNAPSHOT=" + sandboxDto.Snapshot,
 		"DAYTONA_SANDBOX_USER=" + sandboxDto.OsUser,
+		constants.DAYTONA_TOOLBOX_TOKEN_ENV + "=" + toolboxToken,
 	}
 
 	for key, value := range sandboxDto.Env {
@@ -46,7 +60,35 @@ func (d *DockerClient) getContainerCreateConfig(sandboxDto dto.CreateSandboxDTO)
 		Entrypoint:   sandboxDto.Entrypoint,
 		AttachStdout: true,
 		AttachStderr: true,
+	}, nil
+}
+
+// generateToolboxToken returns a random token the daemon requires on toolbox
+// API requests.
+func generateToolboxToken() (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", fmt.Errorf("failed to generate toolbox token: %w", err)
+	}
+	return hex.EncodeToString(token), nil
+}
+
+// GetToolboxToken returns the toolbox token from a container's environment,
+// or an empty string for sandboxes created before tokens were introduced.
+func GetToolboxToken(container *types.ContainerJSON) string {
+	if container.Config == nil {
+		return ""
+	}
+
+	token := ""
+	prefix := constants.DAYTONA_TOOLBOX_TOKEN_ENV + "="
+	for _, env := range container.Config.Env {
+		// Like Docker, the last definition wins
+		if value, ok := strings.CutPrefix(env, prefix); ok {
+			token = value
+		}
 	}
+	return token
 }
 
 func (d *DockerClient) getContainerHostConfig(ctx context.Context, sandboxDto dto.CreateSandboxDTO, volumeMountPathBinds []string) (*container.HostConfig, error) {
@@ -0,0 +1,68 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package logs
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+const (
+	DefaultMaxSizeMB  = 100
+	DefaultMaxAgeDays = 7
+	DefaultMaxBackups = 5
+)
+
+type FileConfig struct {
+	Path string
+	// Size in megabytes at which the file is rotated
+	MaxSizeMB int
+	// Days after which rotated files are removed
+	MaxAgeDays int
+	// Number of rotated files to keep
+	MaxBackups int
+	Compress   bool
+}
+
+// NewFileWriter opens a log file that is rotated once it grows past the
+// configured size. Zero values fall back to the defaults.
+func NewFileWriter(config FileConfig) (io.WriteCloser, error) {
+	if config.Path == "" {
+		return nil, fmt.Errorf("log file path is required")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(config.Path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	if config.MaxSizeMB <= 0 {
+		config.MaxSizeMB = DefaultMaxSizeMB
+	}
+	if config.MaxAgeDays <= 0 {
+		config.MaxAgeDays = DefaultMaxAgeDays
+	}
+	if config.MaxBackups <= 0 {
+		config.MaxBackups = DefaultMaxBackups
+	}
+
+	writer := &lumberjack.Logger{
+		Filename:   config.Path,
+		MaxSize:    config.MaxSizeMB,
+		MaxAge:     config.MaxAgeDays,
+		MaxBackups: config.MaxBackups,
+		Compress:   config.Compress,
+	}
+
+	// Open the file right away so that permission errors surface at startup
+	// rather than on the first log entry
+	if _, err := writer.Write(nil); err != nil {
+		return nil, fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	return writer, nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package logs
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type Format string
+
+const (
+	FormatText Format = "text"
+	FormatJSON Format = "json"
+)
+
+func ParseFormat(format string) (Format, error) {
+	switch Format(strings.ToLower(format)) {
+	case "", FormatText:
+		return FormatText, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	}
+
+	return "", fmt.Errorf("unsupported log format %q, expected text or json", format)
+}
+
+// ConsoleFormatter returns the formatter for interactive output. Text logs are
+// colored when colors is set.
+func ConsoleFormatter(format Format, colors bool) log.Formatter {
+	if format == FormatJSON {
+		return jsonFormatter()
+	}
+
+	return &log.TextFormatter{
+		ForceColors:   colors,
+		FullTimestamp: true,
+	}
+}
+
+// FileFormatter returns the formatter for log files, which never contain ANSI
+// escape codes.
+func FileFormatter(format Format) log.Formatter {
+	if format == FormatJSON {
+		return jsonFormatter()
+	}
+
+	return &log.TextFormatter{
+		DisableColors:   true,
+		FullTimestamp:   true,
+		TimestampFormat: time.RFC3339Nano,
+	}
+}
+
+func jsonFormatter() log.Formatter {
+	return &log.JSONFormatter{
+		TimestampFormat: time.RFC3339Nano,
+		FieldMap: log.FieldMap{
+			log.FieldKeyTime: "timestamp",
+			log.FieldKeyMsg:  "message",
+		},
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package logs_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/daytonaio/common-go/pkg/logs"
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+)
+
+func TestFileFormatter(t *testing.T) {
+	entry := log.WithField(logs.RequestIdField, "abc")
+	entry.Level = log.ErrorLevel
+	entry.Message = "failed"
+
+	text, err := logs.FileFormatter(logs.FormatText).Format(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(text), "\x1b[") {
+		t.Fatalf("text log contains escape codes: %q", text)
+	}
+
+	data, err := logs.FileFormatter(logs.FormatJSON).Format(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatalf("json log is not valid json: %v", err)
+	}
+	if fields["message"] != "failed" || fields["level"] != "error" || fields[logs.RequestIdField] != "abc" {
+		t.Fatalf("unexpected json fields: %v", fields)
+	}
+	if _, ok := fields["timestamp"]; !ok {
+		t.Fatalf("json log has no timestamp: %v", fields)
+	}
+}
+
+func TestRequestIdMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var seen string
+	r := gin.New()
+	r.Use(logs.RequestIdMiddleware())
+	r.GET("/", func(c *gin.Context) {
+		seen = logs.RequestIdFromContext(c)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if seen == "" || w.Header().Get(logs.RequestIdHeader) != seen {
+		t.Fatalf("expected a generated request id to be echoed, got %q and %q", seen, w.Header().Get(logs.RequestIdHeader))
+	}
+
+	// Ids set by the caller are propagated
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(logs.RequestIdHeader, "upstream-id")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if seen != "upstream-id" || w.Header().Get(logs.RequestIdHeader) != "upstream-id" {
+		t.Fatalf("expected the caller's request id, got %q and %q", seen, w.Header().Get(logs.RequestIdHeader))
+	}
+}
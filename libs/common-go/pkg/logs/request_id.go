@@ -0,0 +1,46 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package logs
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	RequestIdHeader = "X-Request-Id"
+	RequestIdField  = "request_id"
+
+	requestIdContextKey = "requestId"
+)
+
+// RequestIdMiddleware tags every request with an id, reusing the one sent by
+// the caller if present. The id is echoed in the response and kept on the
+// request so that it is forwarded when the request is proxied.
+func RequestIdMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		requestId := ctx.GetHeader(RequestIdHeader)
+		if requestId == "" || len(requestId) > 128 {
+			requestId = newRequestId()
+			ctx.Request.Header.Set(RequestIdHeader, requestId)
+		}
+
+		ctx.Set(requestIdContextKey, requestId)
+		ctx.Header(RequestIdHeader, requestId)
+
+		ctx.Next()
+	}
+}
+
+func RequestIdFromContext(ctx *gin.Context) string {
+	return ctx.GetString(requestIdContextKey)
+}
+
+func newRequestId() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,118 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	ErrorCodePortNotListening = "PORT_NOT_LISTENING"
+	ErrorCodeUpstreamTimeout  = "UPSTREAM_TIMEOUT"
+	ErrorCodeBadGateway       = "BAD_GATEWAY"
+)
+
+var errorPage = template.Must(template.New("error").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.StatusCode}} {{.Title}}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 4rem auto; max-width: 40rem; color: #1f2328; }
+code { background: #f6f8fa; padding: 0.1rem 0.3rem; border-radius: 4px; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>{{.Message}}</p>
+<p><code>{{.Code}}</code></p>
+</body>
+</html>
+`))
+
+// handleProxyError writes a structured error for a failed upstream request.
+// Browsers get an HTML page, other clients an ErrorResponse JSON body.
+func handleProxyError(w http.ResponseWriter, r *http.Request, err error) {
+	// The client went away, there is nobody to respond to
+	if errors.Is(err, context.Canceled) && r.Context().Err() != nil {
+		return
+	}
+
+	statusCode, code, title, message := classifyProxyError(r, err)
+
+	entry := log.WithFields(log.Fields{
+		"method": r.Method,
+		"host":   r.URL.Host,
+		"status": statusCode,
+	})
+	// Unexpected errors are only described in the logs, they can contain
+	// internal addresses
+	if code == ErrorCodeBadGateway {
+		entry.Warnf("Proxy error: %v", err)
+	} else {
+		entry.Debugf("Proxy error: %v", err)
+	}
+
+	if strings.Contains(r.Header.Get("Accept"), "text/html") {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(statusCode)
+		_ = errorPage.Execute(w, map[string]interface{}{
+			"StatusCode": statusCode,
+			"Title":      title,
+			"Message":    message,
+			"Code":       code,
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(common_errors.ErrorResponse{
+		StatusCode: statusCode,
+		Message:    message,
+		Code:       code,
+		Timestamp:  time.Now(),
+		Path:       r.URL.Path,
+		Method:     r.Method,
+	})
+}
+
+func classifyProxyError(r *http.Request, err error) (statusCode int, code, title, message string) {
+	var netErr net.Error
+
+	switch {
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return http.StatusBadGateway, ErrorCodePortNotListening, "Port not listening",
+			fmt.Sprintf("No service is listening on port %s. Start a server on this port and reload.", upstreamPort(r))
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return http.StatusGatewayTimeout, ErrorCodeUpstreamTimeout, "Upstream timeout",
+			fmt.Sprintf("The service on port %s did not respond in time.", upstreamPort(r))
+	default:
+		return http.StatusBadGateway, ErrorCodeBadGateway, "Bad gateway",
+			fmt.Sprintf("Failed to reach the service on port %s.", upstreamPort(r))
+	}
+}
+
+func upstreamPort(r *http.Request) string {
+	if port := r.URL.Port(); port != "" {
+		return port
+	}
+	if r.URL.Scheme == "https" {
+		return "443"
+	}
+	return "80"
+}
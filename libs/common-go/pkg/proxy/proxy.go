@@ -4,7 +4,8 @@
 package proxy
 
 import (
-	"net"
+	"context"
+	"mime"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
@@ -13,14 +14,37 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-var proxyTransport = &http.Transport{
-	MaxIdleConns:        100,
-	MaxIdleConnsPerHost: 100,
-	DialContext: (&net.Dialer{
-		KeepAlive: 30 * time.Second,
-	}).DialContext,
+// Config configures how requests are proxied to upstream services.
+type Config struct {
+	// DialTimeout limits establishing the upstream connection
+	DialTimeout time.Duration
+	// ResponseHeaderTimeout limits waiting for the upstream response headers,
+	// zero means no limit
+	ResponseHeaderTimeout time.Duration
+	// IdleConnTimeout closes pooled upstream connections after being idle
+	IdleConnTimeout time.Duration
+	// FlushInterval is the flush interval of non-streaming responses.
+	// Streaming responses are always flushed immediately.
+	FlushInterval time.Duration
+	// H2C sends requests to upstreams over cleartext HTTP/2. gRPC requests
+	// always use it.
+	H2C bool
 }
 
+var DefaultConfig = Config{
+	DialTimeout:     10 * time.Second,
+	IdleConnTimeout: 90 * time.Second,
+}
+
+type proxyTargetKey struct{}
+
+type proxyTarget struct {
+	url          *url.URL
+	extraHeaders map[string]string
+}
+
+var defaultReverseProxy = newReverseProxy(DefaultConfig)
+
 // ProxyRequest handles proxying requests to a sandbox's container
 //
 //	@Tags			toolbox
@@ -37,6 +61,16 @@ var proxyTransport = &http.Transport{
 //	@Failure		500			{object}	string	"Internal server error"
 //	@Router			/workspaces/{workspaceId}/{projectId}/toolbox/{path} [get]
 func NewProxyRequestHandler(getProxyTarget func(*gin.Context) (targetUrl *url.URL, extraHeaders map[string]string, err error)) gin.HandlerFunc {
+	return newProxyRequestHandler(defaultReverseProxy, getProxyTarget)
+}
+
+// NewProxyRequestHandlerWithConfig is like NewProxyRequestHandler but uses
+// its own upstream transport configured by config.
+func NewProxyRequestHandlerWithConfig(getProxyTarget func(*gin.Context) (targetUrl *url.URL, extraHeaders map[string]string, err error), config Config) gin.HandlerFunc {
+	return newProxyRequestHandler(newReverseProxy(config), getProxyTarget)
+}
+
+func newProxyRequestHandler(reverseProxy *httputil.ReverseProxy, getProxyTarget func(*gin.Context) (targetUrl *url.URL, extraHeaders map[string]string, err error)) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		target, extraHeaders, err := getProxyTarget(ctx)
 		if err != nil {
@@ -44,24 +78,63 @@ func NewProxyRequestHandler(getProxyTarget func(*gin.Context) (targetUrl *url.UR
 			return
 		}
 
-		reverseProxy := &httputil.ReverseProxy{
-			Director: func(req *http.Request) {
-				req.Host = target.Host
-				req.URL.Scheme = target.Scheme
-				req.URL.Host = target.Host
-				req.URL.Path = target.Path
-				if target.RawQuery == "" || req.URL.RawQuery == "" {
-					req.URL.RawQuery = target.RawQuery + req.URL.RawQuery
-				} else {
-					req.URL.RawQuery = target.RawQuery + "&" + req.URL.RawQuery
-				}
-				for key, value := range extraHeaders {
-					req.Header.Add(key, value)
-				}
-			},
-			Transport: proxyTransport,
-		}
+		req := ctx.Request.WithContext(context.WithValue(ctx.Request.Context(), proxyTargetKey{}, &proxyTarget{
+			url:          target,
+			extraHeaders: extraHeaders,
+		}))
+
+		reverseProxy.ServeHTTP(ctx.Writer, req)
+	}
+}
+
+func newReverseProxy(config Config) *httputil.ReverseProxy {
+	return &httputil.ReverseProxy{
+		Director: func(req *http.Request) {
+			target, ok := req.Context().Value(proxyTargetKey{}).(*proxyTarget)
+			if !ok {
+				return
+			}
+
+			req.Host = target.url.Host
+			req.URL.Scheme = target.url.Scheme
+			req.URL.Host = target.url.Host
+			req.URL.Path = target.url.Path
+			if target.url.RawQuery == "" || req.URL.RawQuery == "" {
+				req.URL.RawQuery = target.url.RawQuery + req.URL.RawQuery
+			} else {
+				req.URL.RawQuery = target.url.RawQuery + "&" + req.URL.RawQuery
+			}
+			for key, value := range target.extraHeaders {
+				req.Header.Add(key, value)
+			}
+		},
+		Transport:      newUpstreamTransport(config),
+		FlushInterval:  config.FlushInterval,
+		ModifyResponse: disableBufferingForStreams,
+		ErrorHandler:   handleProxyError,
+	}
+}
 
-		reverseProxy.ServeHTTP(ctx.Writer, ctx.Request)
+// disableBufferingForStreams asks proxies in front of us (e.g. nginx) not to
+// buffer streaming responses. The reverse proxy itself flushes them
+// immediately.
+func disableBufferingForStreams(res *http.Response) error {
+	if isStreamingResponse(res) {
+		res.Header.Set("X-Accel-Buffering", "no")
 	}
+	return nil
+}
+
+func isStreamingResponse(res *http.Response) bool {
+	if res.StatusCode == http.StatusSwitchingProtocols {
+		return false
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(res.Header.Get("Content-Type"))
+	switch mediaType {
+	case "text/event-stream", "application/x-ndjson", "application/grpc":
+		return true
+	}
+
+	return res.ContentLength == -1
 }
@@ -0,0 +1,161 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package proxy_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	common_errors "github.com/daytonaio/common-go/pkg/errors"
+	"github.com/daytonaio/common-go/pkg/proxy"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+func newProxyServer(t *testing.T, upstream string, config proxy.Config) *httptest.Server {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Any("/*path", proxy.NewProxyRequestHandlerWithConfig(func(ctx *gin.Context) (*url.URL, map[string]string, error) {
+		target, err := url.Parse(upstream + ctx.Param("path"))
+		return target, nil, err
+	}, config))
+
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestProxy_WebSocket(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		messageType, message, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		_ = conn.WriteMessage(messageType, message)
+	}))
+	defer upstream.Close()
+
+	// h2c must not be used for upgrade requests
+	server := newProxyServer(t, upstream.URL, proxy.Config{H2C: true})
+
+	conn, _, err := websocket.DefaultDialer.Dial(strings.Replace(server.URL, "http", "ws", 1)+"/ws", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if err := conn.WriteMessage(websocket.TextMessage, []byte("ping")); err != nil {
+		t.Fatal(err)
+	}
+	_, message, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(message) != "ping" {
+		t.Fatalf("expected echoed message, got %q", message)
+	}
+}
+
+func TestProxy_H2C(t *testing.T) {
+	upstream := httptest.NewServer(h2c.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.Proto)
+	}), &http2.Server{}))
+	defer upstream.Close()
+
+	server := newProxyServer(t, upstream.URL, proxy.Config{H2C: true})
+
+	res, err := http.Get(server.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	body, _ := io.ReadAll(res.Body)
+	if string(body) != "HTTP/2.0" {
+		t.Fatalf("expected upstream request over HTTP/2, got %q", body)
+	}
+}
+
+func TestProxy_PortNotListening(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	server := newProxyServer(t, "http://"+address, proxy.DefaultConfig)
+
+	res, err := http.Get(server.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusBadGateway {
+		t.Fatalf("expected status 502, got %d", res.StatusCode)
+	}
+
+	var errorResponse common_errors.ErrorResponse
+	if err := json.NewDecoder(res.Body).Decode(&errorResponse); err != nil {
+		t.Fatal(err)
+	}
+	if errorResponse.Code != proxy.ErrorCodePortNotListening {
+		t.Fatalf("expected %s, got %s", proxy.ErrorCodePortNotListening, errorResponse.Code)
+	}
+}
+
+func TestProxy_BadGatewayHidesError(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			conn.Close()
+		}
+	}))
+	defer upstream.Close()
+
+	server := newProxyServer(t, upstream.URL, proxy.DefaultConfig)
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Accept", "text/html")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusBadGateway {
+		t.Fatalf("expected status 502, got %d", res.StatusCode)
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(body), proxy.ErrorCodeBadGateway) {
+		t.Fatalf("expected %s in the error page: %s", proxy.ErrorCodeBadGateway, body)
+	}
+	if strings.Contains(string(body), "EOF") || strings.Contains(string(body), "127.0.0.1") {
+		t.Fatalf("the error page exposes the upstream error: %s", body)
+	}
+}
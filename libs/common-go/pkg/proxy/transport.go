@@ -0,0 +1,123 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package proxy
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/net/http2"
+)
+
+// upstreamTransport sends upgrade requests (e.g. WebSocket) over HTTP/1.1,
+// which is the only protocol supporting them, and other requests over
+// cleartext HTTP/2 (h2c) when enabled or required by gRPC.
+type upstreamTransport struct {
+	http1  *http.Transport
+	h2c    *http2.Transport
+	useH2C bool
+}
+
+func newUpstreamTransport(config Config) *upstreamTransport {
+	dialer := &net.Dialer{
+		Timeout:   config.DialTimeout,
+		KeepAlive: 30 * time.Second,
+	}
+
+	return &upstreamTransport{
+		http1: &http.Transport{
+			MaxIdleConns:          100,
+			MaxIdleConnsPerHost:   100,
+			IdleConnTimeout:       config.IdleConnTimeout,
+			ResponseHeaderTimeout: config.ResponseHeaderTimeout,
+			DialContext:           dialer.DialContext,
+		},
+		h2c: &http2.Transport{
+			AllowHTTP: true,
+			// Dial without TLS to speak HTTP/2 with prior knowledge
+			DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+				return dialer.DialContext(ctx, network, addr)
+			},
+			ReadIdleTimeout: config.IdleConnTimeout,
+		},
+		useH2C: config.H2C,
+	}
+}
+
+func (t *upstreamTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if isUpgradeRequest(req) || req.URL.Scheme != "http" {
+		return t.http1.RoundTrip(req)
+	}
+
+	if t.useH2C || isGRPCRequest(req) {
+		if t.http1.ResponseHeaderTimeout > 0 {
+			return roundTripWithHeaderTimeout(t.h2c, req, t.http1.ResponseHeaderTimeout)
+		}
+		return t.h2c.RoundTrip(req)
+	}
+
+	return t.http1.RoundTrip(req)
+}
+
+// roundTripWithHeaderTimeout fails the request when the response headers do
+// not arrive in time. The HTTP/2 transport has no such option.
+func roundTripWithHeaderTimeout(rt http.RoundTripper, req *http.Request, timeout time.Duration) (*http.Response, error) {
+	ctx, cancel := context.WithCancel(req.Context())
+	timer := time.AfterFunc(timeout, cancel)
+
+	res, err := rt.RoundTrip(req.WithContext(ctx))
+	if !timer.Stop() {
+		if err == nil {
+			res.Body.Close()
+		}
+		cancel()
+		return nil, errResponseHeaderTimeout
+	}
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	res.Body = &cancelOnClose{ReadCloser: res.Body, cancel: cancel}
+	return res, nil
+}
+
+var errResponseHeaderTimeout error = &timeoutError{}
+
+type timeoutError struct{}
+
+func (e *timeoutError) Error() string   { return "timeout awaiting response headers" }
+func (e *timeoutError) Timeout() bool   { return true }
+func (e *timeoutError) Temporary() bool { return true }
+
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
+
+func isUpgradeRequest(req *http.Request) bool {
+	for _, value := range req.Header.Values("Connection") {
+		for _, token := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(token), "upgrade") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isGRPCRequest(req *http.Request) bool {
+	return strings.HasPrefix(req.Header.Get("Content-Type"), "application/grpc")
+}
@@ -4,6 +4,7 @@ go 1.23.0
 
 require (
 	github.com/go-vgo/robotgo v0.110.8
+	github.com/godbus/dbus/v5 v5.1.0
 	github.com/hashicorp/go-hclog v1.6.3
 	github.com/hashicorp/go-plugin v1.6.3
 	github.com/kbinani/screenshot v0.0.0-20250118074034-a3924b7bbc8c
@@ -18,7 +19,6 @@ require (
 	github.com/fatih/color v1.13.0 // indirect
 	github.com/gen2brain/shm v0.1.1 // indirect
 	github.com/go-ole/go-ole v1.3.0 // indirect
-	github.com/godbus/dbus/v5 v5.1.0 // indirect
 	github.com/golang/protobuf v1.5.4 // indirect
 	github.com/hashicorp/yamux v0.1.1 // indirect
 	github.com/jezek/xgb v1.1.1 // indirect
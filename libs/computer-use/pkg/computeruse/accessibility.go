@@ -0,0 +1,496 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package computeruse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/daytonaio/daemon/pkg/toolbox/computeruse"
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	AccessibilityScopeFocused = "focused"
+	AccessibilityScopeAll     = "all"
+
+	defaultAccessibilityDepth = 30
+	maxAccessibilityNodes     = 10000
+	maxAccessibilityValueLen  = 4096
+
+	// Unresponsive applications must not stall the whole dump
+	atspiCallTimeout    = 2 * time.Second
+	atspiRequestTimeout = 30 * time.Second
+)
+
+const (
+	atspiRegistry = "org.a11y.atspi.Registry"
+	atspiRootPath = "/org/a11y/atspi/accessible/root"
+	atspiNullPath = "/org/a11y/atspi/null"
+
+	atspiAccessible   = "org.a11y.atspi.Accessible"
+	atspiAction       = "org.a11y.atspi.Action"
+	atspiComponent    = "org.a11y.atspi.Component"
+	atspiEditableText = "org.a11y.atspi.EditableText"
+	atspiText         = "org.a11y.atspi.Text"
+	atspiValue        = "org.a11y.atspi.Value"
+
+	dbusPropertiesGet = "org.freedesktop.DBus.Properties.Get"
+	dbusPropertiesSet = "org.freedesktop.DBus.Properties.Set"
+
+	atspiCoordTypeScreen = uint32(0)
+
+	atspiStateActive   = 1
+	atspiStateDefunct  = 6
+	atspiStateEditable = 7
+	atspiStateShowing  = 25
+)
+
+// Names of AtspiStateType values, indexed by their bit in the state set
+var atspiStateNames = []string{
+	"invalid", "active", "armed", "busy", "checked", "collapsed", "defunct",
+	"editable", "enabled", "expandable", "expanded", "focusable", "focused",
+	"has-tooltip", "horizontal", "iconified", "modal", "multi-line",
+	"multiselectable", "opaque", "pressed", "resizable", "selectable",
+	"selected", "sensitive", "showing", "single-line", "stale", "transient",
+	"vertical", "visible", "manages-descendants", "indeterminate", "required",
+	"truncated", "animated", "invalid-entry", "supports-autocompletion",
+	"selectable-text", "is-default", "visited", "checkable", "has-popup",
+	"read-only",
+}
+
+var accessibilityPathPattern = regexp.MustCompile(`^\d+(/\d+)*$`)
+
+type accessibleRef struct {
+	Name string
+	Path dbus.ObjectPath
+}
+
+type accessibleAction struct {
+	Name        string
+	Description string
+	KeyBinding  string
+}
+
+// atspiClient talks to the applications on the accessibility bus, which is
+// separate from the session bus
+type atspiClient struct {
+	ctx  context.Context
+	conn *dbus.Conn
+}
+
+func newAtspiClient(ctx context.Context) (*atspiClient, error) {
+	address, err := atspiBusAddress(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("accessibility bus is not available, make sure at-spi2-core is installed: %v", err)
+	}
+
+	conn, err := dbus.Connect(address, dbus.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the accessibility bus: %v", err)
+	}
+
+	return &atspiClient{
+		ctx:  ctx,
+		conn: conn,
+	}, nil
+}
+
+// atspiBusAddress looks the bus up the same way libatspi does: the
+// environment, the bus launcher on the session bus and the property that the
+// launcher sets on the X root window
+func atspiBusAddress(ctx context.Context) (string, error) {
+	if address := os.Getenv("AT_SPI_BUS_ADDRESS"); address != "" {
+		return address, nil
+	}
+
+	if session, err := dbus.ConnectSessionBus(dbus.WithContext(ctx)); err == nil {
+		defer session.Close()
+
+		var address string
+		callCtx, cancel := context.WithTimeout(ctx, atspiCallTimeout)
+		defer cancel()
+		err := session.Object("org.a11y.Bus", "/org/a11y/bus").CallWithContext(callCtx, "org.a11y.Bus.GetAddress", 0).Store(&address)
+		if err == nil && address != "" {
+			return address, nil
+		}
+	}
+
+	output, err := runX11Command(nil, "xprop", "-root", "AT_SPI_BUS")
+	if err != nil {
+		return "", err
+	}
+	// AT_SPI_BUS(STRING) = "unix:path=/tmp/..."
+	_, value, ok := strings.Cut(string(output), "=")
+	address, unquoteErr := strconv.Unquote(strings.TrimSpace(value))
+	if !ok || unquoteErr != nil || address == "" {
+		return "", errors.New("AT_SPI_BUS is not set on the root window")
+	}
+	return address, nil
+}
+
+func (a *atspiClient) Close() {
+	a.conn.Close()
+}
+
+func (a *atspiClient) call(ref accessibleRef, method string, out any, args ...any) error {
+	ctx, cancel := context.WithTimeout(a.ctx, atspiCallTimeout)
+	defer cancel()
+
+	call := a.conn.Object(ref.Name, ref.Path).CallWithContext(ctx, method, 0, args...)
+	if out == nil {
+		return call.Err
+	}
+	return call.Store(out)
+}
+
+func (a *atspiClient) property(ref accessibleRef, iface, name string, out any) error {
+	var value dbus.Variant
+	if err := a.call(ref, dbusPropertiesGet, &value, iface, name); err != nil {
+		return err
+	}
+	return value.Store(out)
+}
+
+func (a *atspiClient) root() accessibleRef {
+	return accessibleRef{Name: atspiRegistry, Path: atspiRootPath}
+}
+
+func (a *atspiClient) children(ref accessibleRef) ([]accessibleRef, error) {
+	var children []accessibleRef
+	if err := a.call(ref, atspiAccessible+".GetChildren", &children); err != nil {
+		return nil, err
+	}
+	return children, nil
+}
+
+func (a *atspiClient) states(ref accessibleRef) ([]uint32, error) {
+	var states []uint32
+	if err := a.call(ref, atspiAccessible+".GetState", &states); err != nil {
+		return nil, err
+	}
+	return states, nil
+}
+
+// resolve walks a node path from the desktop
+func (a *atspiClient) resolve(path string) (accessibleRef, error) {
+	if !accessibilityPathPattern.MatchString(path) {
+		return accessibleRef{}, fmt.Errorf("invalid node path %q", path)
+	}
+
+	ref := a.root()
+	for _, segment := range strings.Split(path, "/") {
+		index, _ := strconv.Atoi(segment)
+
+		var child accessibleRef
+		if err := a.call(ref, atspiAccessible+".GetChildAtIndex", &child, int32(index)); err != nil {
+			return accessibleRef{}, fmt.Errorf("node %s not found: %v", path, err)
+		}
+		if child.Path == atspiNullPath {
+			return accessibleRef{}, fmt.Errorf("node %s not found", path)
+		}
+		ref = child
+	}
+
+	return ref, nil
+}
+
+// focusedWindow finds the top level window with the active state
+func (a *atspiClient) focusedWindow() (accessibleRef, string, error) {
+	applications, err := a.children(a.root())
+	if err != nil {
+		return accessibleRef{}, "", err
+	}
+
+	for i, application := range applications {
+		windows, err := a.children(application)
+		if err != nil {
+			continue
+		}
+		for j, window := range windows {
+			states, err := a.states(window)
+			if err == nil && hasState(states, atspiStateActive) {
+				return window, fmt.Sprintf("%d/%d", i, j), nil
+			}
+		}
+	}
+
+	return accessibleRef{}, "", errors.New("no focused window found, the application may not support accessibility")
+}
+
+type treeWalker struct {
+	client      *atspiClient
+	visibleOnly bool
+	nodes       int
+	truncated   bool
+}
+
+// node reads the properties of a node, and of its descendants up to depth
+// levels below it
+func (w *treeWalker) node(ref accessibleRef, path string, depth int) (*computeruse.AccessibilityNode, error) {
+	a := w.client
+
+	states, err := a.states(ref)
+	if err != nil {
+		return nil, err
+	}
+	if hasState(states, atspiStateDefunct) {
+		return nil, fmt.Errorf("node %s no longer exists", path)
+	}
+
+	node := &computeruse.AccessibilityNode{
+		Path:   path,
+		States: stateNames(states),
+	}
+	w.nodes++
+
+	_ = a.property(ref, atspiAccessible, "Name", &node.Name)
+	_ = a.property(ref, atspiAccessible, "Description", &node.Description)
+	_ = a.call(ref, atspiAccessible+".GetRoleName", &node.Role)
+
+	var interfaces []string
+	_ = a.call(ref, atspiAccessible+".GetInterfaces", &interfaces)
+
+	if slices.Contains(interfaces, atspiComponent) {
+		var extents struct {
+			X, Y, Width, Height int32
+		}
+		if err := a.call(ref, atspiComponent+".GetExtents", &extents, atspiCoordTypeScreen); err == nil && extents.Width > 0 && extents.Height > 0 {
+			node.Bounds = &computeruse.Rectangle{
+				Position: computeruse.Position{X: int(extents.X), Y: int(extents.Y)},
+				Size:     computeruse.Size{Width: int(extents.Width), Height: int(extents.Height)},
+			}
+		}
+	}
+
+	if slices.Contains(interfaces, atspiAction) {
+		var actions []accessibleAction
+		if err := a.call(ref, atspiAction+".GetActions", &actions); err == nil {
+			for _, action := range actions {
+				node.Actions = append(node.Actions, action.Name)
+			}
+		}
+	}
+
+	if slices.Contains(interfaces, atspiValue) {
+		var value float64
+		if err := a.property(ref, atspiValue, "CurrentValue", &value); err == nil {
+			node.Value = strconv.FormatFloat(value, 'f', -1, 64)
+		}
+	} else if slices.Contains(interfaces, atspiText) && hasState(states, atspiStateEditable) {
+		var text string
+		if err := a.call(ref, atspiText+".GetText", &text, int32(0), int32(maxAccessibilityValueLen)); err == nil {
+			node.Value = text
+		}
+	}
+
+	if depth <= 0 {
+		return node, nil
+	}
+
+	children, err := a.children(ref)
+	if err != nil {
+		return node, nil
+	}
+
+	for i, child := range children {
+		if w.nodes >= maxAccessibilityNodes {
+			w.truncated = true
+			break
+		}
+		if child.Path == atspiNullPath {
+			continue
+		}
+		if w.visibleOnly {
+			childStates, err := a.states(child)
+			if err != nil || !hasState(childStates, atspiStateShowing) {
+				continue
+			}
+		}
+
+		childNode, err := w.node(child, fmt.Sprintf("%s/%d", path, i), depth-1)
+		if err != nil {
+			continue
+		}
+		node.Children = append(node.Children, *childNode)
+	}
+
+	return node, nil
+}
+
+func (u *ComputerUse) GetAccessibilityTree(req *computeruse.AccessibilityTreeRequest) (*computeruse.AccessibilityTreeResponse, error) {
+	maxDepth := defaultAccessibilityDepth
+	if req.MaxDepth > 0 {
+		maxDepth = req.MaxDepth
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), atspiRequestTimeout)
+	defer cancel()
+
+	client, err := newAtspiClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	walker := &treeWalker{
+		client:      client,
+		visibleOnly: req.VisibleOnly,
+	}
+
+	response := &computeruse.AccessibilityTreeResponse{
+		Nodes: []computeruse.AccessibilityNode{},
+	}
+
+	switch req.Scope {
+	case "", AccessibilityScopeFocused:
+		window, path, err := client.focusedWindow()
+		if err != nil {
+			return nil, err
+		}
+		node, err := walker.node(window, path, maxDepth)
+		if err != nil {
+			return nil, err
+		}
+		response.Nodes = append(response.Nodes, *node)
+	case AccessibilityScopeAll:
+		applications, err := client.children(client.root())
+		if err != nil {
+			return nil, err
+		}
+		for i, application := range applications {
+			node, err := walker.node(application, strconv.Itoa(i), maxDepth)
+			if err != nil {
+				continue
+			}
+			response.Nodes = append(response.Nodes, *node)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported scope %q, expected %s or %s", req.Scope, AccessibilityScopeFocused, AccessibilityScopeAll)
+	}
+
+	response.Truncated = walker.truncated
+	return response, nil
+}
+
+func (u *ComputerUse) InvokeAccessibilityAction(req *computeruse.AccessibilityActionRequest) (*computeruse.AccessibilityNode, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), atspiRequestTimeout)
+	defer cancel()
+
+	client, err := newAtspiClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	ref, err := client.resolve(req.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var interfaces []string
+	if err := client.call(ref, atspiAccessible+".GetInterfaces", &interfaces); err != nil {
+		return nil, err
+	}
+
+	if req.Value != nil {
+		err = setAccessibleValue(client, ref, interfaces, *req.Value)
+	} else {
+		err = doAccessibleAction(client, ref, interfaces, req.Action)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	walker := &treeWalker{client: client}
+	node, err := walker.node(ref, req.Path, 0)
+	if err != nil {
+		// The action may have removed the node, e.g. a dialog's close button
+		return &computeruse.AccessibilityNode{Path: req.Path, States: []string{}}, nil
+	}
+	return node, nil
+}
+
+func doAccessibleAction(client *atspiClient, ref accessibleRef, interfaces []string, name string) error {
+	if !slices.Contains(interfaces, atspiAction) {
+		return errors.New("node has no actions")
+	}
+
+	var actions []accessibleAction
+	if err := client.call(ref, atspiAction+".GetActions", &actions); err != nil {
+		return err
+	}
+	if len(actions) == 0 {
+		return errors.New("node has no actions")
+	}
+
+	index := 0
+	if name != "" {
+		index = slices.IndexFunc(actions, func(action accessibleAction) bool {
+			return strings.EqualFold(action.Name, name)
+		})
+		if index < 0 {
+			names := make([]string, len(actions))
+			for i, action := range actions {
+				names[i] = action.Name
+			}
+			return fmt.Errorf("node has no %q action, available actions: %s", name, strings.Join(names, ", "))
+		}
+	}
+
+	var ok bool
+	if err := client.call(ref, atspiAction+".DoAction", &ok, int32(index)); err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("action %q failed", actions[index].Name)
+	}
+	return nil
+}
+
+func setAccessibleValue(client *atspiClient, ref accessibleRef, interfaces []string, value string) error {
+	switch {
+	case slices.Contains(interfaces, atspiEditableText):
+		var ok bool
+		if err := client.call(ref, atspiEditableText+".SetTextContents", &ok, value); err != nil {
+			return err
+		}
+		if !ok {
+			return errors.New("node rejected the text")
+		}
+		return nil
+	case slices.Contains(interfaces, atspiValue):
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("node expects a numeric value: %v", err)
+		}
+		return client.call(ref, dbusPropertiesSet, nil, atspiValue, "CurrentValue", dbus.MakeVariant(number))
+	default:
+		return errors.New("node has no editable value")
+	}
+}
+
+// hasState reports whether the state is set in an AT-SPI state set, which is
+// a bitfield split into 32 bit words
+func hasState(states []uint32, state int) bool {
+	word, bit := state/32, state%32
+	return word < len(states) && states[word]&(1<<bit) != 0
+}
+
+func stateNames(states []uint32) []string {
+	names := []string{}
+	for state, name := range atspiStateNames {
+		if state != 0 && hasState(states, state) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,112 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package computeruse
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"os/exec"
+
+	"github.com/daytonaio/daemon/pkg/toolbox/computeruse"
+)
+
+const (
+	ClipboardFormatText  = "text"
+	ClipboardFormatImage = "image"
+)
+
+func (u *ComputerUse) GetClipboard(req *computeruse.GetClipboardRequest) (*computeruse.ClipboardResponse, error) {
+	switch req.Format {
+	case "", ClipboardFormatText:
+		output, err := runX11Command(nil, "xclip", "-selection", "clipboard", "-o")
+		if err != nil {
+			return nil, err
+		}
+		return &computeruse.ClipboardResponse{
+			Format: ClipboardFormatText,
+			Text:   string(output),
+		}, nil
+	case ClipboardFormatImage:
+		output, err := runX11Command(nil, "xclip", "-selection", "clipboard", "-t", "image/png", "-o")
+		if err != nil {
+			return nil, err
+		}
+		return &computeruse.ClipboardResponse{
+			Format: ClipboardFormatImage,
+			Image:  base64.StdEncoding.EncodeToString(output),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported clipboard format %q, expected %s or %s", req.Format, ClipboardFormatText, ClipboardFormatImage)
+	}
+}
+
+func (u *ComputerUse) SetClipboard(req *computeruse.SetClipboardRequest) (*computeruse.Empty, error) {
+	if (req.Text == "") == (req.Image == "") {
+		return nil, errors.New("either text or image is required")
+	}
+
+	if req.Text != "" {
+		if err := writeClipboard([]byte(req.Text), "UTF8_STRING"); err != nil {
+			return nil, err
+		}
+		return new(computeruse.Empty), nil
+	}
+
+	data, err := decodeClipboardImage(req.Image)
+	if err != nil {
+		return nil, fmt.Errorf("invalid image: %v", err)
+	}
+	if err := writeClipboard(data, "image/png"); err != nil {
+		return nil, err
+	}
+
+	return new(computeruse.Empty), nil
+}
+
+// writeClipboard hands the contents over to xclip, which forks and keeps
+// serving the selection until another application takes ownership of it.
+// Its output is not captured because the forked process would hold the pipes
+// open and block the command from returning.
+func writeClipboard(data []byte, target string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), windowCommandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "xclip", "-selection", "clipboard", "-t", target, "-i")
+	cmd.Env = append(os.Environ(), "DISPLAY="+displayName())
+	cmd.Stdin = bytes.NewReader(data)
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("xclip failed: %v", err)
+	}
+	return nil
+}
+
+// decodeClipboardImage decodes a base64 encoded image and converts it to PNG,
+// which is the image format applications understand best when pasting
+func decodeClipboardImage(data string) ([]byte, error) {
+	raw, err := decodeBase64(data)
+	if err != nil {
+		return nil, err
+	}
+
+	img, format, err := image.Decode(bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+	if format == "png" {
+		return raw, nil
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -39,6 +39,7 @@ type ComputerUse struct {
 	processes map[string]*Process
 	mu        sync.RWMutex
 	configDir string
+	recorder  screenRecorder
 }
 
 var _ computeruse.IComputerUse = &ComputerUse{}
@@ -350,6 +351,9 @@ func (c *ComputerUse) startProcess(process *Process) {
 func (c *ComputerUse) Stop() (*computeruse.Empty, error) {
 	log.Info("Stopping all computer use processes...")
 
+	// Finish the video before the X server goes away
+	c.stopScreenRecordings()
+
 	c.mu.RLock()
 	processes := make([]*Process, 0, len(c.processes))
 	for _, p := range c.processes {
@@ -0,0 +1,311 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package computeruse
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"strings"
+	"time"
+
+	"github.com/daytonaio/daemon/pkg/toolbox/computeruse"
+	"github.com/kbinani/screenshot"
+)
+
+const (
+	defaultWaitInterval   = 100 * time.Millisecond
+	defaultWaitTimeout    = 10 * time.Second
+	maxWaitTimeout        = 5 * time.Minute
+	defaultStableDuration = 500 * time.Millisecond
+
+	// Changed pixels are grouped into cells of this size before they are merged
+	// into bounding boxes, so that typing a word does not produce a box per glyph
+	diffCellSize = 16
+)
+
+func (u *ComputerUse) Diff(req *computeruse.DiffRequest) (*computeruse.DiffResponse, error) {
+	if req.Before == "" {
+		return nil, errors.New("before image is required")
+	}
+
+	before, err := decodeScreenshot(req.Before)
+	if err != nil {
+		return nil, fmt.Errorf("invalid before image: %v", err)
+	}
+
+	var after *image.RGBA
+	if req.After != "" {
+		after, err = decodeScreenshot(req.After)
+		if err != nil {
+			return nil, fmt.Errorf("invalid after image: %v", err)
+		}
+	} else {
+		after, err = captureRGBA(screenRegion(req.Position, req.Size))
+		if err != nil {
+			return nil, err
+		}
+		// The before image may come from a scaled compressed screenshot
+		if after.Bounds().Size() != before.Bounds().Size() {
+			after = resizeImage(after, before.Bounds().Dx(), before.Bounds().Dy())
+		}
+	}
+
+	if after.Bounds().Size() != before.Bounds().Size() {
+		return nil, fmt.Errorf("images have different sizes %v and %v", before.Bounds().Size(), after.Bounds().Size())
+	}
+
+	diff := diffImages(before, after, req.Tolerance)
+	return &diff, nil
+}
+
+func (u *ComputerUse) WaitForChange(req *computeruse.WaitForChangeRequest) (*computeruse.WaitForScreenResponse, error) {
+	watch, err := newScreenWatch(req.Position, req.Size, req.Scale, req.Interval, req.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	baseline, err := watch.capture()
+	if err != nil {
+		return nil, err
+	}
+
+	var diff computeruse.DiffResponse
+	for watch.sleep() {
+		current, err := watch.capture()
+		if err != nil {
+			return nil, err
+		}
+
+		diff = diffImages(baseline, current, req.Tolerance)
+		if diff.Changed && diff.ChangedRatio > req.Threshold {
+			return watch.response(true, diff), nil
+		}
+	}
+
+	return watch.response(false, diff), nil
+}
+
+func (u *ComputerUse) WaitForStable(req *computeruse.WaitForStableRequest) (*computeruse.WaitForScreenResponse, error) {
+	watch, err := newScreenWatch(req.Position, req.Size, req.Scale, req.Interval, req.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	duration := defaultStableDuration
+	if req.Duration > 0 {
+		duration = time.Duration(req.Duration) * time.Millisecond
+	}
+
+	previous, err := watch.capture()
+	if err != nil {
+		return nil, err
+	}
+	stableSince := time.Now()
+
+	var diff computeruse.DiffResponse
+	for watch.sleep() {
+		current, err := watch.capture()
+		if err != nil {
+			return nil, err
+		}
+
+		// Consecutive captures are compared so that the screen only has to
+		// settle, not return to how it was when the wait started
+		diff = diffImages(previous, current, req.Tolerance)
+		if diff.Changed && diff.ChangedRatio > req.Threshold {
+			stableSince = time.Now()
+		} else if time.Since(stableSince) >= duration {
+			return watch.response(true, diff), nil
+		}
+		previous = current
+	}
+
+	return watch.response(false, diff), nil
+}
+
+// screenWatch captures a region of the screen repeatedly until a timeout
+type screenWatch struct {
+	region   image.Rectangle
+	scale    float64
+	interval time.Duration
+	start    time.Time
+	deadline time.Time
+}
+
+func newScreenWatch(position computeruse.Position, size computeruse.Size, scale float64, intervalMs, timeoutMs int) (*screenWatch, error) {
+	if scale == 0 {
+		scale = 1.0
+	}
+	if scale < 0.1 || scale > 1.0 {
+		return nil, errors.New("scale must be between 0.1 and 1.0")
+	}
+
+	interval := defaultWaitInterval
+	if intervalMs > 0 {
+		interval = time.Duration(intervalMs) * time.Millisecond
+	}
+
+	timeout := defaultWaitTimeout
+	if timeoutMs > 0 {
+		timeout = time.Duration(timeoutMs) * time.Millisecond
+	}
+	if timeout > maxWaitTimeout {
+		return nil, fmt.Errorf("timeout must be at most %s", maxWaitTimeout)
+	}
+
+	start := time.Now()
+	return &screenWatch{
+		region:   screenRegion(position, size),
+		scale:    scale,
+		interval: interval,
+		start:    start,
+		deadline: start.Add(timeout),
+	}, nil
+}
+
+func (w *screenWatch) capture() (*image.RGBA, error) {
+	img, err := captureRGBA(w.region)
+	if err != nil {
+		return nil, err
+	}
+	return scaleImage(img, w.scale), nil
+}
+
+// sleep waits until the next capture, it returns false once the timeout passed
+func (w *screenWatch) sleep() bool {
+	remaining := time.Until(w.deadline)
+	if remaining <= 0 {
+		return false
+	}
+	time.Sleep(min(w.interval, remaining))
+	return true
+}
+
+func (w *screenWatch) response(satisfied bool, diff computeruse.DiffResponse) *computeruse.WaitForScreenResponse {
+	return &computeruse.WaitForScreenResponse{
+		Satisfied:    satisfied,
+		ElapsedMs:    time.Since(w.start).Milliseconds(),
+		DiffResponse: diff,
+	}
+}
+
+// screenRegion returns the requested region, or the bounds of the first
+// display when the region is empty
+func screenRegion(position computeruse.Position, size computeruse.Size) image.Rectangle {
+	if size.Width <= 0 || size.Height <= 0 {
+		return screenshot.GetDisplayBounds(0)
+	}
+	return image.Rect(position.X, position.Y, position.X+size.Width, position.Y+size.Height)
+}
+
+// decodeBase64 decodes base64 encoded data, with or without a data URL prefix
+func decodeBase64(data string) ([]byte, error) {
+	if _, encoded, ok := strings.Cut(data, ";base64,"); ok {
+		data = encoded
+	}
+	return base64.StdEncoding.DecodeString(data)
+}
+
+// decodeScreenshot decodes a base64 encoded PNG or JPEG image
+func decodeScreenshot(data string) (*image.RGBA, error) {
+	raw, err := decodeBase64(data)
+	if err != nil {
+		return nil, err
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+
+	rgbaImg := image.NewRGBA(image.Rect(0, 0, img.Bounds().Dx(), img.Bounds().Dy()))
+	draw.Draw(rgbaImg, rgbaImg.Bounds(), img, img.Bounds().Min, draw.Src)
+
+	return rgbaImg, nil
+}
+
+// diffImages compares two images of the same size and returns the ratio of
+// pixels that differ by more than tolerance in any color channel, along with
+// the bounding boxes of the connected areas that changed
+func diffImages(before, after *image.RGBA, tolerance int) computeruse.DiffResponse {
+	width, height := before.Bounds().Dx(), before.Bounds().Dy()
+	cols := (width + diffCellSize - 1) / diffCellSize
+	rows := (height + diffCellSize - 1) / diffCellSize
+	// Extent of the changed pixels in every cell
+	cells := make([]image.Rectangle, cols*rows)
+
+	changed := 0
+	for y := 0; y < height; y++ {
+		beforeRow := before.Pix[before.PixOffset(before.Rect.Min.X, before.Rect.Min.Y+y):]
+		afterRow := after.Pix[after.PixOffset(after.Rect.Min.X, after.Rect.Min.Y+y):]
+		for x := 0; x < width; x++ {
+			i := x * 4
+			if channelDiff(beforeRow[i], afterRow[i]) > tolerance ||
+				channelDiff(beforeRow[i+1], afterRow[i+1]) > tolerance ||
+				channelDiff(beforeRow[i+2], afterRow[i+2]) > tolerance {
+				changed++
+				cell := &cells[(y/diffCellSize)*cols+x/diffCellSize]
+				*cell = cell.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+
+	diff := computeruse.DiffResponse{
+		Changed: changed > 0,
+		Regions: []computeruse.Rectangle{},
+	}
+	if width > 0 && height > 0 {
+		diff.ChangedRatio = float64(changed) / float64(width*height)
+	}
+
+	// Flood fill the changed cells, diagonal neighbours included
+	visited := make([]bool, len(cells))
+	for start := range cells {
+		if cells[start].Empty() || visited[start] {
+			continue
+		}
+
+		region := image.Rectangle{}
+		visited[start] = true
+		queue := []int{start}
+		for len(queue) > 0 {
+			cell := queue[0]
+			queue = queue[1:]
+			region = region.Union(cells[cell])
+			col, row := cell%cols, cell/cols
+
+			for dy := -1; dy <= 1; dy++ {
+				for dx := -1; dx <= 1; dx++ {
+					c, r := col+dx, row+dy
+					if c < 0 || c >= cols || r < 0 || r >= rows {
+						continue
+					}
+					neighbour := r*cols + c
+					if !cells[neighbour].Empty() && !visited[neighbour] {
+						visited[neighbour] = true
+						queue = append(queue, neighbour)
+					}
+				}
+			}
+		}
+
+		diff.Regions = append(diff.Regions, computeruse.Rectangle{
+			Position: computeruse.Position{X: region.Min.X, Y: region.Min.Y},
+			Size:     computeruse.Size{Width: region.Dx(), Height: region.Dy()},
+		})
+	}
+
+	return diff
+}
+
+func channelDiff(a, b uint8) int {
+	if a > b {
+		return int(a - b)
+	}
+	return int(b - a)
+}
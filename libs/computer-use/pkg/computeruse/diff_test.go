@@ -0,0 +1,155 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package computeruse
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/daytonaio/daemon/pkg/toolbox/computeruse"
+	"github.com/stretchr/testify/require"
+)
+
+func filledImage(width, height int, c color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func region(x, y, width, height int) computeruse.Rectangle {
+	return computeruse.Rectangle{
+		Position: computeruse.Position{X: x, Y: y},
+		Size:     computeruse.Size{Width: width, Height: height},
+	}
+}
+
+func TestDiffImages(t *testing.T) {
+	gray := color.RGBA{R: 100, G: 100, B: 100, A: 255}
+
+	tests := []struct {
+		name      string
+		width     int
+		height    int
+		changes   []image.Point
+		color     color.RGBA
+		tolerance int
+		want      computeruse.DiffResponse
+	}{
+		{
+			name:   "identical",
+			width:  32,
+			height: 32,
+			want:   computeruse.DiffResponse{Regions: []computeruse.Rectangle{}},
+		},
+		{
+			name:      "within tolerance",
+			width:     32,
+			height:    32,
+			changes:   []image.Point{{X: 3, Y: 3}},
+			color:     color.RGBA{R: 110, G: 90, B: 100, A: 255},
+			tolerance: 10,
+			want:      computeruse.DiffResponse{Regions: []computeruse.Rectangle{}},
+		},
+		{
+			name:      "above tolerance",
+			width:     32,
+			height:    32,
+			changes:   []image.Point{{X: 3, Y: 3}},
+			color:     color.RGBA{R: 100, G: 100, B: 111, A: 255},
+			tolerance: 10,
+			want: computeruse.DiffResponse{
+				Changed:      true,
+				ChangedRatio: 1.0 / 1024,
+				Regions:      []computeruse.Rectangle{region(3, 3, 1, 1)},
+			},
+		},
+		{
+			name:    "alpha is ignored",
+			width:   32,
+			height:  32,
+			changes: []image.Point{{X: 3, Y: 3}},
+			color:   color.RGBA{R: 100, G: 100, B: 100, A: 0},
+			want:    computeruse.DiffResponse{Regions: []computeruse.Rectangle{}},
+		},
+		{
+			name:    "changed ratio",
+			width:   10,
+			height:  10,
+			changes: []image.Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}, {X: 3, Y: 0}, {X: 4, Y: 0}},
+			color:   color.RGBA{A: 255},
+			want: computeruse.DiffResponse{
+				Changed:      true,
+				ChangedRatio: 0.05,
+				Regions:      []computeruse.Rectangle{region(0, 0, 5, 1)},
+			},
+		},
+		{
+			name:    "neighbouring cells merge into one box",
+			width:   64,
+			height:  64,
+			changes: []image.Point{{X: 10, Y: 10}, {X: 20, Y: 12}, {X: 33, Y: 30}},
+			color:   color.RGBA{A: 255},
+			want: computeruse.DiffResponse{
+				Changed:      true,
+				ChangedRatio: 3.0 / 4096,
+				Regions:      []computeruse.Rectangle{region(10, 10, 24, 21)},
+			},
+		},
+		{
+			name:    "distant cells stay separate",
+			width:   64,
+			height:  64,
+			changes: []image.Point{{X: 2, Y: 2}, {X: 50, Y: 60}},
+			color:   color.RGBA{A: 255},
+			want: computeruse.DiffResponse{
+				Changed:      true,
+				ChangedRatio: 2.0 / 4096,
+				Regions:      []computeruse.Rectangle{region(2, 2, 1, 1), region(50, 60, 1, 1)},
+			},
+		},
+		{
+			name:    "partial cells at the edges",
+			width:   17,
+			height:  17,
+			changes: []image.Point{{X: 16, Y: 16}},
+			color:   color.RGBA{A: 255},
+			want: computeruse.DiffResponse{
+				Changed:      true,
+				ChangedRatio: 1.0 / 289,
+				Regions:      []computeruse.Rectangle{region(16, 16, 1, 1)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := filledImage(tt.width, tt.height, gray)
+			after := filledImage(tt.width, tt.height, gray)
+			for _, p := range tt.changes {
+				after.SetRGBA(p.X, p.Y, tt.color)
+			}
+
+			diff := diffImages(before, after, tt.tolerance)
+			require.Equal(t, tt.want.Changed, diff.Changed)
+			require.InDelta(t, tt.want.ChangedRatio, diff.ChangedRatio, 1e-9)
+			require.Equal(t, tt.want.Regions, diff.Regions)
+		})
+	}
+}
+
+func TestDiffImages_SubImages(t *testing.T) {
+	before := filledImage(32, 32, color.RGBA{A: 255})
+	after := filledImage(32, 32, color.RGBA{A: 255})
+	after.SetRGBA(20, 20, color.RGBA{R: 255, A: 255})
+
+	rect := image.Rect(16, 16, 32, 32)
+	diff := diffImages(before.SubImage(rect).(*image.RGBA), after.SubImage(rect).(*image.RGBA), 0)
+	require.True(t, diff.Changed)
+	require.Equal(t, []computeruse.Rectangle{region(4, 4, 1, 1)}, diff.Regions)
+}
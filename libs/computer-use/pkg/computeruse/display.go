@@ -60,29 +60,47 @@ func encodeImageWithCompression(img *image.RGBA, params ImageCompressionParams)
 }
 
 // scaleImage scales an image by the given factor
-func scaleImage(img *image.RGBA, scale float64) image.Image {
+func scaleImage(img *image.RGBA, scale float64) *image.RGBA {
 	if scale == 1.0 {
 		return img
 	}
 
-	// Simple nearest neighbor scaling
 	oldBounds := img.Bounds()
 	newWidth := int(float64(oldBounds.Dx()) * scale)
 	newHeight := int(float64(oldBounds.Dy()) * scale)
 
-	scaledImg := image.NewRGBA(image.Rect(0, 0, newWidth, newHeight))
+	return resizeImage(img, newWidth, newHeight)
+}
+
+// resizeImage resizes an image to the given dimensions
+func resizeImage(img *image.RGBA, width, height int) *image.RGBA {
+	// Simple nearest neighbor scaling
+	oldBounds := img.Bounds()
+	resizedImg := image.NewRGBA(image.Rect(0, 0, width, height))
 
-	for y := 0; y < newHeight; y++ {
-		for x := 0; x < newWidth; x++ {
-			oldX := int(float64(x) / scale)
-			oldY := int(float64(y) / scale)
-			if oldX < oldBounds.Dx() && oldY < oldBounds.Dy() {
-				scaledImg.Set(x, y, img.At(oldX, oldY))
-			}
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			oldX := oldBounds.Min.X + x*oldBounds.Dx()/width
+			oldY := oldBounds.Min.Y + y*oldBounds.Dy()/height
+			resizedImg.SetRGBA(x, y, img.RGBAAt(oldX, oldY))
 		}
 	}
 
-	return scaledImg
+	return resizedImg
+}
+
+// captureRGBA captures a region of the screen into an RGBA image that can be
+// drawn on, compared and encoded
+func captureRGBA(rect image.Rectangle) (*image.RGBA, error) {
+	img, err := screenshot.CaptureRect(rect)
+	if err != nil {
+		return nil, err
+	}
+
+	rgbaImg := image.NewRGBA(image.Rect(0, 0, img.Bounds().Dx(), img.Bounds().Dy()))
+	draw.Draw(rgbaImg, rgbaImg.Bounds(), img, img.Bounds().Min, draw.Src)
+
+	return rgbaImg, nil
 }
 
 func (u *ComputerUse) TakeCompressedScreenshot(req *computeruse.CompressedScreenshotRequest) (*computeruse.ScreenshotResponse, error) {
@@ -93,15 +111,11 @@ func (u *ComputerUse) TakeCompressedScreenshot(req *computeruse.CompressedScreen
 	}
 
 	bounds := screenshot.GetDisplayBounds(0)
-	img, err := screenshot.CaptureRect(bounds)
+	rgbaImg, err := captureRGBA(bounds)
 	if err != nil {
 		return nil, err
 	}
 
-	// Convert to RGBA for drawing
-	rgbaImg := image.NewRGBA(img.Bounds())
-	draw.Draw(rgbaImg, rgbaImg.Bounds(), img, image.Point{}, draw.Src)
-
 	// Draw cursor if requested
 	mouseX, mouseY := 0, 0
 	if req.ShowCursor {
@@ -141,15 +155,11 @@ func (u *ComputerUse) TakeCompressedRegionScreenshot(req *computeruse.Compressed
 	}
 
 	rect := image.Rect(req.X, req.Y, req.X+req.Width, req.Y+req.Height)
-	img, err := screenshot.CaptureRect(rect)
+	rgbaImg, err := captureRGBA(rect)
 	if err != nil {
 		return nil, err
 	}
 
-	// Convert to RGBA for drawing
-	rgbaImg := image.NewRGBA(img.Bounds())
-	draw.Draw(rgbaImg, rgbaImg.Bounds(), img, image.Point{}, draw.Src)
-
 	// Draw cursor if requested and it's within the region
 	mouseX, mouseY := 0, 0
 	if req.ShowCursor {
@@ -211,34 +221,14 @@ func (u *ComputerUse) GetDisplayInfo() (*computeruse.DisplayInfoResponse, error)
 }
 
 func (u *ComputerUse) GetWindows() (*computeruse.WindowsResponse, error) {
-	// This is a simplified version - robotgo's window functions
-	// might need additional setup depending on the platform
-
-	titles, err := robotgo.FindIds("")
+	entries, err := listWindows()
 	if err != nil {
 		return nil, err
 	}
 
-	windows := make([]computeruse.WindowInfo, 0)
-	for _, id := range titles {
-		title := robotgo.GetTitle(id)
-		if title != "" {
-			// Get window position and size (this might need platform-specific implementation)
-			// For now, we'll use placeholder values
-			windows = append(windows, computeruse.WindowInfo{
-				ID:    id,
-				Title: title,
-				Position: computeruse.Position{
-					X: 0, // Would need platform-specific implementation
-					Y: 0, // Would need platform-specific implementation
-				},
-				Size: computeruse.Size{
-					Width:  0, // Would need platform-specific implementation
-					Height: 0, // Would need platform-specific implementation
-				},
-				IsActive: false, // Would need platform-specific implementation
-			})
-		}
+	windows := make([]computeruse.WindowInfo, 0, len(entries))
+	for _, entry := range entries {
+		windows = append(windows, entry.WindowInfo)
 	}
 
 	return &computeruse.WindowsResponse{
@@ -0,0 +1,82 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package computeruse
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// gradientImage returns an image whose pixels encode their own coordinates
+func gradientImage(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetRGBA(x, y, color.RGBA{R: uint8(x), G: uint8(y), A: 255})
+		}
+	}
+	return img
+}
+
+func TestResizeImage(t *testing.T) {
+	tests := []struct {
+		name   string
+		width  int
+		height int
+		// Source coordinates sampled by every row and column
+		cols []int
+		rows []int
+	}{
+		{name: "same size", width: 4, height: 4, cols: []int{0, 1, 2, 3}, rows: []int{0, 1, 2, 3}},
+		{name: "odd downscale", width: 3, height: 1, cols: []int{0, 1, 2}, rows: []int{0}},
+		{name: "odd upscale", width: 5, height: 7, cols: []int{0, 0, 1, 2, 3}, rows: []int{0, 0, 1, 1, 2, 2, 3}},
+		{name: "single pixel", width: 1, height: 1, cols: []int{0}, rows: []int{0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resized := resizeImage(gradientImage(4, 4), tt.width, tt.height)
+			require.Equal(t, image.Rect(0, 0, tt.width, tt.height), resized.Bounds())
+
+			for y, row := range tt.rows {
+				for x, col := range tt.cols {
+					require.Equal(t, color.RGBA{R: uint8(col), G: uint8(row), A: 255}, resized.RGBAAt(x, y), "pixel %d,%d", x, y)
+				}
+			}
+		})
+	}
+}
+
+func TestScaleImage(t *testing.T) {
+	tests := []struct {
+		name  string
+		scale float64
+		want  image.Rectangle
+	}{
+		{name: "unscaled", scale: 1, want: image.Rect(0, 0, 7, 5)},
+		{name: "half", scale: 0.5, want: image.Rect(0, 0, 3, 2)},
+		{name: "third", scale: 1.0 / 3, want: image.Rect(0, 0, 2, 1)},
+		{name: "double", scale: 2, want: image.Rect(0, 0, 14, 10)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img := gradientImage(7, 5)
+			scaled := scaleImage(img, tt.scale)
+			require.Equal(t, tt.want, scaled.Bounds())
+			require.Equal(t, img.RGBAAt(0, 0), scaled.RGBAAt(0, 0))
+		})
+	}
+}
+
+func TestResizeImage_SubImage(t *testing.T) {
+	img := gradientImage(8, 8).SubImage(image.Rect(4, 4, 8, 8)).(*image.RGBA)
+
+	resized := resizeImage(img, 2, 2)
+	require.Equal(t, color.RGBA{R: 4, G: 4, A: 255}, resized.RGBAAt(0, 0))
+	require.Equal(t, color.RGBA{R: 6, G: 6, A: 255}, resized.RGBAAt(1, 1))
+}
@@ -0,0 +1,499 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package computeruse
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/daytonaio/daemon/pkg/toolbox/computeruse"
+	"github.com/go-vgo/robotgo"
+	"github.com/kbinani/screenshot"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	RecordingFormatMP4   = "mp4"
+	RecordingFormatMJPEG = "mjpeg"
+
+	RecordingStatusRecording = "recording"
+	RecordingStatusCompleted = "completed"
+	RecordingStatusFailed    = "failed"
+
+	defaultRecordingFPS = 10
+	maxRecordingFPS     = 60
+	// Motion JPEG stores every frame as a full image, which grows by several
+	// megabytes per second
+	defaultMJPEGMaxDuration = 10 * time.Minute
+	// Time ffmpeg is given to finalize the video after being asked to quit
+	recordingStopTimeout = 10 * time.Second
+)
+
+var recordingIdPattern = regexp.MustCompile(`^[0-9]{8}-[0-9]{6}-[0-9a-f]{8}$`)
+
+type screenRecorder struct {
+	mu        sync.Mutex
+	active    *screenRecording
+	recording sync.WaitGroup
+}
+
+type screenRecording struct {
+	info computeruse.ScreenRecording
+	// Closed to ask the capture to stop
+	stop     chan struct{}
+	stopOnce sync.Once
+	// Closed once the video file is complete
+	done chan struct{}
+}
+
+// requestStop asks the capture to stop, it can be called any number of times
+// and concurrently
+func (r *screenRecording) requestStop() {
+	r.stopOnce.Do(func() {
+		close(r.stop)
+	})
+}
+
+func (u *ComputerUse) recordingsDir() (string, error) {
+	configDir := u.configDir
+	if configDir == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home directory: %v", err)
+		}
+		configDir = filepath.Join(homeDir, ".daytona", "computeruse")
+	}
+
+	dir := filepath.Join(configDir, "recordings")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create recordings directory: %v", err)
+	}
+	return dir, nil
+}
+
+func (u *ComputerUse) StartScreenRecording(req *computeruse.StartScreenRecordingRequest) (*computeruse.ScreenRecording, error) {
+	u.recorder.mu.Lock()
+	defer u.recorder.mu.Unlock()
+
+	if u.recorder.active != nil {
+		return nil, fmt.Errorf("recording %s is already in progress", u.recorder.active.info.ID)
+	}
+
+	fps := req.FPS
+	if fps <= 0 {
+		fps = defaultRecordingFPS
+	}
+	if fps > maxRecordingFPS {
+		return nil, fmt.Errorf("fps must be at most %d", maxRecordingFPS)
+	}
+
+	display := screenshot.GetDisplayBounds(0)
+	region := display
+	if req.Width > 0 && req.Height > 0 {
+		region = image.Rect(req.X, req.Y, req.X+req.Width, req.Y+req.Height)
+		if !region.In(display) {
+			return nil, fmt.Errorf("region %v is outside of the display %v", region, display)
+		}
+	}
+	// Most encoders require even dimensions
+	region.Max.X -= region.Dx() % 2
+	region.Max.Y -= region.Dy() % 2
+	if region.Empty() {
+		return nil, errors.New("region is empty")
+	}
+
+	_, ffmpegErr := exec.LookPath("ffmpeg")
+	format := req.Format
+	switch format {
+	case "":
+		format = RecordingFormatMJPEG
+		if ffmpegErr == nil {
+			format = RecordingFormatMP4
+		}
+	case RecordingFormatMP4:
+		if ffmpegErr != nil {
+			return nil, fmt.Errorf("mp4 recording requires ffmpeg: %v", ffmpegErr)
+		}
+	case RecordingFormatMJPEG:
+	default:
+		return nil, fmt.Errorf("unsupported recording format %q, expected %s or %s", format, RecordingFormatMP4, RecordingFormatMJPEG)
+	}
+
+	if req.MaxDuration < 0 {
+		return nil, errors.New("maxDuration must not be negative")
+	}
+	maxDuration := time.Duration(req.MaxDuration) * time.Second
+	if maxDuration == 0 && format == RecordingFormatMJPEG {
+		maxDuration = defaultMJPEGMaxDuration
+	}
+
+	dir, err := u.recordingsDir()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	id := fmt.Sprintf("%s-%08x", now.UTC().Format("20060102-150405"), now.UnixNano()&0xffffffff)
+
+	recording := &screenRecording{
+		info: computeruse.ScreenRecording{
+			ID:     id,
+			Format: format,
+			Position: computeruse.Position{
+				X: region.Min.X,
+				Y: region.Min.Y,
+			},
+			Size: computeruse.Size{
+				Width:  region.Dx(),
+				Height: region.Dy(),
+			},
+			FPS:         fps,
+			ShowCursor:  req.ShowCursor,
+			MaxDuration: int(maxDuration / time.Second),
+			Status:      RecordingStatusRecording,
+			StartedAt:   now,
+			Path:        recordingPath(dir, id, format),
+		},
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	var capture func(*screenRecording) error
+	if format == RecordingFormatMP4 {
+		capture, err = u.startFFmpegRecording(recording)
+	} else {
+		capture, err = u.startScreenshotRecording(recording)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	u.recorder.active = recording
+	u.recorder.recording.Add(1)
+	go func() {
+		defer u.recorder.recording.Done()
+		err := capture(recording)
+		u.finishScreenRecording(recording, err)
+	}()
+
+	log.Infof("Started %s screen recording %s", format, id)
+
+	info := recording.info
+	return &info, nil
+}
+
+func (u *ComputerUse) StopScreenRecording(req *computeruse.ScreenRecordingRequest) (*computeruse.ScreenRecording, error) {
+	u.recorder.mu.Lock()
+	recording := u.recorder.active
+	u.recorder.mu.Unlock()
+
+	if recording == nil || (req.ID != "" && req.ID != recording.info.ID) {
+		if req.ID != "" {
+			if info, err := u.GetScreenRecording(req); err == nil {
+				return info, nil
+			}
+		}
+		return nil, errors.New("no recording in progress")
+	}
+
+	recording.requestStop()
+	<-recording.done
+
+	return u.GetScreenRecording(&computeruse.ScreenRecordingRequest{ID: recording.info.ID})
+}
+
+func (u *ComputerUse) ListScreenRecordings() (*computeruse.ScreenRecordingsResponse, error) {
+	dir, err := u.recordingsDir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	recordings := []computeruse.ScreenRecording{}
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || !recordingIdPattern.MatchString(id) {
+			continue
+		}
+		info, err := u.GetScreenRecording(&computeruse.ScreenRecordingRequest{ID: id})
+		if err != nil {
+			log.Warnf("Skipping screen recording %s: %v", id, err)
+			continue
+		}
+		recordings = append(recordings, *info)
+	}
+
+	u.recorder.mu.Lock()
+	if active := u.recorder.active; active != nil {
+		recordings = append(recordings, active.info)
+	}
+	u.recorder.mu.Unlock()
+
+	sort.Slice(recordings, func(i, j int) bool {
+		return recordings[i].StartedAt.After(recordings[j].StartedAt)
+	})
+
+	return &computeruse.ScreenRecordingsResponse{
+		Recordings: recordings,
+	}, nil
+}
+
+func (u *ComputerUse) GetScreenRecording(req *computeruse.ScreenRecordingRequest) (*computeruse.ScreenRecording, error) {
+	if !recordingIdPattern.MatchString(req.ID) {
+		return nil, fmt.Errorf("recording %s not found", req.ID)
+	}
+
+	u.recorder.mu.Lock()
+	if active := u.recorder.active; active != nil && active.info.ID == req.ID {
+		info := active.info
+		u.recorder.mu.Unlock()
+		if stat, err := os.Stat(info.Path); err == nil {
+			info.SizeBytes = stat.Size()
+		}
+		return &info, nil
+	}
+	u.recorder.mu.Unlock()
+
+	dir, err := u.recordingsDir()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, req.ID+".json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("recording %s not found", req.ID)
+		}
+		return nil, err
+	}
+
+	var info computeruse.ScreenRecording
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("invalid metadata of recording %s: %v", req.ID, err)
+	}
+	if info.Format != RecordingFormatMP4 && info.Format != RecordingFormatMJPEG {
+		return nil, fmt.Errorf("invalid metadata of recording %s: unsupported format %q", req.ID, info.Format)
+	}
+
+	// The metadata file can be edited, the video file is always the one of the
+	// requested recording
+	info.ID = req.ID
+	info.Path = recordingPath(dir, req.ID, info.Format)
+	return &info, nil
+}
+
+func recordingPath(dir, id, format string) string {
+	return filepath.Join(dir, id+"."+format)
+}
+
+// maxDurationTimer returns a channel receiving once the maximum duration of the
+// recording is reached, or nil when it has no limit.
+func maxDurationTimer(info computeruse.ScreenRecording) (<-chan time.Time, func() bool) {
+	if info.MaxDuration <= 0 {
+		return nil, func() bool { return false }
+	}
+	timer := time.NewTimer(time.Duration(info.MaxDuration) * time.Second)
+	return timer.C, timer.Stop
+}
+
+// stopScreenRecordings stops the active recording and waits for its file to
+// be complete, it is called when the desktop processes are stopped.
+func (u *ComputerUse) stopScreenRecordings() {
+	u.recorder.mu.Lock()
+	recording := u.recorder.active
+	u.recorder.mu.Unlock()
+
+	if recording != nil {
+		recording.requestStop()
+	}
+	u.recorder.recording.Wait()
+}
+
+func (u *ComputerUse) finishScreenRecording(recording *screenRecording, err error) {
+	endedAt := time.Now()
+
+	u.recorder.mu.Lock()
+	recording.info.EndedAt = &endedAt
+	recording.info.Status = RecordingStatusCompleted
+	if err != nil {
+		recording.info.Status = RecordingStatusFailed
+		recording.info.Error = err.Error()
+		log.Errorf("Screen recording %s failed: %v", recording.info.ID, err)
+	} else {
+		log.Infof("Screen recording %s completed", recording.info.ID)
+	}
+	if stat, statErr := os.Stat(recording.info.Path); statErr == nil {
+		recording.info.SizeBytes = stat.Size()
+	}
+	info := recording.info
+	u.recorder.mu.Unlock()
+
+	if metadataErr := writeRecordingMetadata(info); metadataErr != nil {
+		log.Errorf("Failed to save metadata of screen recording %s: %v", info.ID, metadataErr)
+	}
+
+	u.recorder.mu.Lock()
+	if u.recorder.active == recording {
+		u.recorder.active = nil
+	}
+	u.recorder.mu.Unlock()
+
+	close(recording.done)
+}
+
+func writeRecordingMetadata(info computeruse.ScreenRecording) error {
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := strings.TrimSuffix(info.Path, filepath.Ext(info.Path)) + ".json"
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// startFFmpegRecording captures the display with ffmpeg's x11grab device and
+// encodes it to H.264.
+func (u *ComputerUse) startFFmpegRecording(recording *screenRecording) (func(*screenRecording) error, error) {
+	info := recording.info
+
+	drawMouse := "0"
+	if info.ShowCursor {
+		drawMouse = "1"
+	}
+
+	cmd := exec.Command("ffmpeg",
+		"-hide_banner", "-loglevel", "error", "-y",
+		"-f", "x11grab",
+		"-framerate", strconv.Itoa(info.FPS),
+		"-video_size", fmt.Sprintf("%dx%d", info.Width, info.Height),
+		"-draw_mouse", drawMouse,
+		"-i", fmt.Sprintf("%s+%d,%d", displayName(), info.X, info.Y),
+		"-c:v", "libx264",
+		"-preset", "ultrafast",
+		"-pix_fmt", "yuv420p",
+		"-movflags", "+faststart",
+		info.Path,
+	)
+
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start ffmpeg: %v", err)
+	}
+
+	return func(recording *screenRecording) error {
+		exited := make(chan error, 1)
+		go func() {
+			exited <- cmd.Wait()
+		}()
+
+		maxDurationReached, stopTimer := maxDurationTimer(info)
+		defer stopTimer()
+
+		select {
+		case err := <-exited:
+			return fmt.Errorf("ffmpeg exited: %v: %s", err, strings.TrimSpace(stderr.String()))
+		case <-recording.stop:
+		case <-maxDurationReached:
+			log.Infof("Screen recording %s reached its maximum duration", info.ID)
+		}
+
+		// Asking ffmpeg to quit lets it write the index of the video
+		_, _ = io.WriteString(stdin, "q")
+		_ = stdin.Close()
+
+		select {
+		case err := <-exited:
+			if err != nil {
+				return fmt.Errorf("ffmpeg failed: %v: %s", err, strings.TrimSpace(stderr.String()))
+			}
+			return nil
+		case <-time.After(recordingStopTimeout):
+			_ = cmd.Process.Kill()
+			<-exited
+			return errors.New("ffmpeg did not finish the video in time")
+		}
+	}, nil
+}
+
+// startScreenshotRecording is the fallback when ffmpeg is not installed. It
+// captures the region at the requested rate and appends every frame to a
+// Motion JPEG stream, which ffmpeg and most players can read with the frame
+// rate given in the metadata.
+func (u *ComputerUse) startScreenshotRecording(recording *screenRecording) (func(*screenRecording) error, error) {
+	file, err := os.Create(recording.info.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recording file: %v", err)
+	}
+
+	info := recording.info
+	region := image.Rect(info.X, info.Y, info.X+info.Width, info.Y+info.Height)
+
+	return func(recording *screenRecording) error {
+		defer file.Close()
+
+		ticker := time.NewTicker(time.Second / time.Duration(info.FPS))
+		defer ticker.Stop()
+
+		maxDurationReached, stopTimer := maxDurationTimer(info)
+		defer stopTimer()
+
+		frame := image.NewRGBA(image.Rect(0, 0, region.Dx(), region.Dy()))
+		for {
+			img, err := screenshot.CaptureRect(region)
+			if err != nil {
+				return fmt.Errorf("failed to capture frame: %v", err)
+			}
+			draw.Draw(frame, frame.Bounds(), img, img.Bounds().Min, draw.Src)
+
+			if info.ShowCursor {
+				mouseX, mouseY := robotgo.Location()
+				if image.Pt(mouseX, mouseY).In(region) {
+					drawCursor(frame, mouseX-region.Min.X, mouseY-region.Min.Y)
+				}
+			}
+
+			if err := jpeg.Encode(file, frame, &jpeg.Options{Quality: 80}); err != nil {
+				return fmt.Errorf("failed to write frame: %v", err)
+			}
+
+			select {
+			case <-recording.stop:
+				return file.Sync()
+			case <-maxDurationReached:
+				log.Infof("Screen recording %s reached its maximum duration", info.ID)
+				return file.Sync()
+			case <-ticker.C:
+			}
+		}
+	}, nil
+}
@@ -0,0 +1,301 @@
+// Copyright 2025 Daytona Platforms Inc.
+// SPDX-License-Identifier: AGPL-3.0
+
+package computeruse
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/daytonaio/daemon/pkg/toolbox/computeruse"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// Time given to the X11 command line tools to talk to the window manager
+	windowCommandTimeout = 5 * time.Second
+
+	defaultLaunchTimeout = 10 * time.Second
+	maxLaunchTimeout     = 2 * time.Minute
+	launchPollInterval   = 200 * time.Millisecond
+)
+
+// Matches the lines printed by wmctrl -lpG: id, desktop, pid, x, y, width,
+// height, host and title
+var wmctrlWindowPattern = regexp.MustCompile(`^(0x[0-9a-fA-F]+)\s+(-?\d+)\s+(\d+)\s+(-?\d+)\s+(-?\d+)\s+(\d+)\s+(\d+)\s+(\S+)\s?(.*)$`)
+
+// displayName returns the X display the plugin and the applications it starts
+// are attached to
+func displayName() string {
+	if display := os.Getenv("DISPLAY"); display != "" {
+		return display
+	}
+	return ":0"
+}
+
+// runX11Command runs one of the X11 command line tools against the plugin's
+// display and returns its standard output
+func runX11Command(stdin []byte, name string, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), windowCommandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Env = append(os.Environ(), "DISPLAY="+displayName())
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, fmt.Errorf("%s failed: %s", name, message)
+		}
+		return nil, fmt.Errorf("%s failed: %v", name, err)
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// windowId formats a window id the way wmctrl and xdotool expect it
+func windowId(id int) string {
+	return fmt.Sprintf("0x%08x", id)
+}
+
+type windowEntry struct {
+	computeruse.WindowInfo
+	pid int
+}
+
+// listWindows returns the windows managed by the window manager
+func listWindows() ([]windowEntry, error) {
+	output, err := runX11Command(nil, "wmctrl", "-lpG")
+	if err != nil {
+		return nil, err
+	}
+
+	activeId := 0
+	if active, err := runX11Command(nil, "xdotool", "getactivewindow"); err == nil {
+		activeId, _ = strconv.Atoi(strings.TrimSpace(string(active)))
+	}
+
+	windows := make([]windowEntry, 0)
+	for _, line := range strings.Split(string(output), "\n") {
+		match := wmctrlWindowPattern.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			continue
+		}
+
+		id, err := strconv.ParseInt(match[1], 0, 64)
+		if err != nil {
+			continue
+		}
+		pid, _ := strconv.Atoi(match[3])
+		x, _ := strconv.Atoi(match[4])
+		y, _ := strconv.Atoi(match[5])
+		width, _ := strconv.Atoi(match[6])
+		height, _ := strconv.Atoi(match[7])
+
+		windows = append(windows, windowEntry{
+			WindowInfo: computeruse.WindowInfo{
+				ID:    int(id),
+				Title: match[9],
+				Position: computeruse.Position{
+					X: x,
+					Y: y,
+				},
+				Size: computeruse.Size{
+					Width:  width,
+					Height: height,
+				},
+				IsActive: int(id) == activeId,
+			},
+			pid: pid,
+		})
+	}
+
+	return windows, nil
+}
+
+func getWindow(id int) (*computeruse.WindowInfo, error) {
+	windows, err := listWindows()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, window := range windows {
+		if window.ID == id {
+			return &window.WindowInfo, nil
+		}
+	}
+
+	return nil, fmt.Errorf("window %d not found", id)
+}
+
+// windowOperation runs the commands against an existing window and returns
+// the window's state afterwards
+func windowOperation(id int, commands ...[]string) (*computeruse.WindowInfo, error) {
+	if _, err := getWindow(id); err != nil {
+		return nil, err
+	}
+
+	for _, command := range commands {
+		if _, err := runX11Command(nil, command[0], command[1:]...); err != nil {
+			return nil, err
+		}
+	}
+
+	return getWindow(id)
+}
+
+func (u *ComputerUse) FocusWindow(req *computeruse.WindowRequest) (*computeruse.WindowInfo, error) {
+	// windowactivate also switches desktops and restores minimized windows
+	return windowOperation(req.ID,
+		[]string{"xdotool", "windowactivate", "--sync", windowId(req.ID)},
+	)
+}
+
+func (u *ComputerUse) MoveWindow(req *computeruse.MoveWindowRequest) (*computeruse.WindowInfo, error) {
+	// Window managers ignore geometry changes of maximized windows
+	return windowOperation(req.ID,
+		[]string{"wmctrl", "-i", "-r", windowId(req.ID), "-b", "remove,maximized_vert,maximized_horz"},
+		[]string{"wmctrl", "-i", "-r", windowId(req.ID), "-e", fmt.Sprintf("0,%d,%d,-1,-1", req.X, req.Y)},
+	)
+}
+
+func (u *ComputerUse) ResizeWindow(req *computeruse.ResizeWindowRequest) (*computeruse.WindowInfo, error) {
+	if req.Width <= 0 || req.Height <= 0 {
+		return nil, errors.New("width and height must be positive")
+	}
+
+	return windowOperation(req.ID,
+		[]string{"wmctrl", "-i", "-r", windowId(req.ID), "-b", "remove,maximized_vert,maximized_horz"},
+		[]string{"wmctrl", "-i", "-r", windowId(req.ID), "-e", fmt.Sprintf("0,-1,-1,%d,%d", req.Width, req.Height)},
+	)
+}
+
+func (u *ComputerUse) MinimizeWindow(req *computeruse.WindowRequest) (*computeruse.WindowInfo, error) {
+	return windowOperation(req.ID,
+		[]string{"xdotool", "windowminimize", windowId(req.ID)},
+	)
+}
+
+func (u *ComputerUse) MaximizeWindow(req *computeruse.WindowRequest) (*computeruse.WindowInfo, error) {
+	return windowOperation(req.ID,
+		[]string{"wmctrl", "-i", "-r", windowId(req.ID), "-b", "add,maximized_vert,maximized_horz"},
+	)
+}
+
+func (u *ComputerUse) CloseWindow(req *computeruse.WindowRequest) (*computeruse.Empty, error) {
+	if _, err := getWindow(req.ID); err != nil {
+		return nil, err
+	}
+
+	// Asks the application to close the window, like the close button does
+	if _, err := runX11Command(nil, "wmctrl", "-i", "-c", windowId(req.ID)); err != nil {
+		return nil, err
+	}
+
+	return new(computeruse.Empty), nil
+}
+
+// LaunchApplication starts a process on the plugin's display and waits for a
+// new window to be mapped. Windows owned by the process are preferred, but
+// applications that hand off to another process (e.g. a running browser
+// instance) are matched by the first window that appears.
+func (u *ComputerUse) LaunchApplication(req *computeruse.LaunchApplicationRequest) (*computeruse.LaunchApplicationResponse, error) {
+	if req.Command == "" {
+		return nil, errors.New("command is required")
+	}
+
+	timeout := defaultLaunchTimeout
+	if req.Timeout > 0 {
+		timeout = time.Duration(req.Timeout) * time.Millisecond
+	}
+	if timeout > maxLaunchTimeout {
+		return nil, fmt.Errorf("timeout must be at most %s", maxLaunchTimeout)
+	}
+
+	existing := make(map[int]bool)
+	if windows, err := listWindows(); err == nil {
+		for _, window := range windows {
+			existing[window.ID] = true
+		}
+	}
+
+	cmd := exec.Command(req.Command, req.Args...)
+	cmd.Dir = req.Cwd
+	cmd.Env = append(os.Environ(), "DISPLAY="+displayName())
+	for key, value := range req.Env {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", key, value))
+	}
+	// Keep the application running when the plugin's process group is signalled
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start %s: %v", req.Command, err)
+	}
+
+	pid := cmd.Process.Pid
+	log.Infof("Launched %s with pid %d", req.Command, pid)
+
+	exited := make(chan error, 1)
+	go func() {
+		exited <- cmd.Wait()
+	}()
+
+	response := &computeruse.LaunchApplicationResponse{
+		PID: pid,
+	}
+
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		select {
+		case err := <-exited:
+			// Launchers commonly exit successfully once they handed off
+			if err != nil {
+				return nil, fmt.Errorf("%s exited: %v", req.Command, err)
+			}
+			exited = nil
+		case <-time.After(launchPollInterval):
+		}
+
+		windows, err := listWindows()
+		if err != nil {
+			continue
+		}
+
+		var window *computeruse.WindowInfo
+		for _, w := range windows {
+			if existing[w.ID] {
+				continue
+			}
+			if w.pid == pid {
+				window = &w.WindowInfo
+				break
+			}
+			if window == nil {
+				window = &w.WindowInfo
+			}
+		}
+
+		if window != nil {
+			response.Window = window
+			return response, nil
+		}
+	}
+
+	log.Warnf("No window of %s was mapped within %s", req.Command, timeout)
+	return response, nil
+}